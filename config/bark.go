@@ -0,0 +1,68 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// Bark Bark推送通知
+type Bark struct {
+	// 包含Key的推送地址, 如：https://api.day.app/yourkey
+	BarkURL   string
+	BarkSound string
+	BarkGroup string
+	// 触发条件, 同 WebhookTrigger
+	BarkTrigger string
+}
+
+// barkNotifier Bark推送通知
+type barkNotifier struct {
+	Bark
+}
+
+// Notify 通过Bark推送更新结果
+func (b barkNotifier) Notify(ctx context.Context, results []DomainResult) error {
+	params := map[string]string{
+		"title": "ddns-go",
+		"body":  getNotifyText(results),
+	}
+	if b.BarkSound != "" {
+		params["sound"] = b.BarkSound
+	}
+	if b.BarkGroup != "" {
+		params["group"] = b.BarkGroup
+	}
+	byt, _ := json.Marshal(params)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(b.BarkURL, "/"), bytes.NewReader(byt))
+	if err != nil {
+		return b.hideURL(err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	var result struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	clt := util.CreateHTTPClient()
+	resp, err := clt.Do(req)
+	err = util.GetHTTPResponse(resp, err, &result)
+	if err != nil {
+		return b.hideURL(err)
+	}
+	if result.Code != http.StatusOK {
+		return errors.New(result.Message)
+	}
+	return nil
+}
+
+// hideURL 异常信息中可能包含地址中的Key
+func (b barkNotifier) hideURL(err error) error {
+	return errors.New(strings.ReplaceAll(err.Error(), strings.TrimSuffix(b.BarkURL, "/"), "***"))
+}
@@ -1,9 +1,15 @@
 package config
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
 	"io"
 	"log"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"regexp"
@@ -11,6 +17,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/jeessy2/ddns-go/v6/util"
 	passwordvalidator "github.com/wagslane/go-password-validator"
@@ -23,6 +30,9 @@ var Ipv4Reg = regexp.MustCompile(`((25[0-5]|(2[0-4]|1{0,1}[0-9]){0,1}[0-9])\.){3
 // Ipv6Reg IPv6正则
 var Ipv6Reg = regexp.MustCompile(`((([0-9A-Fa-f]{1,4}:){7}([0-9A-Fa-f]{1,4}|:))|(([0-9A-Fa-f]{1,4}:){6}(:[0-9A-Fa-f]{1,4}|((25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)(\.(25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)){3})|:))|(([0-9A-Fa-f]{1,4}:){5}(((:[0-9A-Fa-f]{1,4}){1,2})|:((25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)(\.(25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)){3})|:))|(([0-9A-Fa-f]{1,4}:){4}(((:[0-9A-Fa-f]{1,4}){1,3})|((:[0-9A-Fa-f]{1,4})?:((25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)(\.(25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)){3}))|:))|(([0-9A-Fa-f]{1,4}:){3}(((:[0-9A-Fa-f]{1,4}){1,4})|((:[0-9A-Fa-f]{1,4}){0,2}:((25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)(\.(25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)){3}))|:))|(([0-9A-Fa-f]{1,4}:){2}(((:[0-9A-Fa-f]{1,4}){1,5})|((:[0-9A-Fa-f]{1,4}){0,3}:((25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)(\.(25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)){3}))|:))|(([0-9A-Fa-f]{1,4}:){1}(((:[0-9A-Fa-f]{1,4}){1,6})|((:[0-9A-Fa-f]{1,4}){0,4}:((25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)(\.(25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)){3}))|:))|(:(((:[0-9A-Fa-f]{1,4}){1,7})|((:[0-9A-Fa-f]{1,4}){0,5}:((25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)(\.(25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)){3}))|:)))`)
 
+// 获取IP的命令的超时时间
+const cmdTimeout = 30 * time.Second
+
 // DnsConfig 配置
 type DnsConfig struct {
 	Name string
@@ -31,8 +41,12 @@ type DnsConfig struct {
 		// 获取IP类型 url/netInterface
 		GetType      string
 		URL          string
+		URLReg       string // 接口返回值的匹配正则表达式, 有捕获组时使用第一个
+		URLAuth      URLAuth
 		NetInterface string
 		Cmd          string
+		// 允许使用内网/回环/链路本地/CGNAT地址, 默认不允许
+		AllowPrivate bool
 		Domains      []string
 	}
 	Ipv6 struct {
@@ -40,13 +54,55 @@ type DnsConfig struct {
 		// 获取IP类型 url/netInterface
 		GetType      string
 		URL          string
+		URLReg       string // 接口返回值的匹配正则表达式, 有捕获组时使用第一个
+		URLAuth      URLAuth
 		NetInterface string
 		Cmd          string
 		Ipv6Reg      string // ipv6匹配正则表达式
-		Domains      []string
+		// 从网卡获取时优先使用临时地址, 默认优先使用稳定地址
+		PreferTemporary bool
+		// 接口ID(后缀), 如 ::1234, 填写后使用获得IPv6的/64前缀与其组合
+		Suffix  string
+		Domains []string
+	}
+	// CNAME记录, 每行为 域名 目标, 用空格分隔。如：cloudflare
+	Cname struct {
+		Domains []string
+	}
+	// MX记录, 每行为 域名 优先级 目标, 用空格分隔。如：cloudflare
+	Mx struct {
+		Domains []string
 	}
 	DNS DNS
 	TTL string
+	// 请求DNS服务商的超时时间(秒), 为空默认30秒
+	HTTPTimeout string
+	// 同步间隔时间(秒), 为空使用 -f 的值
+	Interval string
+	// DoH服务地址, 填写后先通过DoH查询当前记录, 与IP一致时不再请求DNS服务商
+	DohURL string
+	// 是否开启代理。如：cloudflare
+	Proxied bool
+	// 记录备注。如：cloudflare
+	Comment string
+	// 记录标签, 多个用英文逗号分隔。如：cloudflare
+	Tags string
+	// 是否删除重复的记录, 默认不删除。如：cloudflare,porkbun,hetzner,digitalocean
+	CleanDuplicates bool
+	// 只更新已存在的记录, 未找到时不新增并标记失败, 也可在域名后添加 ?updateOnly=true 单独开启
+	UpdateOnly bool
+	// 连续3次获取不到IP时删除A/AAAA记录, 再次获取到IP后重新新增。如：cloudflare,hetzner,digitalocean
+	DeleteOnNoIP bool
+	// 是否启用, 关闭后不再更新, 保留配置以便之后重新启用
+	Enabled bool
+}
+
+// URLAuth 通过接口获取IP时的请求头和Basic认证, 用于需要认证的接口(如路由器状态接口)
+type URLAuth struct {
+	// 请求头, 如 Authorization: Bearer xxx
+	Headers  map[string]string
+	Username string
+	Password string
 }
 
 // DNS DNS配置
@@ -55,14 +111,36 @@ type DNS struct {
 	Name   string
 	ID     string
 	Secret string
+	// ZoneID 区域ID, 填写后不再通过根域名查询。如：cloudflare,hetzner,route53,azure,googleclouddns
+	ZoneID string
+	// Endpoint 更新地址或接口地址。如：dyndns2,ovh
+	Endpoint string
+	// TenantID 租户ID。如：azure
+	TenantID string
+	// ConsumerKey 如：ovh
+	ConsumerKey string
+	// AuthMode 认证方式 token/global, 默认 token。global 时使用 ID 作为Email, Secret 作为 Global API Key。如：cloudflare
+	AuthMode string
 }
 
 type Config struct {
+	// 配置文件的版本, 见 ConfigVersion
+	Version int
 	DnsConf []DnsConfig
 	User
 	Webhook
+	Telegram
+	Bark
+	Discord
+	Slack
+	Email
+	Hook
 	// 禁止公网访问
 	NotAllowWanAccess bool
+	// 演练模式, 只记录将要进行的修改
+	DryRun bool
+	// 通知冷却时间(分钟), 通知后同一域名在此时间内的变化不再通知, 更新失败不受限制。为空不限制
+	NotifyCooldown string
 	// 语言
 	Lang string
 }
@@ -95,20 +173,41 @@ func GetConfigCached() (conf Config, err error) {
 		return *cache.ConfigSingle, err
 	}
 
+	updateConfigModTime()
 	byt, err := os.ReadFile(configFilePath)
 	if err != nil {
-		util.Log("异常信息: %s", err)
+		util.LogError("异常信息: %s", err)
 		cache.Err = err
 		return *cache.ConfigSingle, err
 	}
 
-	err = yaml.Unmarshal(byt, cache.ConfigSingle)
+	// 替换引用的环境变量, 如 ${CF_TOKEN}
+	var node yaml.Node
+	err = yaml.Unmarshal(byt, &node)
+	if err == nil && node.Kind != 0 {
+		envRefs = map[string]envRef{}
+		expandEnvNode(&node, "", envRefs)
+		err = node.Decode(cache.ConfigSingle)
+	}
 	if err != nil {
-		util.Log("异常信息: %s", err)
+		util.LogError("异常信息: %s", err)
 		cache.Err = err
 		return *cache.ConfigSingle, err
 	}
 
+	// 升级旧版本的配置, 如果之前密码不为空且不是bcrypt加密后的密码, 把密码加密
+	changed := cache.ConfigSingle.migrate(byt)
+	if cache.ConfigSingle.Password != "" && !util.IsHashedPassword(cache.ConfigSingle.Password) {
+		hashedPwd, err := util.HashPassword(cache.ConfigSingle.Password)
+		if err == nil {
+			cache.ConfigSingle.Password = hashedPwd
+			changed = true
+		}
+	}
+	if changed {
+		writeConfig(cache.ConfigSingle)
+	}
+
 	// 未填写登录信息, 确保不能从公网访问
 	if cache.ConfigSingle.Username == "" && cache.ConfigSingle.Password == "" {
 		cache.ConfigSingle.NotAllowWanAccess = true
@@ -119,56 +218,35 @@ func GetConfigCached() (conf Config, err error) {
 	return *cache.ConfigSingle, err
 }
 
-// CompatibleConfig 兼容之前的配置文件
-func (conf *Config) CompatibleConfig() {
-	// 如配置文件不为空, 兼容之前的语言为中文
-	if conf.Lang == "" {
-		conf.Lang = "zh"
-	}
-
-	// 如果之前密码不为空且不是bcrypt加密后的密码, 把密码加密并保存
-	if conf.Password != "" && !util.IsHashedPassword(conf.Password) {
-		hashedPwd, err := util.HashPassword(conf.Password)
-		if err == nil {
-			conf.Password = hashedPwd
-			conf.SaveConfig()
-		}
-	}
-
-	// 兼容v5.0.0之前的配置文件
-	if len(conf.DnsConf) > 0 {
-		return
-	}
+// SaveConfig 保存配置
+func (conf *Config) SaveConfig() (err error) {
+	cache.Lock.Lock()
+	defer cache.Lock.Unlock()
 
-	configFilePath := util.GetConfigFilePath()
-	_, err := os.Stat(configFilePath)
-	if err != nil {
-		return
-	}
-	byt, err := os.ReadFile(configFilePath)
+	err = writeConfig(conf)
 	if err != nil {
 		return
 	}
 
-	dnsConf := &DnsConfig{}
-	err = yaml.Unmarshal(byt, dnsConf)
-	if err != nil {
-		return
-	}
-	if len(dnsConf.DNS.Name) > 0 {
-		cache.Lock.Lock()
-		defer cache.Lock.Unlock()
-		conf.DnsConf = append(conf.DnsConf, *dnsConf)
-		cache.ConfigSingle = conf
-	}
+	// 清空配置缓存
+	cache.ConfigSingle = nil
+
+	return
 }
 
-// SaveConfig 保存配置
-func (conf *Config) SaveConfig() (err error) {
-	cache.Lock.Lock()
-	defer cache.Lock.Unlock()
+// writeConfig 写入配置文件, 需持有 cache.Lock
+func writeConfig(conf *Config) (err error) {
+	conf.Version = ConfigVersion
 
-	byt, err := yaml.Marshal(conf)
+	// 未修改的引用了环境变量的值, 仍保存为 ${NAME}
+	var node yaml.Node
+	err = node.Encode(conf)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	restoreEnvNode(&node, "", envRefs)
+	byt, err := yaml.Marshal(&node)
 	if err != nil {
 		log.Println(err)
 		return err
@@ -181,11 +259,8 @@ func (conf *Config) SaveConfig() (err error) {
 		return
 	}
 
+	updateConfigModTime()
 	util.Log("配置文件已保存在: %s", configFilePath)
-
-	// 清空配置缓存
-	cache.ConfigSingle = nil
-
 	return
 }
 
@@ -197,7 +272,7 @@ func (conf *Config) ResetPassword(newPassword string) {
 	// 先检查密码是否安全
 	hashedPwd, err := conf.CheckPassword(newPassword)
 	if err != nil {
-		util.Log(err.Error())
+		util.LogError(err.Error())
 		return
 	}
 
@@ -226,10 +301,37 @@ func (conf *Config) CheckPassword(newPassword string) (hashedPwd string, err err
 	return
 }
 
+// GetHTTPTimeout 获得请求DNS服务商的超时时间, 默认30秒
+func (conf *DnsConfig) GetHTTPTimeout() time.Duration {
+	seconds, err := strconv.Atoi(conf.HTTPTimeout)
+	if err != nil || seconds <= 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// GetNotifyCooldown 获得通知冷却时间, 未设置时返回0
+func (conf *Config) GetNotifyCooldown() time.Duration {
+	minutes, err := strconv.Atoi(conf.NotifyCooldown)
+	if err != nil || minutes <= 0 {
+		return 0
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// GetInterval 获得同步间隔时间, 未设置时返回 defaultInterval
+func (conf *DnsConfig) GetInterval(defaultInterval time.Duration) time.Duration {
+	seconds, err := strconv.Atoi(conf.Interval)
+	if err != nil || seconds <= 0 {
+		return defaultInterval
+	}
+	return time.Duration(seconds) * time.Second
+}
+
 func (conf *DnsConfig) getIpv4AddrFromInterface() string {
 	ipv4, _, err := GetNetInterface()
 	if err != nil {
-		util.Log("从网卡获得IPv4失败")
+		util.LogError("从网卡获得IPv4失败")
 		return ""
 	}
 
@@ -239,37 +341,124 @@ func (conf *DnsConfig) getIpv4AddrFromInterface() string {
 		}
 	}
 
-	util.Log("从网卡中获得IPv4失败! 网卡名: %s", conf.Ipv4.NetInterface)
+	util.LogError("从网卡中获得IPv4失败! 网卡名: %s", conf.Ipv4.NetInterface)
 	return ""
 }
 
 func (conf *DnsConfig) getIpv4AddrFromUrl() string {
-	client := util.CreateNoProxyHTTPClient("tcp4")
-	urls := strings.Split(conf.Ipv4.URL, ",")
-	for _, url := range urls {
+	return getAddrFromUrls(util.CreateNoProxyHTTPClient("tcp4"), conf.Ipv4.URL, conf.Ipv4.URLReg, conf.Ipv4.URLAuth, "IPv4")
+}
+
+// getAddrFromUrls 按顺序请求接口, 直到获得合法的IP
+// urlReg 为空时匹配返回值中的第一个IP
+func getAddrFromUrls(client *http.Client, urls string, urlReg string, auth URLAuth, addrType string) string {
+	comp := Ipv4Reg
+	if addrType == "IPv6" {
+		comp = Ipv6Reg
+	}
+	var userReg *regexp.Regexp
+	if urlReg != "" {
+		reg, err := regexp.Compile(urlReg)
+		if err != nil {
+			util.LogWarn("正则表达式 %s 不正确, 将匹配返回值中的第一个%s", urlReg, addrType)
+		} else {
+			userReg = reg
+		}
+	}
+
+	for _, url := range strings.Split(urls, ",") {
 		url = strings.TrimSpace(url)
-		resp, err := client.Get(url)
+		if url == "" {
+			continue
+		}
+		// 通过DNS查询, 如 dns://resolver1.opendns.com/myip.opendns.com
+		if util.IsDNSIPURL(url) {
+			result, err := util.LookupIPFromDNS(url, addrType == "IPv6")
+			if err != nil {
+				util.LogError("通过DNS查询获取%s失败! 地址: %s", addrType, url)
+				util.LogError("异常信息: %s", err)
+				continue
+			}
+			util.LogDebug("通过接口 %s 获得%s: %s", url, addrType, result)
+			return result
+		}
+		req, err := http.NewRequest(http.MethodGet, url, nil)
 		if err != nil {
-			util.Log("通过接口获取IPv4失败! 接口地址: %s", url)
-			util.Log("异常信息: %s", err)
+			util.LogError("通过接口获取%s失败! 接口地址: %s", addrType, url)
+			util.LogError("异常信息: %s", err)
+			continue
+		}
+		for key, value := range auth.Headers {
+			req.Header.Set(key, value)
+		}
+		if auth.Username != "" || auth.Password != "" {
+			req.SetBasicAuth(auth.Username, auth.Password)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			util.LogError("通过接口获取%s失败! 接口地址: %s", addrType, url)
+			util.LogError("异常信息: %s", err)
 			continue
 		}
-		defer resp.Body.Close()
 		lr := io.LimitReader(resp.Body, 1024000)
 		body, err := io.ReadAll(lr)
+		resp.Body.Close()
 		if err != nil {
-			util.Log("异常信息: %s", err)
+			util.LogError("异常信息: %s", err)
 			continue
 		}
-		result := Ipv4Reg.FindString(string(body))
-		if result == "" {
-			util.Log("获取IPv4结果失败! 接口: %s ,返回值: %s", url, string(body))
+		text := string(body)
+		if userReg == nil && isTraceURL(url) {
+			text = getTraceIP(text)
+		}
+		result := comp.FindString(text)
+		if userReg != nil {
+			result = ""
+			if match := userReg.FindStringSubmatch(string(body)); len(match) > 1 {
+				result = strings.TrimSpace(match[1])
+			} else if len(match) == 1 {
+				result = strings.TrimSpace(match[0])
+			}
+		}
+		if !isValidIP(result, addrType) {
+			util.LogError("获取%s结果失败! 接口: %s ,返回值: %s", addrType, url, string(body))
+			continue
 		}
+		util.LogDebug("通过接口 %s 获得%s: %s", url, addrType, result)
 		return result
 	}
 	return ""
 }
 
+// isTraceURL 是否为 Cloudflare 的 trace 接口, 如 https://cloudflare.com/cdn-cgi/trace
+func isTraceURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && strings.HasSuffix(u.Path, "/cdn-cgi/trace")
+}
+
+// getTraceIP 获得 trace 接口返回值中 ip= 的值
+// 返回值为多行 key=value, h= 等也可能是IP, 不能直接匹配第一个IP
+func getTraceIP(body string) string {
+	for _, line := range strings.Split(body, "\n") {
+		if ip, ok := strings.CutPrefix(strings.TrimSpace(line), "ip="); ok {
+			return ip
+		}
+	}
+	return ""
+}
+
+// newShellCmd 使用系统的shell运行命令
+func newShellCmd(ctx context.Context, cmd string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "powershell", "-Command", cmd)
+	}
+	// If Bash does not exist, use sh
+	if _, err := exec.LookPath("bash"); err != nil {
+		return exec.CommandContext(ctx, "sh", "-c", cmd)
+	}
+	return exec.CommandContext(ctx, "bash", "-c", cmd)
+}
+
 func (conf *DnsConfig) getAddrFromCmd(addrType string) string {
 	var cmd string
 	var comp *regexp.Regexp
@@ -284,62 +473,97 @@ func (conf *DnsConfig) getAddrFromCmd(addrType string) string {
 	if cmd == "" {
 		return ""
 	}
-	// run cmd with proper shell
-	var execCmd *exec.Cmd
-	if runtime.GOOS == "windows" {
-		execCmd = exec.Command("powershell", "-Command", cmd)
-	} else {
-		// If Bash does not exist, use sh
-		_, err := exec.LookPath("bash")
-		if err != nil {
-			execCmd = exec.Command("sh", "-c", cmd)
-		} else {
-			execCmd = exec.Command("bash", "-c", cmd)
-		}
+	// 超时后结束命令, 防止阻塞后续的更新
+	ctx, cancel := context.WithTimeout(context.Background(), cmdTimeout)
+	defer cancel()
+	execCmd := newShellCmd(ctx, cmd)
+	// run cmd, 只使用标准输出
+	var stderr bytes.Buffer
+	execCmd.Stderr = &stderr
+	out, err := execCmd.Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		util.LogError("获取%s结果失败! 命令 %s 执行超过 %s", addrType, execCmd.String(), cmdTimeout)
+		return ""
 	}
-	// run cmd
-	out, err := execCmd.CombinedOutput()
 	if err != nil {
-		util.Log("获取%s结果失败! 未能成功执行命令：%s, 错误：%q, 退出状态码：%s", addrType, execCmd.String(), out, err)
+		util.LogError("获取%s结果失败! 未能成功执行命令：%s, 错误：%q, 退出状态码：%s", addrType, execCmd.String(), stderr.String(), err)
 		return ""
 	}
-	str := string(out)
-	// get result
-	result := comp.FindString(str)
-	if result == "" {
-		util.Log("获取%s结果失败! 命令: %s, 标准输出: %q", addrType, execCmd.String(), str)
+	str := strings.TrimSpace(string(out))
+	// 标准输出为IP时直接使用, 否则从输出中提取
+	result := str
+	if !isValidIP(result, addrType) {
+		result = comp.FindString(str)
+	}
+	if !isValidIP(result, addrType) {
+		util.LogError("获取%s结果失败! 命令: %s, 标准输出: %q", addrType, execCmd.String(), str)
+		return ""
 	}
 	return result
 }
 
+// isValidIP 是否为对应类型的合法IP
+func isValidIP(addr string, addrType string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	if addrType == "IPv4" {
+		return ip.To4() != nil
+	}
+	return ip.To4() == nil
+}
+
+// cgnatNet 运营商级NAT地址段 100.64.0.0/10
+var cgnatNet = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// isPublicIP 是否为公网地址, 内网/回环/链路本地/CGNAT等地址返回false
+func isPublicIP(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	return !(ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() ||
+		ip.IsUnspecified() || ip.IsMulticast() || cgnatNet.Contains(ip))
+}
+
 // GetIpv4Addr 获得IPv4地址
-func (conf *DnsConfig) GetIpv4Addr() string {
+func (conf *DnsConfig) GetIpv4Addr() (result string) {
 	// 判断从哪里获取IP
 	switch conf.Ipv4.GetType {
 	case "netInterface":
 		// 从网卡获取 IP
-		return conf.getIpv4AddrFromInterface()
+		result = conf.getIpv4AddrFromInterface()
 	case "url":
 		// 从 URL 获取 IP
-		return conf.getIpv4AddrFromUrl()
+		result = conf.getIpv4AddrFromUrl()
 	case "cmd":
 		// 从命令行获取 IP
-		return conf.getAddrFromCmd("IPv4")
+		result = conf.getAddrFromCmd("IPv4")
 	default:
 		log.Println("IPv4's get IP method is unknown")
 		return "" // unknown type
 	}
+
+	if result != "" && !conf.Ipv4.AllowPrivate && !isPublicIP(result) {
+		util.LogWarn("获得的IPv4 %s 不是公网地址, 将不会更新! 如需使用请勾选允许内网IP", result)
+		return ""
+	}
+	return
 }
 
 func (conf *DnsConfig) getIpv6AddrFromInterface() string {
 	_, ipv6, err := GetNetInterface()
 	if err != nil {
-		util.Log("从网卡获得IPv6失败")
+		util.LogError("从网卡获得IPv6失败")
 		return ""
 	}
 
 	for _, netInterface := range ipv6 {
 		if netInterface.Name == conf.Ipv6.NetInterface && len(netInterface.Address) > 0 {
+			// 排除已弃用的地址并按稳定/临时地址排序, @N 仍按网卡中的顺序
+			candidates := sortIpv6Addrs(netInterface.Address, getIpv6AddrFlags(), conf.Ipv6.PreferTemporary)
+			util.LogDebug("网卡 %s 共有 %d 个IPv6地址, 可用 %d 个", netInterface.Name, len(netInterface.Address), len(candidates))
 			if conf.Ipv6.Ipv6Reg != "" {
 				// 匹配第几个IPv6
 				if match, err := regexp.MatchString("@\\d", conf.Ipv6.Ipv6Reg); err == nil && match {
@@ -349,58 +573,34 @@ func (conf *DnsConfig) getIpv6AddrFromInterface() string {
 							if num <= len(netInterface.Address) {
 								return netInterface.Address[num-1]
 							}
-							util.Log("未找到第 %d 个IPv6地址! 将使用第一个IPv6地址", num)
+							util.LogWarn("未找到第 %d 个IPv6地址! 将使用第一个IPv6地址", num)
 							return netInterface.Address[0]
 						}
-						util.Log("IPv6匹配表达式 %s 不正确! 最小从1开始", conf.Ipv6.Ipv6Reg)
+						util.LogWarn("IPv6匹配表达式 %s 不正确! 最小从1开始", conf.Ipv6.Ipv6Reg)
 						return ""
 					}
 				}
 				// 正则表达式匹配
-				util.Log("IPv6将使用正则表达式 %s 进行匹配", conf.Ipv6.Ipv6Reg)
-				for i := 0; i < len(netInterface.Address); i++ {
-					matched, err := regexp.MatchString(conf.Ipv6.Ipv6Reg, netInterface.Address[i])
+				util.LogDebug("IPv6将使用正则表达式 %s 进行匹配", conf.Ipv6.Ipv6Reg)
+				for i := 0; i < len(candidates); i++ {
+					matched, err := regexp.MatchString(conf.Ipv6.Ipv6Reg, candidates[i])
 					if matched && err == nil {
-						util.Log("匹配成功! 匹配到地址: ", netInterface.Address[i])
-						return netInterface.Address[i]
+						util.LogDebug("匹配成功! 匹配到地址: %s", candidates[i])
+						return candidates[i]
 					}
 				}
-				util.Log("没有匹配到任何一个IPv6地址, 将使用第一个地址")
+				util.LogWarn("没有匹配到任何一个IPv6地址, 将使用第一个地址")
 			}
-			return netInterface.Address[0]
+			return candidates[0]
 		}
 	}
 
-	util.Log("从网卡中获得IPv6失败! 网卡名: %s", conf.Ipv6.NetInterface)
+	util.LogError("从网卡中获得IPv6失败! 网卡名: %s", conf.Ipv6.NetInterface)
 	return ""
 }
 
 func (conf *DnsConfig) getIpv6AddrFromUrl() string {
-	client := util.CreateNoProxyHTTPClient("tcp6")
-	urls := strings.Split(conf.Ipv6.URL, ",")
-	for _, url := range urls {
-		url = strings.TrimSpace(url)
-		resp, err := client.Get(url)
-		if err != nil {
-			util.Log("通过接口获取IPv6失败! 接口地址: %s", url)
-			util.Log("异常信息: %s", err)
-			continue
-		}
-
-		defer resp.Body.Close()
-		lr := io.LimitReader(resp.Body, 1024000)
-		body, err := io.ReadAll(lr)
-		if err != nil {
-			util.Log("异常信息: %s", err)
-			continue
-		}
-		result := Ipv6Reg.FindString(string(body))
-		if result == "" {
-			util.Log("获取IPv6结果失败! 接口: %s ,返回值: %s", url, result)
-		}
-		return result
-	}
-	return ""
+	return getAddrFromUrls(util.CreateNoProxyHTTPClient("tcp6"), conf.Ipv6.URL, conf.Ipv6.URLReg, conf.Ipv6.URLAuth, "IPv6")
 }
 
 // GetIpv6Addr 获得IPv6地址
@@ -409,15 +609,49 @@ func (conf *DnsConfig) GetIpv6Addr() (result string) {
 	switch conf.Ipv6.GetType {
 	case "netInterface":
 		// 从网卡获取 IP
-		return conf.getIpv6AddrFromInterface()
+		result = conf.getIpv6AddrFromInterface()
 	case "url":
 		// 从 URL 获取 IP
-		return conf.getIpv6AddrFromUrl()
+		result = conf.getIpv6AddrFromUrl()
 	case "cmd":
 		// 从命令行获取 IP
-		return conf.getAddrFromCmd("IPv6")
+		result = conf.getAddrFromCmd("IPv6")
 	default:
 		log.Println("IPv6's get IP method is unknown")
 		return "" // unknown type
 	}
+
+	if result != "" && conf.Ipv6.Suffix != "" {
+		composed, err := composeIPv6(result, conf.Ipv6.Suffix)
+		if err != nil {
+			util.LogWarn("IPv6后缀 %s 不正确! 异常信息: %s", conf.Ipv6.Suffix, err)
+			return ""
+		}
+		util.LogDebug("使用 %s 的前缀与后缀 %s 组合为 %s", result, conf.Ipv6.Suffix, composed)
+		return composed
+	}
+	return
+}
+
+// ipv6PrefixLen 组合IPv6时保留的前缀长度(字节), 即/64
+const ipv6PrefixLen = 8
+
+// composeIPv6 使用 addr 的/64前缀与 suffix 的接口ID组合为新的IPv6
+func composeIPv6(addr string, suffix string) (string, error) {
+	prefixIP := net.ParseIP(addr)
+	if prefixIP == nil || prefixIP.To4() != nil {
+		return "", fmt.Errorf("%s is not IPv6", addr)
+	}
+	if !strings.Contains(suffix, ":") {
+		suffix = "::" + suffix
+	}
+	suffixIP := net.ParseIP(suffix)
+	if suffixIP == nil || suffixIP.To4() != nil {
+		return "", fmt.Errorf("%s is not IPv6", suffix)
+	}
+
+	composed := make(net.IP, net.IPv6len)
+	copy(composed[:ipv6PrefixLen], prefixIP.To16()[:ipv6PrefixLen])
+	copy(composed[ipv6PrefixLen:], suffixIP.To16()[ipv6PrefixLen:])
+	return composed.String(), nil
 }
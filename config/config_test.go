@@ -0,0 +1,174 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+)
+
+// TestGetAddrFromCmd 测试通过命令获取IP
+func TestGetAddrFromCmd(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("使用 sh 测试")
+	}
+
+	tests := []struct {
+		addrType string
+		cmd      string
+		want     string
+	}{
+		{"IPv4", "echo ' 1.2.3.4 '", "1.2.3.4"},
+		{"IPv4", "echo 'inet 10.0.0.1/24 brd 10.0.0.255'", "10.0.0.1"},
+		{"IPv4", "echo 1.2.3.4 >&2", ""},
+		{"IPv4", "echo 2400:3200::1", ""},
+		{"IPv6", "echo 2400:3200::1", "2400:3200::1"},
+		{"IPv6", "echo 1.2.3.4", ""},
+		{"IPv4", "exit 1", ""},
+	}
+
+	for _, tt := range tests {
+		conf := &DnsConfig{}
+		conf.Ipv4.Cmd = tt.cmd
+		conf.Ipv6.Cmd = tt.cmd
+		if got := conf.getAddrFromCmd(tt.addrType); got != tt.want {
+			t.Errorf("%s %q 期待 %q, 得到 %q", tt.addrType, tt.cmd, tt.want, got)
+		}
+	}
+}
+
+// TestGetAddrFromUrls 测试接口失败或返回值不正确时使用下一个接口
+func TestGetAddrFromUrls(t *testing.T) {
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("rate limited"))
+	}))
+	defer bad.Close()
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("1.2.3.4\n"))
+	}))
+	defer good.Close()
+
+	urls := "http://127.0.0.1:1, " + bad.URL + ", " + good.URL
+	if got := getAddrFromUrls(http.DefaultClient, urls, "", URLAuth{}, "IPv4"); got != "1.2.3.4" {
+		t.Errorf("期待 1.2.3.4, 得到 %q", got)
+	}
+	if got := getAddrFromUrls(http.DefaultClient, bad.URL, "", URLAuth{}, "IPv4"); got != "" {
+		t.Errorf("期待空, 得到 %q", got)
+	}
+}
+
+// TestGetAddrFromUrlsWithReg 测试使用正则表达式从返回值中提取IP
+func TestGetAddrFromUrlsWithReg(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"proxy":"9.9.9.9","ip":"1.2.3.4"}`))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		reg  string
+		want string
+	}{
+		{"", "9.9.9.9"},
+		{`"ip":"([^"]+)"`, "1.2.3.4"},
+		{`"proxy":"[^"]+"`, ""},
+		{`"ip":"(`, "9.9.9.9"},
+	}
+	for _, tt := range tests {
+		if got := getAddrFromUrls(http.DefaultClient, srv.URL, tt.reg, URLAuth{}, "IPv4"); got != tt.want {
+			t.Errorf("正则 %q 期待 %q, 得到 %q", tt.reg, tt.want, got)
+		}
+	}
+}
+
+// TestGetAddrFromTrace 测试从 Cloudflare trace 接口的 ip= 获取IP
+func TestGetAddrFromTrace(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/cdn-cgi/trace", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("fl=123f45\nh=1.1.1.1\nip=1.2.3.4\nts=1700000000.123\nvisit_scheme=https\n"))
+	})
+	mux.HandleFunc("/v6/cdn-cgi/trace", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("h=[2606:4700:4700::1111]\nip=2409::1\n"))
+	})
+	mux.HandleFunc("/no-ip/cdn-cgi/trace", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("h=1.1.1.1\n"))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	if got := getAddrFromUrls(http.DefaultClient, srv.URL+"/cdn-cgi/trace", "", URLAuth{}, "IPv4"); got != "1.2.3.4" {
+		t.Errorf("期待 1.2.3.4, 得到 %q", got)
+	}
+	if got := getAddrFromUrls(http.DefaultClient, srv.URL+"/v6/cdn-cgi/trace", "", URLAuth{}, "IPv6"); got != "2409::1" {
+		t.Errorf("期待 2409::1, 得到 %q", got)
+	}
+	if got := getAddrFromUrls(http.DefaultClient, srv.URL+"/no-ip/cdn-cgi/trace", "", URLAuth{}, "IPv4"); got != "" {
+		t.Errorf("期待空, 得到 %q", got)
+	}
+}
+
+// TestGetAddrFromUrlsWithAuth 测试请求接口时带上请求头和Basic认证
+func TestGetAddrFromUrlsWithAuth(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if r.Header.Get("X-Token") != "abc" || !ok || username != "admin" || password != "p:w" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("1.2.3.4"))
+	}))
+	defer srv.Close()
+
+	auth := URLAuth{Headers: map[string]string{"X-Token": "abc"}, Username: "admin", Password: "p:w"}
+	if got := getAddrFromUrls(http.DefaultClient, srv.URL, "", auth, "IPv4"); got != "1.2.3.4" {
+		t.Errorf("期待 1.2.3.4, 得到 %q", got)
+	}
+	if got := getAddrFromUrls(http.DefaultClient, srv.URL, "", URLAuth{}, "IPv4"); got != "" {
+		t.Errorf("期待空, 得到 %q", got)
+	}
+}
+
+// TestIsPublicIP 测试内网/回环/链路本地/CGNAT地址不是公网地址
+func TestIsPublicIP(t *testing.T) {
+	tests := map[string]bool{
+		"1.2.3.4":         true,
+		"100.63.255.255":  true,
+		"100.64.0.1":      false,
+		"100.127.255.255": false,
+		"192.168.1.1":     false,
+		"10.0.0.1":        false,
+		"172.16.0.1":      false,
+		"127.0.0.1":       false,
+		"169.254.1.1":     false,
+		"0.0.0.0":         false,
+		"2400:3200::1":    true,
+		"fd00::1":         false,
+		"fe80::1":         false,
+		"::1":             false,
+	}
+	for addr, want := range tests {
+		if got := isPublicIP(addr); got != want {
+			t.Errorf("%s 期待 %v, 得到 %v", addr, want, got)
+		}
+	}
+}
+
+// TestComposeIPv6 测试IPv6前缀与后缀组合
+func TestComposeIPv6(t *testing.T) {
+	tests := []struct {
+		addr, suffix, want string
+		wantErr            bool
+	}{
+		{"2400:3200:1:2:aaaa:bbbb:cccc:dddd", "::1234", "2400:3200:1:2::1234", false},
+		{"2400:3200:1:2::1", "1234", "2400:3200:1:2::1234", false},
+		{"2400:3200:1:2::1", "::a:b:c:d", "2400:3200:1:2:a:b:c:d", false},
+		{"2400:3200:1:2::1", "ffff:ffff:ffff:ffff:0:0:0:1", "2400:3200:1:2::1", false},
+		{"2400:3200:1:2::1", "xyz", "", true},
+		{"1.2.3.4", "::1", "", true},
+	}
+	for _, tt := range tests {
+		got, err := composeIPv6(tt.addr, tt.suffix)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("%s + %s 期待 %q, 得到 %q, %v", tt.addr, tt.suffix, tt.want, got, err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// 每条消息最多10个embed
+const discordMaxEmbeds = 10
+
+// Discord Discord Webhook通知
+type Discord struct {
+	// 如：https://discord.com/api/webhooks/id/token
+	DiscordURL string
+	// 触发条件, 同 WebhookTrigger
+	DiscordTrigger string
+}
+
+// discordNotifier Discord Webhook通知
+type discordNotifier struct {
+	Discord
+}
+
+// discordEmbed 每个域名一个embed
+type discordEmbed struct {
+	Title  string              `json:"title"`
+	Color  int                 `json:"color"`
+	Fields []discordEmbedField `json:"fields"`
+}
+
+type discordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+// Notify 通过Discord Webhook发送更新结果, 超过10个域名时分多条消息发送
+func (d discordNotifier) Notify(ctx context.Context, results []DomainResult) error {
+	embeds := getDiscordEmbeds(getNotifyResults(results))
+	for len(embeds) > 0 {
+		n := len(embeds)
+		if n > discordMaxEmbeds {
+			n = discordMaxEmbeds
+		}
+		if err := d.send(ctx, embeds[:n]); err != nil {
+			return d.hideURL(err)
+		}
+		embeds = embeds[n:]
+	}
+	return nil
+}
+
+func (d discordNotifier) send(ctx context.Context, embeds []discordEmbed) error {
+	byt, _ := json.Marshal(map[string]interface{}{
+		"username": "ddns-go",
+		"embeds":   embeds,
+	})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.DiscordURL, bytes.NewReader(byt))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	clt := util.CreateHTTPClient()
+	resp, err := clt.Do(req)
+	_, err = util.GetHTTPResponseOrg(resp, err)
+	return err
+}
+
+// hideURL 异常信息中可能包含地址中的Token
+func (d discordNotifier) hideURL(err error) error {
+	return errors.New(strings.ReplaceAll(err.Error(), d.DiscordURL, "***"))
+}
+
+// getDiscordEmbeds 每个域名的 原IP → 新IP 及更新结果, 成功为绿色, 失败为红色
+func getDiscordEmbeds(results []DomainResult) []discordEmbed {
+	embeds := make([]discordEmbed, 0, len(results))
+	for _, result := range results {
+		oldIP := result.OldIP
+		if oldIP == "" {
+			oldIP = "-"
+		}
+		status := result.updateStatus()
+		embeds = append(embeds, discordEmbed{
+			Title: result.Domain + " " + result.RecordType,
+			Color: getStatusColor(status),
+			Fields: []discordEmbedField{
+				{Name: "IP", Value: oldIP + " → " + result.NewIP, Inline: true},
+				{Name: "Status", Value: util.LogStr(string(status)), Inline: true},
+			},
+		})
+	}
+	return embeds
+}
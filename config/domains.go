@@ -2,6 +2,7 @@ package config
 
 import (
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/jeessy2/ddns-go/v6/util"
@@ -16,6 +17,17 @@ type Domains struct {
 	Ipv6Addr    string
 	Ipv6Cache   *util.IpCache
 	Ipv6Domains []*Domain
+	// 上次获得的IP, 启动或更新失败后为空
+	Ipv4PrevAddr string
+	Ipv6PrevAddr string
+	// CnameDomains 不需要获取IP, Target 为解析目标
+	CnameDomains []*Domain
+	// MxDomains 不需要获取IP, Target 为邮件服务器, Priority 为优先级
+	MxDomains []*Domain
+	// TxtDomains 不需要获取IP, Target 为记录值
+	TxtDomains []*Domain
+	// DohURL 为空时不通过DoH预先查询
+	DohURL string
 }
 
 // Domain 域名实体
@@ -25,6 +37,20 @@ type Domain struct {
 	// SubDomain 子域名
 	SubDomain    string
 	CustomParams string
+	// Provider DNS服务商名称, 用于日志
+	Provider string
+	// Target CNAME记录的目标, MX记录的邮件服务器或TXT记录的值
+	Target string
+	// Priority MX记录的优先级
+	Priority int
+	// TTL 单条记录的TTL, 为0时使用配置的TTL
+	TTL int
+	// Proxied 单条记录是否开启代理, 为nil时使用配置的值
+	Proxied *bool
+	// NonWeb 非Web服务(如SSH、游戏服务器), 总是不开启代理
+	NonWeb bool
+	// UpdateOnly 只更新已存在的记录, 不新增
+	UpdateOnly   bool
 	UpdateStatus updateStatusType // 更新状态
 }
 
@@ -35,6 +61,11 @@ func (d Domain) String() string {
 	return d.DomainName
 }
 
+// LogFields JSON格式的日志中包含域名和DNS服务商
+func (d Domain) LogFields() map[string]string {
+	return map[string]string{"domain": d.String(), "provider": d.Provider}
+}
+
 // GetFullDomain 获得全部的，子域名
 func (d Domain) GetFullDomain() string {
 	if d.SubDomain != "" {
@@ -52,6 +83,25 @@ func (d Domain) GetSubDomain() string {
 	return "@"
 }
 
+// GetTTL 获得记录的TTL, 未单独设置时返回配置的TTL
+func (d Domain) GetTTL(defaultTTL int) int {
+	if d.TTL > 0 {
+		return d.TTL
+	}
+	return defaultTTL
+}
+
+// GetProxied 获得记录是否开启代理, 非Web服务总是不开启, 未单独设置时返回配置的值
+func (d Domain) GetProxied(defaultProxied bool) bool {
+	if d.NonWeb {
+		return false
+	}
+	if d.Proxied != nil {
+		return *d.Proxied
+	}
+	return defaultProxied
+}
+
 // GetCustomParams not be nil
 func (d Domain) GetCustomParams() url.Values {
 	if d.CustomParams != "" {
@@ -67,6 +117,17 @@ func (d Domain) GetCustomParams() url.Values {
 func (domains *Domains) GetNewIp(dnsConf *DnsConfig) {
 	domains.Ipv4Domains = checkParseDomains(dnsConf.Ipv4.Domains)
 	domains.Ipv6Domains = checkParseDomains(dnsConf.Ipv6.Domains)
+	domains.CnameDomains = checkParseCnameDomains(dnsConf.Cname.Domains)
+	domains.MxDomains = checkParseMxDomains(dnsConf.Mx.Domains)
+	for _, list := range [][]*Domain{domains.Ipv4Domains, domains.Ipv6Domains, domains.CnameDomains, domains.MxDomains} {
+		for _, domain := range list {
+			domain.Provider = dnsConf.DNS.Name
+			domain.UpdateOnly = domain.UpdateOnly || dnsConf.UpdateOnly
+		}
+	}
+	domains.DohURL = dnsConf.DohURL
+	domains.Ipv4PrevAddr = domains.Ipv4Cache.Addr
+	domains.Ipv6PrevAddr = domains.Ipv6Cache.Addr
 
 	// IPv4
 	if dnsConf.Ipv4.Enable && len(domains.Ipv4Domains) > 0 {
@@ -80,7 +141,7 @@ func (domains *Domains) GetNewIp(dnsConf *DnsConfig) {
 			if domains.Ipv4Cache.TimesFailedIP == 3 {
 				domains.Ipv4Domains[0].UpdateStatus = UpdatedFailed
 			}
-			util.Log("未能获取IPv4地址, 将不会更新")
+			util.LogWarn("未能获取IPv4地址, 将不会更新")
 		}
 	}
 
@@ -96,7 +157,7 @@ func (domains *Domains) GetNewIp(dnsConf *DnsConfig) {
 			if domains.Ipv6Cache.TimesFailedIP == 3 {
 				domains.Ipv6Domains[0].UpdateStatus = UpdatedFailed
 			}
-			util.Log("未能获取IPv6地址, 将不会更新")
+			util.LogWarn("未能获取IPv6地址, 将不会更新")
 		}
 	}
 
@@ -121,10 +182,12 @@ func checkParseDomains(domainArr []string) (domains []*Domain) {
 
 		switch len(dp) {
 		case 1: // 不使用冒号分割，自动识别域名
+			// @example.com 或 @.example.com 表示根域名本身
+			domainStr = strings.TrimPrefix(strings.TrimPrefix(domainStr, "@."), "@")
 			domainName, err := publicsuffix.EffectiveTLDPlusOne(domainStr)
 			if err != nil {
-				util.Log("域名: %s 不正确", domainStr)
-				util.Log("异常信息: %s", err)
+				util.LogWarn("域名: %s 不正确", domainStr)
+				util.LogError("异常信息: %s", err)
 				continue
 			}
 			domain.DomainName = domainName
@@ -136,13 +199,16 @@ func checkParseDomains(domainArr []string) (domains []*Domain) {
 		case 2: // 使用冒号分隔，为 子域名:根域名 格式
 			sp := strings.Split(dp[1], ".")
 			if len(sp) <= 1 {
-				util.Log("域名: %s 不正确", domainStr)
+				util.LogWarn("域名: %s 不正确", domainStr)
 				continue
 			}
 			domain.DomainName = dp[1]
-			domain.SubDomain = dp[0]
+			// @:example.com 表示根域名本身
+			if dp[0] != "@" {
+				domain.SubDomain = dp[0]
+			}
 		default:
-			util.Log("域名: %s 不正确", domainStr)
+			util.LogWarn("域名: %s 不正确", domainStr)
 			continue
 		}
 
@@ -150,31 +216,171 @@ func checkParseDomains(domainArr []string) (domains []*Domain) {
 		if len(qp) == 2 {
 			u, err := url.Parse("https://baidu.com?" + qp[1])
 			if err != nil {
-				util.Log("域名: %s 解析失败", domainStr)
+				util.LogError("域名: %s 解析失败", domainStr)
 				continue
 			}
-			domain.CustomParams = u.Query().Encode()
+			query := u.Query()
+			// ttl 只用于覆盖配置的TTL, 不传递给DNS服务商
+			if ttlStr := query.Get("ttl"); ttlStr != "" {
+				if ttl, err := strconv.Atoi(ttlStr); err == nil && ttl > 0 {
+					domain.TTL = ttl
+				} else {
+					util.LogWarn("域名: %s 的TTL %s 不正确, 将使用配置的TTL", domainStr, ttlStr)
+				}
+				query.Del("ttl")
+			}
+			// proxied 只用于覆盖配置的代理开关, 不传递给DNS服务商
+			if proxiedStr := query.Get("proxied"); proxiedStr != "" {
+				if proxied, err := strconv.ParseBool(proxiedStr); err == nil {
+					domain.Proxied = &proxied
+				} else {
+					util.LogWarn("域名: %s 的proxied %s 不正确, 将使用配置的值", domainStr, proxiedStr)
+				}
+				query.Del("proxied")
+			}
+			// nonWeb 只用于强制不开启代理, 不传递给DNS服务商
+			if nonWebStr := query.Get("nonWeb"); nonWebStr != "" {
+				if nonWeb, err := strconv.ParseBool(nonWebStr); err == nil {
+					domain.NonWeb = nonWeb
+				} else {
+					util.LogWarn("域名: %s 的nonWeb %s 不正确, 将使用配置的值", domainStr, nonWebStr)
+				}
+				if domain.NonWeb && domain.Proxied != nil && *domain.Proxied {
+					util.LogWarn("域名: %s 为非Web服务, 将不会开启代理", domainStr)
+				}
+				query.Del("nonWeb")
+			}
+			// updateOnly 只用于开启只更新不新增, 不传递给DNS服务商
+			if updateOnlyStr := query.Get("updateOnly"); updateOnlyStr != "" {
+				if updateOnly, err := strconv.ParseBool(updateOnlyStr); err == nil {
+					domain.UpdateOnly = updateOnly
+				} else {
+					util.LogWarn("域名: %s 的updateOnly %s 不正确, 将使用配置的值", domainStr, updateOnlyStr)
+				}
+				query.Del("updateOnly")
+			}
+			domain.CustomParams = query.Encode()
 		}
 		domains = append(domains, domain)
 	}
 	return
 }
 
-// GetNewIpResult 获得GetNewIp结果
+// checkParseCnameDomains 解析 域名 目标 格式的CNAME记录
+func checkParseCnameDomains(lines []string) (domains []*Domain) {
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			util.LogWarn("CNAME记录: %s 不正确, 格式为 域名 目标", line)
+			continue
+		}
+		parsed := checkParseDomains(fields[:1])
+		if len(parsed) == 0 {
+			continue
+		}
+		parsed[0].Target = strings.TrimSuffix(fields[1], ".")
+		domains = append(domains, parsed[0])
+	}
+	return
+}
+
+// checkParseMxDomains 解析 域名 优先级 目标 格式的MX记录
+func checkParseMxDomains(lines []string) (domains []*Domain) {
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 {
+			util.LogWarn("MX记录: %s 不正确, 格式为 域名 优先级 目标", line)
+			continue
+		}
+		priority, err := strconv.Atoi(fields[1])
+		if err != nil || priority < 0 || priority > 65535 {
+			util.LogWarn("MX记录: %s 不正确, 格式为 域名 优先级 目标", line)
+			continue
+		}
+		parsed := checkParseDomains(fields[:1])
+		if len(parsed) == 0 {
+			continue
+		}
+		parsed[0].Priority = priority
+		parsed[0].Target = strings.TrimSuffix(fields[2], ".")
+		domains = append(domains, parsed[0])
+	}
+	return
+}
+
+// GetNewIpResult 获得GetNewIp结果, CNAME/MX/TXT记录没有IP, 总是返回空
 func (domains *Domains) GetNewIpResult(recordType string) (ipAddr string, retDomains []*Domain) {
+	switch recordType {
+	case "CNAME":
+		return "", domains.CnameDomains
+	case "MX":
+		return "", domains.MxDomains
+	case "TXT":
+		return "", domains.TxtDomains
+	}
 	if recordType == "AAAA" {
 		if domains.Ipv6Cache.Check(domains.Ipv6Addr) {
-			return domains.Ipv6Addr, domains.Ipv6Domains
+			return domains.filterByDoH(domains.Ipv6Addr, recordType, domains.Ipv6Domains)
 		} else {
-			util.Log("IPv6未改变, 将等待 %d 次后与DNS服务商进行比对", domains.Ipv6Cache.Times)
+			if domains.Ipv6Cache.Times < 0 {
+				util.LogDebug("IPv6未改变, 将不会与DNS服务商进行比对")
+			} else {
+				util.LogDebug("IPv6未改变, 将等待 %d 次后与DNS服务商进行比对", domains.Ipv6Cache.Times)
+			}
 			return "", domains.Ipv6Domains
 		}
 	}
 	// IPv4
 	if domains.Ipv4Cache.Check(domains.Ipv4Addr) {
-		return domains.Ipv4Addr, domains.Ipv4Domains
+		return domains.filterByDoH(domains.Ipv4Addr, recordType, domains.Ipv4Domains)
 	} else {
-		util.Log("IPv4未改变, 将等待 %d 次后与DNS服务商进行比对", domains.Ipv4Cache.Times)
+		if domains.Ipv4Cache.Times < 0 {
+			util.LogDebug("IPv4未改变, 将不会与DNS服务商进行比对")
+		} else {
+			util.LogDebug("IPv4未改变, 将等待 %d 次后与DNS服务商进行比对", domains.Ipv4Cache.Times)
+		}
 		return "", domains.Ipv4Domains
 	}
 }
+
+// filterByDoH 通过DoH查询当前记录, 去掉记录已是 ipAddr 的域名
+// 全部无需更新时返回空IP, 不再请求DNS服务商
+func (domains *Domains) filterByDoH(ipAddr string, recordType string, list []*Domain) (string, []*Domain) {
+	if domains.DohURL == "" || ipAddr == "" {
+		return ipAddr, list
+	}
+
+	client := util.CreateHTTPClient()
+	var result []*Domain
+	for _, domain := range list {
+		// 带自定义参数的记录可能有多条线路, 交给DNS服务商比对
+		if domain.CustomParams != "" {
+			result = append(result, domain)
+			continue
+		}
+		addrs, err := util.LookupDoH(client, domains.DohURL, domain.String(), recordType)
+		if err != nil {
+			util.LogError("通过DoH查询域名 %s 失败! 异常信息: %s", domain.String(), err)
+			result = append(result, domain)
+			continue
+		}
+		if len(addrs) == 1 && addrs[0] == ipAddr {
+			util.LogDebug("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+			domain.UpdateStatus = UpdatedNothing
+			continue
+		}
+		result = append(result, domain)
+	}
+
+	// 全部无需更新, 均已标记为未改变
+	if len(result) == 0 {
+		return "", list
+	}
+	return ipAddr, result
+}
@@ -1,7 +1,13 @@
 package config
 
 import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+	"golang.org/x/net/dns/dnsmessage"
 )
 
 // TestParseDomainArr 测试 parseDomainArr
@@ -37,3 +43,206 @@ func TestParseDomainArr(t *testing.T) {
 	}
 
 }
+
+// TestParsePublicSuffix 测试多级公共后缀按公共后缀列表拆分根域名和子域名
+func TestParsePublicSuffix(t *testing.T) {
+	tests := []struct {
+		input      string
+		domainName string
+		subDomain  string
+	}{
+		{"sub.example.co.uk", "example.co.uk", "sub"},
+		{"a.b.example.co.uk", "example.co.uk", "a.b"},
+		{"example.co.uk", "example.co.uk", ""},
+		{"www.example.com.cn", "example.com.cn", "www"},
+		{"blog.user.github.io", "user.github.io", "blog"},
+		{"user.github.io", "user.github.io", ""},
+	}
+
+	for _, tt := range tests {
+		parsed := checkParseDomains([]string{tt.input})
+		if len(parsed) != 1 || parsed[0].DomainName != tt.domainName || parsed[0].SubDomain != tt.subDomain {
+			t.Errorf("解析 %s 失败, 期待 %s %s, 得到 %+v", tt.input, tt.domainName, tt.subDomain, parsed)
+		}
+	}
+}
+
+// TestParseApexAndWildcard 测试根域名、子域名、多级子域名和泛解析
+func TestParseApexAndWildcard(t *testing.T) {
+	tests := []struct {
+		input      string
+		domainName string
+		subDomain  string
+		full       string
+		rr         string
+	}{
+		{"example.com", "example.com", "", "example.com", "@"},
+		{"@example.com", "example.com", "", "example.com", "@"},
+		{"@.example.com", "example.com", "", "example.com", "@"},
+		{"@:example.com", "example.com", "", "example.com", "@"},
+		{"www.example.com", "example.com", "www", "www.example.com", "www"},
+		{"a.b.c.example.com", "example.com", "a.b.c", "a.b.c.example.com", "a.b.c"},
+		{"*.example.com", "example.com", "*", "*.example.com", "*"},
+		{"*.dev.example.com", "example.com", "*.dev", "*.dev.example.com", "*.dev"},
+		{"*:example.cn.eu.org", "example.cn.eu.org", "*", "*.example.cn.eu.org", "*"},
+	}
+
+	for _, tt := range tests {
+		parsed := checkParseDomains([]string{tt.input})
+		if len(parsed) != 1 {
+			t.Errorf("解析 %s 失败", tt.input)
+			continue
+		}
+		d := parsed[0]
+		if d.DomainName != tt.domainName || d.SubDomain != tt.subDomain ||
+			d.String() != tt.full || d.GetSubDomain() != tt.rr {
+			t.Errorf("解析 %s 失败：期待 %s/%s/%s/%s，得到 %s/%s/%s/%s", tt.input,
+				tt.domainName, tt.subDomain, tt.full, tt.rr,
+				d.DomainName, d.SubDomain, d.String(), d.GetSubDomain())
+		}
+	}
+}
+
+// TestParseCnameDomains 测试CNAME记录解析
+func TestParseCnameDomains(t *testing.T) {
+	parsed := checkParseCnameDomains([]string{"www.example.com home.example.net.", "", "bad.example.com", "@:example.com\ttarget.example.org"})
+	if len(parsed) != 2 {
+		t.Fatalf("期待 2 条记录, 得到 %d 条", len(parsed))
+	}
+	if parsed[0].String() != "www.example.com" || parsed[0].Target != "home.example.net" {
+		t.Errorf("解析失败, 得到 %s -> %s", parsed[0], parsed[0].Target)
+	}
+	if parsed[1].String() != "example.com" || parsed[1].Target != "target.example.org" {
+		t.Errorf("解析失败, 得到 %s -> %s", parsed[1], parsed[1].Target)
+	}
+}
+
+// TestParseDomainTTL 测试单条记录的TTL
+func TestParseDomainTTL(t *testing.T) {
+	parsed := checkParseDomains([]string{"www.example.com?ttl=3600&Line=oversea", "example.com", "bad.example.com?ttl=abc"})
+	if len(parsed) != 3 {
+		t.Fatalf("期待 3 条记录, 得到 %d 条", len(parsed))
+	}
+	if parsed[0].GetTTL(300) != 3600 || parsed[0].CustomParams != "Line=oversea" {
+		t.Errorf("期待 TTL 3600 和 Line=oversea, 得到 %d 和 %s", parsed[0].GetTTL(300), parsed[0].CustomParams)
+	}
+	if parsed[1].GetTTL(300) != 300 {
+		t.Errorf("期待 TTL 300, 得到 %d", parsed[1].GetTTL(300))
+	}
+	if parsed[2].GetTTL(300) != 300 || parsed[2].CustomParams != "" {
+		t.Errorf("期待 TTL 300 且无自定义参数, 得到 %d 和 %s", parsed[2].GetTTL(300), parsed[2].CustomParams)
+	}
+}
+
+// TestParseDomainProxied 测试单条记录的代理开关
+func TestParseDomainProxied(t *testing.T) {
+	parsed := checkParseDomains([]string{"ssh.example.com?proxied=false", "www.example.com?proxied=true&ttl=60", "mail.example.com"})
+	if len(parsed) != 3 {
+		t.Fatalf("期待 3 条记录, 得到 %d 条", len(parsed))
+	}
+	if parsed[0].GetProxied(true) || !parsed[1].GetProxied(false) || !parsed[2].GetProxied(true) || parsed[2].GetProxied(false) {
+		t.Error("proxied 解析不正确")
+	}
+	if parsed[1].GetTTL(1) != 60 || parsed[0].CustomParams != "" || parsed[1].CustomParams != "" {
+		t.Errorf("期待参数被移除, 得到 %s 和 %s", parsed[0].CustomParams, parsed[1].CustomParams)
+	}
+}
+
+// TestParseDomainUpdateOnly 测试单条记录开启只更新不新增
+func TestParseDomainUpdateOnly(t *testing.T) {
+	parsed := checkParseDomains([]string{"www.example.com?updateOnly=true&Line=oversea", "mail.example.com?updateOnly=abc", "example.com"})
+	if len(parsed) != 3 {
+		t.Fatalf("期待 3 条记录, 得到 %d 条", len(parsed))
+	}
+	if !parsed[0].UpdateOnly || parsed[1].UpdateOnly || parsed[2].UpdateOnly {
+		t.Error("updateOnly 解析不正确")
+	}
+	if parsed[0].CustomParams != "Line=oversea" || parsed[1].CustomParams != "" {
+		t.Errorf("期待参数被移除, 得到 %s 和 %s", parsed[0].CustomParams, parsed[1].CustomParams)
+	}
+}
+
+// TestParseDomainNonWeb 测试非Web服务总是不开启代理
+func TestParseDomainNonWeb(t *testing.T) {
+	parsed := checkParseDomains([]string{"ssh.example.com?nonWeb=true", "game.example.com?nonWeb=true&proxied=true", "www.example.com?nonWeb=false"})
+	if len(parsed) != 3 {
+		t.Fatalf("期待 3 条记录, 得到 %d 条", len(parsed))
+	}
+	for i, want := range []bool{false, false, true} {
+		if got := parsed[i].GetProxied(true); got != want {
+			t.Errorf("%s 期待代理 %t, 得到 %t", parsed[i], want, got)
+		}
+		if parsed[i].CustomParams != "" {
+			t.Errorf("期待参数被移除, 得到 %s", parsed[i].CustomParams)
+		}
+	}
+}
+
+// TestNewIpResultMultipleDomains 测试同一配置下的多个域名都使用获取到的同一个IP
+func TestNewIpResultMultipleDomains(t *testing.T) {
+	domains := Domains{
+		Ipv4Cache:   &util.IpCache{},
+		Ipv4Addr:    "1.2.3.4",
+		Ipv4Domains: checkParseDomains([]string{"home.example.com", "vpn.example.com", "example.net"}),
+	}
+	ipAddr, list := domains.GetNewIpResult("A")
+	if ipAddr != "1.2.3.4" || len(list) != 3 {
+		t.Fatalf("期待 3 个域名使用 1.2.3.4, 得到 %d 个使用 %q", len(list), ipAddr)
+	}
+}
+
+// TestParseMxDomains 测试MX记录解析
+func TestParseMxDomains(t *testing.T) {
+	parsed := checkParseMxDomains([]string{"example.com 10 mail.example.com.", "", "bad.example.com mail.example.com", "bad.example.com x mail.example.com", "@:example.net\t20\tmx.example.org"})
+	if len(parsed) != 2 {
+		t.Fatalf("期待 2 条记录, 得到 %d 条", len(parsed))
+	}
+	if parsed[0].String() != "example.com" || parsed[0].Priority != 10 || parsed[0].Target != "mail.example.com" {
+		t.Errorf("解析失败, 得到 %s -> %d %s", parsed[0], parsed[0].Priority, parsed[0].Target)
+	}
+	if parsed[1].String() != "example.net" || parsed[1].Priority != 20 || parsed[1].Target != "mx.example.org" {
+		t.Errorf("解析失败, 得到 %s -> %d %s", parsed[1], parsed[1].Priority, parsed[1].Target)
+	}
+}
+
+// TestFilterByDoH 测试DoH查询到记录已是当前IP的域名标记为未改变, 其它域名交给DNS服务商
+func TestFilterByDoH(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query, _ := base64.RawURLEncoding.DecodeString(r.URL.Query().Get("dns"))
+		var msg dnsmessage.Message
+		if err := msg.Unpack(query); err != nil {
+			t.Fatal(err)
+		}
+		q := msg.Questions[0]
+		ip := [4]byte{5, 6, 7, 8}
+		if q.Name.String() == "same.example.com." {
+			ip = [4]byte{1, 2, 3, 4}
+		}
+		msg.Response = true
+		msg.Answers = []dnsmessage.Resource{{
+			Header: dnsmessage.ResourceHeader{Name: q.Name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET},
+			Body:   &dnsmessage.AResource{A: ip},
+		}}
+		resp, _ := msg.Pack()
+		w.Write(resp)
+	}))
+	defer srv.Close()
+
+	same := &Domain{DomainName: "example.com", SubDomain: "same"}
+	changed := &Domain{DomainName: "example.com", SubDomain: "changed"}
+	domains := &Domains{DohURL: srv.URL}
+	ip, result := domains.filterByDoH("1.2.3.4", "A", []*Domain{same, changed})
+	if ip != "1.2.3.4" || len(result) != 1 || result[0] != changed {
+		t.Fatalf("期待只更新 changed, 得到 %q %v", ip, result)
+	}
+	if same.UpdateStatus != UpdatedNothing || changed.UpdateStatus != "" {
+		t.Errorf("状态不正确: same %q, changed %q", same.UpdateStatus, changed.UpdateStatus)
+	}
+
+	// 全部无需更新时返回空IP
+	same.UpdateStatus = ""
+	ip, result = domains.filterByDoH("1.2.3.4", "A", []*Domain{same})
+	if ip != "" || len(result) != 1 || same.UpdateStatus != UpdatedNothing {
+		t.Errorf("期待全部未改变, 得到 %q %v %q", ip, result, same.UpdateStatus)
+	}
+}
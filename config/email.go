@@ -0,0 +1,148 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// Email 邮件通知
+type Email struct {
+	EmailHost string
+	// 为空时 TLS 默认465, STARTTLS 默认587
+	EmailPort     string
+	EmailUsername string
+	EmailPassword string
+	EmailFrom     string
+	// 收件人, 多个用英文逗号分隔
+	EmailTo string
+	// true 使用TLS(SSL)连接, false 使用STARTTLS
+	EmailTLS bool
+	// 触发条件, 同 WebhookTrigger
+	EmailTrigger string
+}
+
+// emailNotifier 邮件通知
+type emailNotifier struct {
+	Email
+}
+
+// Notify 通过邮件发送更新结果
+func (e emailNotifier) Notify(ctx context.Context, results []DomainResult) error {
+	now := time.Now()
+	status := getResultsStatus(results, "A")
+	if v6Status := getResultsStatus(results, "AAAA"); status == UpdatedNothing || v6Status == UpdatedFailed {
+		status = v6Status
+	}
+	subject := "ddns-go " + util.LogStr(string(status))
+	body := util.LogStr("时间: %s", now.Format("2006-01-02 15:04:05")) + "\n" + getNotifyText(results)
+	return e.send(ctx, subject, body, now)
+}
+
+// send 发送纯文本邮件
+func (e Email) send(ctx context.Context, subject, body string, date time.Time) error {
+	port := e.EmailPort
+	if port == "" {
+		port = "587"
+		if e.EmailTLS {
+			port = "465"
+		}
+	}
+	addr := net.JoinHostPort(e.EmailHost, port)
+	tlsConfig := &tls.Config{ServerName: e.EmailHost}
+
+	var conn net.Conn
+	var err error
+	if e.EmailTLS {
+		conn, err = (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, e.EmailHost)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if !e.EmailTLS {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err = c.StartTLS(tlsConfig); err != nil {
+				return err
+			}
+		}
+	}
+	if e.EmailUsername != "" {
+		// 未加密的连接 PlainAuth 会拒绝发送密码
+		if err = c.Auth(smtp.PlainAuth("", e.EmailUsername, e.EmailPassword, e.EmailHost)); err != nil {
+			return err
+		}
+	}
+
+	from := e.EmailFrom
+	if from == "" {
+		from = e.EmailUsername
+	}
+	var to []string
+	for _, rcpt := range strings.Split(e.EmailTo, ",") {
+		if rcpt = strings.TrimSpace(rcpt); rcpt != "" {
+			to = append(to, rcpt)
+		}
+	}
+	if err = c.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err = c.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(buildEmailMessage(from, to, subject, body, date)); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// buildEmailMessage 生成邮件内容, 正文使用base64编码
+func buildEmailMessage(from string, to []string, subject, body string, date time.Time) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(strings.ReplaceAll(body, "\n", "\r\n")))
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded + "\r\n")
+	return buf.Bytes()
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+	"gopkg.in/yaml.v3"
+)
+
+// envVarReg 配置中引用的环境变量, 如 ${CF_TOKEN}
+var envVarReg = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnv 将 ${NAME} 替换为环境变量的值, 其它内容不变
+func expandEnv(s string) string {
+	return envVarReg.ReplaceAllStringFunc(s, func(match string) string {
+		name := envVarReg.FindStringSubmatch(match)[1]
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			util.LogWarn("配置文件中引用的环境变量 %s 未设置", name)
+		}
+		return value
+	})
+}
+
+// secretFilePrefix 从文件读取值, 如 file:/run/secrets/cf_token
+const secretFilePrefix = "file:"
+
+// readSecretFile 读取文件内容, 去掉末尾的换行
+func readSecretFile(path string) string {
+	byt, err := os.ReadFile(path)
+	if err != nil {
+		util.LogError("读取配置文件中引用的文件失败: %s", err)
+		return ""
+	}
+	return strings.TrimRight(string(byt), "\r\n")
+}
+
+// expandRef 替换引用的环境变量或文件, 没有引用时返回false
+func expandRef(value string) (string, bool) {
+	if strings.HasPrefix(value, secretFilePrefix) {
+		return readSecretFile(strings.TrimPrefix(value, secretFilePrefix)), true
+	}
+	if envVarReg.MatchString(value) {
+		return expandEnv(value), true
+	}
+	return value, false
+}
+
+// 读取配置文件时引用了环境变量或文件的值, 路径→值, 需持有 cache.Lock
+var envRefs map[string]envRef
+
+// envRef 引用了环境变量或文件的值
+type envRef struct {
+	raw      string // 原始值, 如 ${CF_TOKEN}, file:/run/secrets/cf_token
+	expanded string // 替换后的值
+}
+
+// expandEnvNode 替换所有值中引用的环境变量或文件, 记录 路径→原始值, 用于保存时还原
+func expandEnvNode(node *yaml.Node, path string, refs map[string]envRef) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			expandEnvNode(child, path, refs)
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			expandEnvNode(child, path+"."+strconv.Itoa(i), refs)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			expandEnvNode(node.Content[i+1], path+"."+node.Content[i].Value, refs)
+		}
+	case yaml.ScalarNode:
+		expanded, ok := expandRef(node.Value)
+		if !ok {
+			return
+		}
+		ref := envRef{raw: node.Value, expanded: expanded}
+		refs[path] = ref
+		node.Value = ref.expanded
+		// 未加引号时按替换后的值推断类型, 如 true/300
+		if node.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) == 0 {
+			node.Tag = ""
+		}
+	}
+}
+
+// restoreEnvNode 值未修改时还原为 ${NAME} 或 file:路径, 避免将密钥保存到配置文件
+func restoreEnvNode(node *yaml.Node, path string, refs map[string]envRef) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			restoreEnvNode(child, path, refs)
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			restoreEnvNode(child, path+"."+strconv.Itoa(i), refs)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			restoreEnvNode(node.Content[i+1], path+"."+node.Content[i].Value, refs)
+		}
+	case yaml.ScalarNode:
+		ref, ok := refs[path]
+		if ok && node.Value == ref.expanded {
+			node.Value = ref.raw
+			node.Tag = "!!str"
+			node.Style = 0
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// TestExpandEnv 测试只替换 ${NAME}
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("DDNS_GO_TEST_TOKEN", "abc")
+	tests := map[string]string{
+		"${DDNS_GO_TEST_TOKEN}":         "abc",
+		"Bearer ${DDNS_GO_TEST_TOKEN}!": "Bearer abc!",
+		"$DDNS_GO_TEST_TOKEN":           "$DDNS_GO_TEST_TOKEN",
+		"pa$$word":                      "pa$$word",
+		"${DDNS_GO_TEST_NOT_SET}":       "",
+	}
+	for value, want := range tests {
+		if got := expandEnv(value); got != want {
+			t.Errorf("%q 期待 %q, 得到 %q", value, want, got)
+		}
+	}
+}
+
+// TestExpandRef 测试从文件读取值
+func TestExpandRef(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "cf_token")
+	if err := os.WriteFile(secretFile, []byte("abc\n\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		value string
+		want  string
+		ok    bool
+	}{
+		{"file:" + secretFile, "abc", true},
+		{"file:" + secretFile + ".not-exist", "", true},
+		{"abc", "abc", false},
+		{"https://file:8080", "https://file:8080", false},
+	}
+	for _, tt := range tests {
+		if got, ok := expandRef(tt.value); got != tt.want || ok != tt.ok {
+			t.Errorf("%q 期待 %q %v, 得到 %q %v", tt.value, tt.want, tt.ok, got, ok)
+		}
+	}
+}
+
+// TestConfigEnv 测试读取时替换环境变量和文件, 保存时未修改的值仍为 ${NAME} 或 file:路径
+func TestConfigEnv(t *testing.T) {
+	t.Setenv("DDNS_GO_TEST_TOKEN", "abc")
+	t.Setenv("DDNS_GO_TEST_PROXIED", "true")
+	configFilePath := filepath.Join(t.TempDir(), ".ddns_go_config.yaml")
+	t.Setenv(util.ConfigFilePathENV, configFilePath)
+	cache.ConfigSingle = nil
+	defer func() { cache.ConfigSingle = nil }()
+
+	idFile := filepath.Join(t.TempDir(), "id")
+	if err := os.WriteFile(idFile, []byte("id\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	err := os.WriteFile(configFilePath, []byte(`dnsconf:
+    - name: test
+      dns:
+        name: cloudflare
+        id: file:`+idFile+`
+        secret: ${DDNS_GO_TEST_TOKEN}
+      proxied: ${DDNS_GO_TEST_PROXIED}
+      ttl: "${DDNS_GO_TEST_TOKEN}"
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf, err := GetConfigCached()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dc := conf.DnsConf[0]
+	if dc.DNS.ID != "id" {
+		t.Errorf("从文件读取失败: %q", dc.DNS.ID)
+	}
+	if dc.DNS.Secret != "abc" || !dc.Proxied || dc.TTL != "abc" {
+		t.Fatalf("替换环境变量失败: %+v", dc)
+	}
+
+	conf.DnsConf[0].TTL = "600"
+	if err := conf.SaveConfig(); err != nil {
+		t.Fatal(err)
+	}
+	byt, _ := os.ReadFile(configFilePath)
+	saved := string(byt)
+	for _, want := range []string{"id: file:" + idFile, "secret: ${DDNS_GO_TEST_TOKEN}", "proxied: ${DDNS_GO_TEST_PROXIED}", `ttl: "600"`} {
+		if !strings.Contains(saved, want) {
+			t.Errorf("保存的配置中缺少 %s\n%s", want, saved)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"os"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// Hook 域名更新成功后运行的命令
+type Hook struct {
+	// 每个更新成功的域名运行一次, 通过环境变量
+	// DDNS_DOMAIN/DDNS_RECORD_TYPE/DDNS_OLD_IP/DDNS_NEW_IP 获得更新结果
+	HookCommand string
+}
+
+// ExecHook 对每个更新成功的域名运行命令, 未改变和失败的不运行
+func ExecHook(domains *Domains, conf *Config) {
+	if conf.HookCommand == "" {
+		return
+	}
+	for _, result := range GetDomainResults(domains) {
+		if result.Status == "success" {
+			runHook(conf.HookCommand, result)
+		}
+	}
+}
+
+// runHook 运行命令并记录输出, 超时后结束命令
+func runHook(cmd string, result DomainResult) {
+	ctx, cancel := context.WithTimeout(context.Background(), cmdTimeout)
+	defer cancel()
+
+	execCmd := newShellCmd(ctx, cmd)
+	execCmd.Env = append(os.Environ(),
+		"DDNS_DOMAIN="+result.Domain,
+		"DDNS_RECORD_TYPE="+result.RecordType,
+		"DDNS_OLD_IP="+result.OldIP,
+		"DDNS_NEW_IP="+result.NewIP,
+	)
+	var stdout, stderr bytes.Buffer
+	execCmd.Stdout = &stdout
+	execCmd.Stderr = &stderr
+	err := execCmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		util.LogError("域名 %s 更新后运行命令超过 %s, 已结束", result.Domain, cmdTimeout)
+		return
+	}
+	if err != nil {
+		util.LogError("域名 %s 更新后运行命令失败! 错误：%s, 输出：%q, 错误输出：%q", result.Domain, err, stdout.String(), stderr.String())
+		return
+	}
+	util.Log("域名 %s 更新后运行命令成功, 输出：%q, 错误输出：%q", result.Domain, stdout.String(), stderr.String())
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// TestExecHook 测试只对更新成功的域名运行命令
+func TestExecHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("命令使用 sh 语法")
+	}
+	out := filepath.Join(t.TempDir(), "hook.txt")
+	conf := &Config{Hook: Hook{HookCommand: `echo "$DDNS_RECORD_TYPE $DDNS_DOMAIN $DDNS_OLD_IP $DDNS_NEW_IP" >> ` + out}}
+	domains := &Domains{
+		Ipv4Addr:     "1.2.3.5",
+		Ipv4PrevAddr: "1.2.3.4",
+		Ipv4Domains: []*Domain{
+			{DomainName: "example.com", SubDomain: "www", UpdateStatus: UpdatedSuccess},
+			{DomainName: "example.com", SubDomain: "fail", UpdateStatus: UpdatedFailed},
+			{DomainName: "example.com", SubDomain: "same", UpdateStatus: UpdatedNothing},
+		},
+	}
+
+	ExecHook(domains, conf)
+
+	byt, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "A www.example.com 1.2.3.4 1.2.3.5\n"; string(byt) != want {
+		t.Errorf("期待 %q, 得到 %q", want, string(byt))
+	}
+}
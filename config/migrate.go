@@ -0,0 +1,74 @@
+package config
+
+import (
+	"github.com/jeessy2/ddns-go/v6/util"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigVersion 当前配置文件的版本
+//
+//	0: v5.0.0之前, 只有一个DNS配置
+//	1: 支持多个DNS配置, 没有 Version 字段
+//	2: 增加 Version 字段
+//	3: DNS配置增加 Enabled 字段
+//	4: Cloudflare增加 AuthMode 字段
+const ConfigVersion = 4
+
+// migrations[i] 将版本 i 的配置升级到版本 i+1, byt 为配置文件的内容
+var migrations = []func(conf *Config, byt []byte){
+	migrateV0,
+	migrateV1,
+	migrateV2,
+	migrateV3,
+}
+
+// migrate 将旧版本的配置升级到当前版本, 返回是否有升级
+func (conf *Config) migrate(byt []byte) bool {
+	version := conf.Version
+	if version == 0 && len(conf.DnsConf) > 0 {
+		version = 1
+	}
+	if version >= ConfigVersion {
+		return false
+	}
+	for v := version; v < ConfigVersion; v++ {
+		migrations[v](conf, byt)
+	}
+	conf.Version = ConfigVersion
+	util.Log("配置文件已从版本 %d 升级到 %d", version, ConfigVersion)
+	return true
+}
+
+// migrateV0 兼容v5.0.0之前的配置文件, DNS配置在顶层
+func migrateV0(conf *Config, byt []byte) {
+	dnsConf := DnsConfig{}
+	if err := yaml.Unmarshal(byt, &dnsConf); err != nil {
+		return
+	}
+	if dnsConf.DNS.Name != "" {
+		conf.DnsConf = append(conf.DnsConf, dnsConf)
+	}
+}
+
+// migrateV1 之前的配置文件没有语言, 默认为中文
+func migrateV1(conf *Config, byt []byte) {
+	if conf.Lang == "" {
+		conf.Lang = "zh"
+	}
+}
+
+// migrateV2 之前的DNS配置没有启用开关, 全部启用
+func migrateV2(conf *Config, byt []byte) {
+	for i := range conf.DnsConf {
+		conf.DnsConf[i].Enabled = true
+	}
+}
+
+// migrateV3 之前的Cloudflare配置只支持API令牌, 保持使用令牌认证
+func migrateV3(conf *Config, byt []byte) {
+	for i := range conf.DnsConf {
+		if conf.DnsConf[i].DNS.Name == "cloudflare" && conf.DnsConf[i].DNS.AuthMode == "" {
+			conf.DnsConf[i].DNS.AuthMode = "token"
+		}
+	}
+}
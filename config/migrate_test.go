@@ -0,0 +1,106 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// loadTestConfig 写入配置文件并读取
+func loadTestConfig(t *testing.T, content string) (Config, string) {
+	configFilePath := filepath.Join(t.TempDir(), ".ddns_go_config.yaml")
+	t.Setenv(util.ConfigFilePathENV, configFilePath)
+	cache.ConfigSingle = nil
+	t.Cleanup(func() { cache.ConfigSingle = nil })
+
+	if err := os.WriteFile(configFilePath, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	conf, err := GetConfigCached()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conf, configFilePath
+}
+
+// TestMigrateV1 测试升级没有 Version 字段的配置文件并写回
+func TestMigrateV1(t *testing.T) {
+	conf, configFilePath := loadTestConfig(t, `dnsconf:
+    - name: test
+      ipv4:
+        enable: true
+        domains:
+            - www.example.com
+      dns:
+        name: cloudflare
+        secret: abc
+user:
+    username: admin
+    password: "123456"
+`)
+
+	if conf.Version != ConfigVersion || conf.Lang != "zh" || !util.IsHashedPassword(conf.Password) {
+		t.Errorf("升级失败: Version %d, Lang %q, Password %q", conf.Version, conf.Lang, conf.Password)
+	}
+	if len(conf.DnsConf) != 1 || !conf.DnsConf[0].Enabled || conf.DnsConf[0].DNS.Secret != "abc" || conf.DnsConf[0].Ipv4.Domains[0] != "www.example.com" {
+		t.Errorf("DNS配置不正确: %+v", conf.DnsConf)
+	}
+
+	byt, _ := os.ReadFile(configFilePath)
+	saved := string(byt)
+	for _, want := range []string{"version: 4", "lang: zh", "enabled: true", "secret: abc", "authmode: token"} {
+		if !strings.Contains(saved, want) {
+			t.Errorf("写回的配置中缺少 %s\n%s", want, saved)
+		}
+	}
+	if strings.Contains(saved, "123456") {
+		t.Errorf("密码未加密\n%s", saved)
+	}
+}
+
+// TestMigrateV0 测试升级v5.0.0之前的配置文件
+func TestMigrateV0(t *testing.T) {
+	conf, _ := loadTestConfig(t, `ipv4:
+    enable: true
+    domains:
+        - www.example.com
+dns:
+    name: alidns
+    id: id
+`)
+
+	if conf.Version != ConfigVersion || len(conf.DnsConf) != 1 || !conf.DnsConf[0].Enabled || conf.DnsConf[0].DNS.Name != "alidns" ||
+		conf.DnsConf[0].Ipv4.Domains[0] != "www.example.com" {
+		t.Errorf("升级失败: %+v", conf)
+	}
+}
+
+// TestMigrateV2 测试之前的DNS配置全部启用
+func TestMigrateV2(t *testing.T) {
+	conf := Config{Version: 2, DnsConf: []DnsConfig{{Name: "a"}, {Name: "b"}}}
+	if !conf.migrate(nil) || !conf.DnsConf[0].Enabled || !conf.DnsConf[1].Enabled {
+		t.Errorf("升级失败: %+v", conf.DnsConf)
+	}
+}
+
+// TestMigrateV3 测试之前的Cloudflare配置使用令牌认证, 即使填写了ID
+func TestMigrateV3(t *testing.T) {
+	conf := Config{Version: 3, DnsConf: []DnsConfig{
+		{DNS: DNS{Name: "cloudflare", ID: "left@example.com"}},
+		{DNS: DNS{Name: "alidns", ID: "id"}},
+	}}
+	if !conf.migrate(nil) || conf.DnsConf[0].DNS.AuthMode != "token" || conf.DnsConf[1].DNS.AuthMode != "" {
+		t.Errorf("升级失败: %+v", conf.DnsConf)
+	}
+}
+
+// TestMigrateCurrent 当前版本的配置不需要升级
+func TestMigrateCurrent(t *testing.T) {
+	conf := Config{Version: ConfigVersion, DnsConf: []DnsConfig{{Name: "test"}}}
+	if conf.migrate(nil) || conf.Lang != "" {
+		t.Error("不应升级")
+	}
+}
@@ -1,8 +1,12 @@
 package config
 
 import (
+	"encoding/hex"
 	"fmt"
 	"net"
+	"os"
+	"strconv"
+	"strings"
 )
 
 // NetInterface 本机网络
@@ -67,3 +71,59 @@ func GetNetInterface() (ipv4NetInterfaces []NetInterface, ipv6NetInterfaces []Ne
 
 	return ipv4NetInterfaces, ipv6NetInterfaces, nil
 }
+
+// IPv6地址标志, 见 linux/if_addr.h
+const (
+	ifaFlagTemporary  = 0x01
+	ifaFlagDeprecated = 0x20
+)
+
+// ipv6AddrFlagsFile Linux下记录IPv6地址标志的文件
+var ipv6AddrFlagsFile = "/proc/net/if_inet6"
+
+// getIpv6AddrFlags 获得IPv6地址的标志, 仅支持Linux, 其它系统返回空
+func getIpv6AddrFlags() map[string]int {
+	flags := map[string]int{}
+	byt, err := os.ReadFile(ipv6AddrFlagsFile)
+	if err != nil {
+		return flags
+	}
+	// 格式: 地址 网卡序号 前缀长度 范围 标志 网卡名
+	for _, line := range strings.Split(string(byt), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 6 || len(fields[0]) != 32 {
+			continue
+		}
+		addr, err := hex.DecodeString(fields[0])
+		if err != nil {
+			continue
+		}
+		flag, err := strconv.ParseInt(fields[4], 16, 32)
+		if err != nil {
+			continue
+		}
+		flags[net.IP(addr).String()] = int(flag)
+	}
+	return flags
+}
+
+// sortIpv6Addrs 排除已弃用的地址, 默认稳定地址在前, preferTemporary 时临时地址在前
+// 都已弃用时返回原地址
+func sortIpv6Addrs(addrs []string, flags map[string]int, preferTemporary bool) []string {
+	var preferred, others []string
+	for _, addr := range addrs {
+		flag := flags[addr]
+		if flag&ifaFlagDeprecated != 0 {
+			continue
+		}
+		if (flag&ifaFlagTemporary != 0) == preferTemporary {
+			preferred = append(preferred, addr)
+		} else {
+			others = append(others, addr)
+		}
+	}
+	if len(preferred)+len(others) == 0 {
+		return addrs
+	}
+	return append(preferred, others...)
+}
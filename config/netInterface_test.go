@@ -1,6 +1,7 @@
 package config
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -11,3 +12,24 @@ func TestGetNetInterface(t *testing.T) {
 	}
 	t.Log(ipv4NetInterfaces, ipv6NetInterfaces)
 }
+
+func TestSortIpv6Addrs(t *testing.T) {
+	addrs := []string{"2001:db8::1", "2001:db8::2", "2001:db8::3"}
+	flags := map[string]int{
+		"2001:db8::1": ifaFlagTemporary,
+		"2001:db8::2": ifaFlagDeprecated,
+	}
+
+	if got, want := sortIpv6Addrs(addrs, flags, false), []string{"2001:db8::3", "2001:db8::1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("stable: got %v, want %v", got, want)
+	}
+	if got, want := sortIpv6Addrs(addrs, flags, true), []string{"2001:db8::1", "2001:db8::3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("temporary: got %v, want %v", got, want)
+	}
+
+	// 都已弃用时使用原地址
+	deprecated := map[string]int{"2001:db8::1": ifaFlagDeprecated}
+	if got := sortIpv6Addrs(addrs[:1], deprecated, false); !reflect.DeepEqual(got, addrs[:1]) {
+		t.Errorf("all deprecated: got %v", got)
+	}
+}
@@ -0,0 +1,337 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// 发送通知的超时时间
+const notifyTimeout = 30 * time.Second
+
+// Notifier 通知方式
+type Notifier interface {
+	// Notify 发送所有域名的更新结果
+	Notify(ctx context.Context, results []DomainResult) error
+}
+
+// DomainResult 单个域名的更新结果
+type DomainResult struct {
+	Domain     string `json:"domain"`
+	RecordType string `json:"recordType"`
+	OldIP      string `json:"oldIP"`
+	// NewIP CNAME记录为解析目标
+	NewIP string `json:"newIP"`
+	// Status success/failed/nothing
+	Status string `json:"status"`
+}
+
+// updateStatus 转换为 updateStatusType
+func (r DomainResult) updateStatus() updateStatusType {
+	switch r.Status {
+	case "success":
+		return UpdatedSuccess
+	case "failed":
+		return UpdatedFailed
+	default:
+		return UpdatedNothing
+	}
+}
+
+// notifierEntry 注册的通知方式
+type notifierEntry struct {
+	name string
+	// newNotifier 根据配置创建通知方式及其触发条件, 未启用时返回nil
+	newNotifier func(conf *Config) (Notifier, string)
+	// 连续更新失败的次数
+	failedTimes int
+	cooldown    notifyCooldown
+}
+
+// notifyCooldown 通知冷却, 通知后同一域名在冷却时间内的变化不再通知, 冷却结束后通知最新的结果
+type notifyCooldown struct {
+	// 域名最后一次通知成功的时间
+	lastTimes map[string]time.Time
+	// 冷却时间内未通知的最新结果
+	pending map[string]DomainResult
+}
+
+// filter 去掉冷却时间内的成功结果, 冷却结束且未改变时换为之前未通知的结果, 失败的结果总是通知
+func (c *notifyCooldown) filter(results []DomainResult, cooldown time.Duration, now time.Time) []DomainResult {
+	if cooldown <= 0 {
+		return results
+	}
+	if c.lastTimes == nil {
+		c.lastTimes = map[string]time.Time{}
+		c.pending = map[string]DomainResult{}
+	}
+
+	filtered := make([]DomainResult, 0, len(results))
+	for _, result := range results {
+		key := result.RecordType + " " + result.Domain
+		pending, hasPending := c.pending[key]
+		inCooldown := now.Sub(c.lastTimes[key]) < cooldown
+		switch result.updateStatus() {
+		case UpdatedSuccess:
+			// 从最后一次通知的IP开始计算变化
+			if hasPending {
+				result.OldIP = pending.OldIP
+			}
+			if inCooldown {
+				if result.OldIP == result.NewIP {
+					// 已变回最后一次通知的IP
+					delete(c.pending, key)
+				} else {
+					c.pending[key] = result
+				}
+				util.LogDebug("域名 %s 在通知冷却时间内, 暂不通知", result.Domain)
+				continue
+			}
+			// 通知成功后才删除, 发送失败时下次重新通知
+			c.pending[key] = result
+		case UpdatedNothing:
+			if hasPending && !inCooldown {
+				result = pending
+			}
+		}
+		filtered = append(filtered, result)
+	}
+	return filtered
+}
+
+// notified 记录通知成功的域名的通知时间, 删除已通知的结果
+func (c *notifyCooldown) notified(results []DomainResult, now time.Time) {
+	if c.lastTimes == nil {
+		return
+	}
+	for _, result := range results {
+		if result.updateStatus() == UpdatedSuccess {
+			key := result.RecordType + " " + result.Domain
+			c.lastTimes[key] = now
+			delete(c.pending, key)
+		}
+	}
+}
+
+var (
+	notifiers     []*notifierEntry
+	notifiersLock sync.Mutex
+)
+
+// RegisterNotifier 注册通知方式, name 用于日志
+func RegisterNotifier(name string, newNotifier func(conf *Config) (n Notifier, trigger string)) {
+	notifiersLock.Lock()
+	defer notifiersLock.Unlock()
+	notifiers = append(notifiers, &notifierEntry{name: name, newNotifier: newNotifier})
+}
+
+func init() {
+	RegisterNotifier("Webhook", func(conf *Config) (Notifier, string) {
+		if conf.WebhookURL == "" {
+			return nil, ""
+		}
+		return webhookNotifier{conf.Webhook}, conf.WebhookTrigger
+	})
+	RegisterNotifier("Telegram", func(conf *Config) (Notifier, string) {
+		if conf.TelegramBotToken == "" || conf.TelegramChatID == "" {
+			return nil, ""
+		}
+		return telegramNotifier{conf.Telegram}, conf.TelegramTrigger
+	})
+	RegisterNotifier("Bark", func(conf *Config) (Notifier, string) {
+		if conf.BarkURL == "" {
+			return nil, ""
+		}
+		return barkNotifier{conf.Bark}, conf.BarkTrigger
+	})
+	RegisterNotifier("Discord", func(conf *Config) (Notifier, string) {
+		if conf.DiscordURL == "" {
+			return nil, ""
+		}
+		return discordNotifier{conf.Discord}, conf.DiscordTrigger
+	})
+	RegisterNotifier("Slack", func(conf *Config) (Notifier, string) {
+		if conf.SlackURL == "" {
+			return nil, ""
+		}
+		return slackNotifier{conf.Slack}, conf.SlackTrigger
+	})
+	RegisterNotifier("邮件", func(conf *Config) (Notifier, string) {
+		if conf.EmailHost == "" || conf.EmailTo == "" {
+			return nil, ""
+		}
+		return emailNotifier{conf.Email}, conf.EmailTrigger
+	})
+}
+
+// ExecNotify 按触发条件发送所有已启用的通知, 返回IPv4/IPv6的更新状态
+func ExecNotify(domains *Domains, conf *Config) (v4Status updateStatusType, v6Status updateStatusType) {
+	v4Status = getDomainsStatus(domains.Ipv4Domains)
+	v6Status = getDomainsStatus(domains.Ipv6Domains)
+	results := GetDomainResults(domains)
+	cooldown := conf.GetNotifyCooldown()
+	now := time.Now()
+
+	notifiersLock.Lock()
+	defer notifiersLock.Unlock()
+	for _, entry := range notifiers {
+		n, trigger := entry.newNotifier(conf)
+		if n == nil {
+			continue
+		}
+		entryResults := entry.cooldown.filter(results, cooldown, now)
+		entryV4Status := getResultsStatus(entryResults, "A")
+		entryV6Status := getResultsStatus(entryResults, "AAAA")
+		if !shouldNotify(trigger, entryV4Status, entryV6Status) ||
+			!checkFailedTimes(&entry.failedTimes, util.LogStr(entry.name), entryV4Status, entryV6Status) {
+			continue
+		}
+		if SendNotify(entry.name, n, entryResults) == nil {
+			entry.cooldown.notified(entryResults, now)
+		}
+	}
+	return
+}
+
+// SendNotify 发送一次通知并记录结果, 返回发送的异常
+func SendNotify(name string, n Notifier, results []DomainResult) error {
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+	if err := n.Notify(ctx, results); err != nil {
+		util.LogError("%s通知发送失败! 异常信息：%s", util.LogStr(name), err)
+		return err
+	}
+	util.Log("%s通知发送成功", util.LogStr(name))
+	return nil
+}
+
+// checkFailedTimes 连续失败时只在第3次失败时通知一次, 返回是否需要通知
+func checkFailedTimes(failedTimes *int, name string, v4Status updateStatusType, v6Status updateStatusType) bool {
+	if v4Status == UpdatedFailed || v6Status == UpdatedFailed {
+		*failedTimes++
+		if *failedTimes != 3 {
+			util.LogDebug("将不会发送%s通知, 仅在第 3 次失败时发送一次, 当前失败次数：%d", name, *failedTimes)
+			return false
+		}
+		return true
+	}
+	*failedTimes = 0
+	return true
+}
+
+// shouldNotify 根据触发条件判断是否需要通知
+func shouldNotify(trigger string, v4Status updateStatusType, v6Status updateStatusType) bool {
+	switch trigger {
+	case TriggerAlways:
+		return true
+	case TriggerOnFailure:
+		return v4Status == UpdatedFailed || v6Status == UpdatedFailed
+	default:
+		return v4Status != UpdatedNothing || v6Status != UpdatedNothing
+	}
+}
+
+// GetDomainResults 获得所有域名的更新结果
+func GetDomainResults(domains *Domains) (results []DomainResult) {
+	add := func(list []*Domain, recordType, oldIP, newIP string) {
+		for _, domain := range list {
+			result := DomainResult{
+				Domain:     domain.String(),
+				RecordType: recordType,
+				OldIP:      oldIP,
+				NewIP:      newIP,
+				Status:     "nothing",
+			}
+			switch recordType {
+			case "CNAME":
+				result.NewIP = domain.Target
+			case "MX":
+				result.NewIP = fmt.Sprintf("%d %s", domain.Priority, domain.Target)
+			}
+			switch domain.UpdateStatus {
+			case UpdatedSuccess:
+				result.Status = "success"
+			case UpdatedFailed:
+				result.Status = "failed"
+			}
+			results = append(results, result)
+		}
+	}
+	add(domains.Ipv4Domains, "A", domains.Ipv4PrevAddr, domains.Ipv4Addr)
+	add(domains.Ipv6Domains, "AAAA", domains.Ipv6PrevAddr, domains.Ipv6Addr)
+	add(domains.CnameDomains, "CNAME", "", "")
+	add(domains.MxDomains, "MX", "", "")
+	return
+}
+
+// getResultsStatus 获得某种记录的更新状态, 一个失败则失败, 一个成功则成功
+func getResultsStatus(results []DomainResult, recordType string) updateStatusType {
+	status := UpdatedNothing
+	for _, result := range results {
+		if result.RecordType != recordType {
+			continue
+		}
+		switch result.updateStatus() {
+		case UpdatedFailed:
+			return UpdatedFailed
+		case UpdatedSuccess:
+			status = UpdatedSuccess
+		}
+	}
+	return status
+}
+
+// getStatusColor 通知消息中更新结果的颜色, 成功为绿色, 失败为红色, 未改变为灰色
+func getStatusColor(status updateStatusType) int {
+	switch status {
+	case UpdatedSuccess:
+		return 0x2ecc71
+	case UpdatedFailed:
+		return 0xe74c3c
+	default:
+		return 0x95a5a6
+	}
+}
+
+// getResultsJSON 所有域名的更新结果, JSON数组
+func getResultsJSON(results []DomainResult) string {
+	if results == nil {
+		return "[]"
+	}
+	byt, _ := json.Marshal(results)
+	return string(byt)
+}
+
+// getNotifyResults 获得需要通知的结果, 只包含有变化的域名, 都没有变化时返回全部域名
+func getNotifyResults(results []DomainResult) []DomainResult {
+	var changed []DomainResult
+	for _, result := range results {
+		if result.updateStatus() != UpdatedNothing {
+			changed = append(changed, result)
+		}
+	}
+	if len(changed) > 0 {
+		return changed
+	}
+	return results
+}
+
+// getNotifyText 获得通知内容, 每行一个有变化的域名, 都没有变化时列出全部域名
+func getNotifyText(results []DomainResult) string {
+	results = getNotifyResults(results)
+	lines := make([]string, 0, len(results))
+	for _, result := range results {
+		oldIP := result.OldIP
+		if oldIP == "" {
+			oldIP = "-"
+		}
+		lines = append(lines, fmt.Sprintf("%s %s: %s → %s %s",
+			result.Domain, result.RecordType, oldIP, result.NewIP, util.LogStr(string(result.updateStatus()))))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,296 @@
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestCheckFailedTimes 测试连续失败时只在第3次通知
+func TestCheckFailedTimes(t *testing.T) {
+	times := 0
+	want := []bool{false, false, true, false}
+	for i, w := range want {
+		if got := checkFailedTimes(&times, "test", UpdatedFailed, UpdatedNothing); got != w {
+			t.Errorf("第 %d 次失败期待 %v, 得到 %v", i+1, w, got)
+		}
+	}
+	if !checkFailedTimes(&times, "test", UpdatedSuccess, UpdatedNothing) || times != 0 {
+		t.Errorf("成功后应通知并重置失败次数, 当前失败次数 %d", times)
+	}
+}
+
+// TestNotifyCooldown 测试冷却时间内不通知成功的变化, 结束后通知最新的结果, 失败总是通知
+func TestNotifyCooldown(t *testing.T) {
+	var c notifyCooldown
+	start := time.Now()
+	result := func(oldIP, newIP, status string) []DomainResult {
+		return []DomainResult{{Domain: "example.com", RecordType: "A", OldIP: oldIP, NewIP: newIP, Status: status}}
+	}
+
+	sent := c.filter(result("1.1.1.1", "2.2.2.2", "success"), 10*time.Minute, start)
+	if len(sent) != 1 {
+		t.Fatalf("首次变化应通知, 得到 %v", sent)
+	}
+	c.notified(sent, start)
+
+	// 冷却时间内的变化不通知, 失败总是通知
+	if sent := c.filter(result("2.2.2.2", "3.3.3.3", "success"), 10*time.Minute, start.Add(time.Minute)); len(sent) != 0 {
+		t.Errorf("冷却时间内不应通知, 得到 %v", sent)
+	}
+	if sent := c.filter(result("3.3.3.3", "4.4.4.4", "failed"), 10*time.Minute, start.Add(2*time.Minute)); len(sent) != 1 {
+		t.Errorf("失败应通知, 得到 %v", sent)
+	}
+	if sent := c.filter(result("3.3.3.3", "4.4.4.4", "success"), 10*time.Minute, start.Add(3*time.Minute)); len(sent) != 0 {
+		t.Errorf("冷却时间内不应通知, 得到 %v", sent)
+	}
+
+	// 冷却结束后通知从最后一次通知的IP开始的变化
+	sent = c.filter(result("4.4.4.4", "4.4.4.4", "nothing"), 10*time.Minute, start.Add(11*time.Minute))
+	want := result("2.2.2.2", "4.4.4.4", "success")
+	if !reflect.DeepEqual(sent, want) {
+		t.Errorf("期待 %v, 得到 %v", want, sent)
+	}
+	c.notified(sent, start.Add(11*time.Minute))
+
+	// 冷却时间内变回最后一次通知的IP, 之后不需要通知
+	c.filter(result("4.4.4.4", "5.5.5.5", "success"), 10*time.Minute, start.Add(12*time.Minute))
+	c.filter(result("5.5.5.5", "4.4.4.4", "success"), 10*time.Minute, start.Add(13*time.Minute))
+	sent = c.filter(result("4.4.4.4", "4.4.4.4", "nothing"), 10*time.Minute, start.Add(22*time.Minute))
+	if len(sent) != 1 || sent[0].Status != "nothing" {
+		t.Errorf("期待未改变, 得到 %v", sent)
+	}
+
+	// 未设置冷却时间时原样返回
+	if sent := c.filter(result("4.4.4.4", "6.6.6.6", "success"), 0, start.Add(23*time.Minute)); len(sent) != 1 {
+		t.Errorf("未设置冷却时间应通知, 得到 %v", sent)
+	}
+}
+
+// TestGetNotifyText 测试只列出有变化的域名
+func TestGetNotifyText(t *testing.T) {
+	domains := &Domains{
+		Ipv4Addr:     "1.2.3.5",
+		Ipv4PrevAddr: "1.2.3.4",
+		Ipv4Domains: []*Domain{
+			{DomainName: "example.com", SubDomain: "www", UpdateStatus: UpdatedSuccess},
+			{DomainName: "example.com", UpdateStatus: UpdatedNothing},
+		},
+		Ipv6Addr: "::1",
+		Ipv6Domains: []*Domain{
+			{DomainName: "example.com", SubDomain: "v6", UpdateStatus: UpdatedFailed},
+		},
+	}
+	expected := "www.example.com A: 1.2.3.4 → 1.2.3.5 success\nv6.example.com AAAA: - → ::1 failed"
+	if got := getNotifyText(GetDomainResults(domains)); got != expected {
+		t.Errorf("期待 %q, 得到 %q", expected, got)
+	}
+
+	// 都没有变化时列出全部域名
+	domains.Ipv4Domains[0].UpdateStatus = UpdatedNothing
+	domains.Ipv6Domains = nil
+	expected = "www.example.com A: 1.2.3.4 → 1.2.3.5 no changed\nexample.com A: 1.2.3.4 → 1.2.3.5 no changed"
+	if got := getNotifyText(GetDomainResults(domains)); got != expected {
+		t.Errorf("期待 %q, 得到 %q", expected, got)
+	}
+}
+
+// failingNotifier 前几次发送失败的通知方式
+type failingNotifier struct {
+	failures int
+	sent     [][]DomainResult
+}
+
+func (n *failingNotifier) Notify(ctx context.Context, results []DomainResult) error {
+	if n.failures > 0 {
+		n.failures--
+		return errors.New("network error")
+	}
+	n.sent = append(n.sent, results)
+	return nil
+}
+
+// TestExecNotifyCooldownFailed 测试发送失败时不开始冷却, 下次重新通知
+func TestExecNotifyCooldownFailed(t *testing.T) {
+	n := &failingNotifier{failures: 1}
+	oldNotifiers := notifiers
+	notifiers = []*notifierEntry{{name: "test", newNotifier: func(conf *Config) (Notifier, string) { return n, "" }}}
+	defer func() { notifiers = oldNotifiers }()
+
+	conf := &Config{NotifyCooldown: "10"}
+	changed := &Domains{Ipv4PrevAddr: "1.1.1.1", Ipv4Addr: "2.2.2.2", Ipv4Domains: []*Domain{{DomainName: "example.com", UpdateStatus: UpdatedSuccess}}}
+	ExecNotify(changed, conf)
+	if len(n.sent) != 0 {
+		t.Fatalf("第一次应发送失败, 得到 %v", n.sent)
+	}
+
+	// IP未改变时重新通知之前发送失败的变化
+	unchanged := &Domains{Ipv4PrevAddr: "2.2.2.2", Ipv4Addr: "2.2.2.2", Ipv4Domains: []*Domain{{DomainName: "example.com", UpdateStatus: UpdatedNothing}}}
+	ExecNotify(unchanged, conf)
+	want := [][]DomainResult{{{Domain: "example.com", RecordType: "A", OldIP: "1.1.1.1", NewIP: "2.2.2.2", Status: "success"}}}
+	if !reflect.DeepEqual(n.sent, want) {
+		t.Fatalf("期待 %v, 得到 %v", want, n.sent)
+	}
+
+	// 发送成功后开始冷却
+	ExecNotify(&Domains{Ipv4PrevAddr: "2.2.2.2", Ipv4Addr: "3.3.3.3", Ipv4Domains: []*Domain{{DomainName: "example.com", UpdateStatus: UpdatedSuccess}}}, conf)
+	if len(n.sent) != 1 {
+		t.Errorf("冷却时间内不应通知, 得到 %v", n.sent)
+	}
+}
+
+// TestExecBark 测试Bark推送的参数
+func TestExecBark(t *testing.T) {
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/key" {
+			t.Errorf("推送地址不正确: %s", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"code":200,"message":"success"}`))
+	}))
+	defer srv.Close()
+
+	n := barkNotifier{Bark{BarkURL: srv.URL + "/key/", BarkSound: "minuet"}}
+	results := []DomainResult{{Domain: "example.com", RecordType: "A", NewIP: "1.2.3.4", Status: "success"}}
+	if err := n.Notify(context.Background(), results); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"title": "ddns-go",
+		"body":  "example.com A: - → 1.2.3.4 success",
+		"sound": "minuet",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("期待 %v, 得到 %v", want, got)
+	}
+	// 推送失败时异常信息中不包含Key
+	srv.Close()
+	n.BarkURL = srv.URL + "/secretkey"
+	if err := n.Notify(context.Background(), results); err == nil || strings.Contains(err.Error(), "secretkey") {
+		t.Errorf("期待不包含地址的错误, 得到 %v", err)
+	}
+}
+
+// TestBuildEmailMessage 测试邮件内容
+func TestBuildEmailMessage(t *testing.T) {
+	date := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	msg := string(buildEmailMessage("a@example.com", []string{"b@example.com", "c@example.com"}, "ddns-go 成功", "第一行\n第二行", date))
+
+	for _, header := range []string{
+		"From: a@example.com\r\n",
+		"To: b@example.com, c@example.com\r\n",
+		"Subject: =?UTF-8?b?ZGRucy1nbyDmiJDlip8=?=\r\n",
+		"Date: Tue, 02 Jan 2024 03:04:05 +0000\r\n",
+		"Content-Transfer-Encoding: base64\r\n\r\n",
+	} {
+		if !strings.Contains(msg, header) {
+			t.Errorf("缺少 %q, 邮件内容: %s", header, msg)
+		}
+	}
+
+	body := msg[strings.Index(msg, "\r\n\r\n")+4:]
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(body, "\r\n", ""))
+	if err != nil || string(decoded) != "第一行\r\n第二行" {
+		t.Errorf("正文不正确: %q, %v", decoded, err)
+	}
+}
+
+// TestExecDiscord 测试Discord消息的embed, 超过10个域名时分多条发送
+func TestExecDiscord(t *testing.T) {
+	var got []map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		got = append(got, body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	results := []DomainResult{
+		{Domain: "www.example.com", RecordType: "A", OldIP: "1.2.3.4", NewIP: "1.2.3.5", Status: "success"},
+		{Domain: "v6.example.com", RecordType: "AAAA", NewIP: "::1", Status: "failed"},
+		{Domain: "example.com", RecordType: "A", OldIP: "1.2.3.4", NewIP: "1.2.3.5", Status: "nothing"},
+	}
+	n := discordNotifier{Discord{DiscordURL: srv.URL}}
+	if err := n.Notify(context.Background(), results); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("期待 1 条消息, 得到 %d 条", len(got))
+	}
+	byt, _ := json.Marshal(got[0]["embeds"])
+	want := `[{"color":3066993,"fields":[{"inline":true,"name":"IP","value":"1.2.3.4 → 1.2.3.5"},{"inline":true,"name":"Status","value":"success"}],"title":"www.example.com A"},` +
+		`{"color":15158332,"fields":[{"inline":true,"name":"IP","value":"- → ::1"},{"inline":true,"name":"Status","value":"failed"}],"title":"v6.example.com AAAA"}]`
+	if string(byt) != want {
+		t.Errorf("期待 %s, 得到 %s", want, byt)
+	}
+
+	got = nil
+	results = nil
+	for i := 0; i < 12; i++ {
+		results = append(results, DomainResult{Domain: "example.com", RecordType: "A", NewIP: "1.2.3.4", Status: "success"})
+	}
+	if err := n.Notify(context.Background(), results); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Errorf("期待 2 条消息, 得到 %d 条", len(got))
+	}
+
+	n = discordNotifier{Discord{DiscordURL: srv.URL + "/missing?token"}}
+	srv.Config.Handler = http.NotFoundHandler()
+	if err := n.Notify(context.Background(), results); err == nil || strings.Contains(err.Error(), "token") {
+		t.Errorf("期待不包含地址的错误, 得到 %v", err)
+	}
+}
+
+// TestExecSlack 测试Slack消息的统计、频道及attachment
+func TestExecSlack(t *testing.T) {
+	var got map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	results := []DomainResult{
+		{Domain: "www.example.com", RecordType: "A", OldIP: "1.2.3.4", NewIP: "1.2.3.5", Status: "success"},
+		{Domain: "v6.example.com", RecordType: "AAAA", NewIP: "::1", Status: "failed"},
+		{Domain: "example.com", RecordType: "A", OldIP: "1.2.3.4", NewIP: "1.2.3.5", Status: "nothing"},
+	}
+	n := slackNotifier{Slack{SlackURL: srv.URL, SlackChannel: "#ddns"}}
+	if err := n.Notify(context.Background(), results); err != nil {
+		t.Fatal(err)
+	}
+	if got["channel"] != "#ddns" {
+		t.Errorf("期待频道 #ddns, 得到 %v", got["channel"])
+	}
+	if text := got["text"].(string); !strings.Contains(text, "1") {
+		t.Errorf("期待包含统计, 得到 %s", text)
+	}
+	byt, _ := json.Marshal(got["attachments"])
+	want := `[{"color":"#2ecc71","fallback":"www.example.com A: 1.2.3.4 → 1.2.3.5 success","fields":[{"short":true,"title":"IP","value":"1.2.3.4 → 1.2.3.5"},{"short":true,"title":"Status","value":"success"}],"title":"www.example.com A"},` +
+		`{"color":"#e74c3c","fallback":"v6.example.com AAAA: - → ::1 failed","fields":[{"short":true,"title":"IP","value":"- → ::1"},{"short":true,"title":"Status","value":"failed"}],"title":"v6.example.com AAAA"}]`
+	if string(byt) != want {
+		t.Errorf("期待 %s, 得到 %s", want, byt)
+	}
+
+	// 未填写频道时不发送channel
+	got = nil
+	n.SlackChannel = ""
+	if err := n.Notify(context.Background(), results); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := got["channel"]; ok {
+		t.Errorf("期待不包含频道, 得到 %v", got["channel"])
+	}
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// UpdateResult 更新结果统计, 未处理的域名计为未改变
+type UpdateResult struct {
+	Success int
+	Failed  int
+	Nothing int
+	// FailedDomains 更新失败的域名
+	FailedDomains []string
+}
+
+// Result 统计IPv4/IPv6/CNAME/MX/TXT记录的更新结果
+func (domains *Domains) Result() (result UpdateResult) {
+	for _, list := range [][]*Domain{domains.Ipv4Domains, domains.Ipv6Domains, domains.CnameDomains, domains.MxDomains, domains.TxtDomains} {
+		for _, domain := range list {
+			switch domain.UpdateStatus {
+			case UpdatedSuccess:
+				result.Success++
+			case UpdatedFailed:
+				result.Failed++
+				result.FailedDomains = append(result.FailedDomains, domain.String())
+			default:
+				result.Nothing++
+			}
+		}
+	}
+	return
+}
+
+// Add 合并其它配置的更新结果
+func (r *UpdateResult) Add(other UpdateResult) {
+	r.Success += other.Success
+	r.Failed += other.Failed
+	r.Nothing += other.Nothing
+	r.FailedDomains = append(r.FailedDomains, other.FailedDomains...)
+}
+
+// Err 有更新失败的域名时返回错误
+func (r UpdateResult) Err() error {
+	if r.Failed == 0 {
+		return nil
+	}
+	return errors.New(util.LogStr("更新失败的域名: %s", strings.Join(r.FailedDomains, ", ")))
+}
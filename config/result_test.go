@@ -0,0 +1,30 @@
+package config
+
+import "testing"
+
+// TestDomainsResult 测试更新结果统计
+func TestDomainsResult(t *testing.T) {
+	domains := Domains{
+		Ipv4Domains:  []*Domain{{DomainName: "example.com", UpdateStatus: UpdatedSuccess}, {DomainName: "example.com", SubDomain: "www"}},
+		Ipv6Domains:  []*Domain{{DomainName: "example.com", SubDomain: "v6", UpdateStatus: UpdatedFailed}},
+		CnameDomains: []*Domain{{DomainName: "example.com", SubDomain: "cdn", UpdateStatus: UpdatedNothing}},
+	}
+
+	result := domains.Result()
+	if result.Success != 1 || result.Failed != 1 || result.Nothing != 2 {
+		t.Errorf("期待 1/1/2, 得到 %d/%d/%d", result.Success, result.Failed, result.Nothing)
+	}
+	if result.Err() == nil || len(result.FailedDomains) != 1 || result.FailedDomains[0] != "v6.example.com" {
+		t.Errorf("期待 v6.example.com 失败, 得到 %v", result.FailedDomains)
+	}
+
+	var total UpdateResult
+	total.Add(result)
+	total.Add(UpdateResult{Success: 2})
+	if total.Success != 3 || total.Failed != 1 {
+		t.Errorf("期待合并后 3/1, 得到 %d/%d", total.Success, total.Failed)
+	}
+	if (UpdateResult{Success: 1}).Err() != nil {
+		t.Error("没有失败时不应返回错误")
+	}
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// Slack Slack Incoming Webhook通知
+type Slack struct {
+	// 如：https://hooks.slack.com/services/T000/B000/XXXX
+	SlackURL string
+	// 频道, 如 #ddns, 为空时使用Webhook默认的频道
+	SlackChannel string
+	// 触发条件, 同 WebhookTrigger
+	SlackTrigger string
+}
+
+// slackNotifier Slack Incoming Webhook通知
+type slackNotifier struct {
+	Slack
+}
+
+// slackMessage 消息正文为本次更新的统计, 每个域名一个attachment
+type slackMessage struct {
+	Channel     string            `json:"channel,omitempty"`
+	Username    string            `json:"username"`
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments"`
+}
+
+type slackAttachment struct {
+	Fallback string       `json:"fallback"`
+	Color    string       `json:"color"`
+	Title    string       `json:"title"`
+	Fields   []slackField `json:"fields"`
+}
+
+type slackField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+// Notify 通过Slack Incoming Webhook发送更新结果
+func (s slackNotifier) Notify(ctx context.Context, results []DomainResult) error {
+	byt, _ := json.Marshal(getSlackMessage(s.SlackChannel, results))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.SlackURL, bytes.NewReader(byt))
+	if err != nil {
+		return s.hideURL(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	clt := util.CreateHTTPClient()
+	resp, err := clt.Do(req)
+	_, err = util.GetHTTPResponseOrg(resp, err)
+	if err != nil {
+		return s.hideURL(err)
+	}
+	return nil
+}
+
+// hideURL 异常信息中可能包含地址中的Token
+func (s slackNotifier) hideURL(err error) error {
+	return errors.New(strings.ReplaceAll(err.Error(), s.SlackURL, "***"))
+}
+
+// getSlackMessage 统计所有域名的更新结果, 每个有变化的域名为一个attachment, 成功为绿色, 失败为红色
+func getSlackMessage(channel string, results []DomainResult) slackMessage {
+	var result UpdateResult
+	for _, r := range results {
+		switch r.updateStatus() {
+		case UpdatedSuccess:
+			result.Success++
+		case UpdatedFailed:
+			result.Failed++
+		default:
+			result.Nothing++
+		}
+	}
+
+	msg := slackMessage{
+		Channel:  channel,
+		Username: "ddns-go",
+		Text:     util.LogStr("更新完成, 成功: %d, 失败: %d, 未改变: %d", result.Success, result.Failed, result.Nothing),
+	}
+	for _, r := range getNotifyResults(results) {
+		oldIP := r.OldIP
+		if oldIP == "" {
+			oldIP = "-"
+		}
+		status := util.LogStr(string(r.updateStatus()))
+		title := r.Domain + " " + r.RecordType
+		msg.Attachments = append(msg.Attachments, slackAttachment{
+			Fallback: fmt.Sprintf("%s: %s → %s %s", title, oldIP, r.NewIP, status),
+			Color:    fmt.Sprintf("#%06x", getStatusColor(r.updateStatus())),
+			Title:    title,
+			Fields: []slackField{
+				{Title: "IP", Value: oldIP + " → " + r.NewIP, Short: true},
+				{Title: "Status", Value: status, Short: true},
+			},
+		})
+	}
+	return msg
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+const telegramEndpoint = "https://api.telegram.org"
+
+// Telegram Telegram机器人通知
+type Telegram struct {
+	TelegramBotToken string
+	TelegramChatID   string
+	// 触发条件, 同 WebhookTrigger
+	TelegramTrigger string
+}
+
+// telegramNotifier Telegram机器人通知
+type telegramNotifier struct {
+	Telegram
+}
+
+// Notify 通过Telegram机器人发送更新结果
+func (t telegramNotifier) Notify(ctx context.Context, results []DomainResult) error {
+	byt, _ := json.Marshal(map[string]string{
+		"chat_id": t.TelegramChatID,
+		"text":    "ddns-go\n" + getNotifyText(results),
+	})
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		telegramEndpoint+"/bot"+t.TelegramBotToken+"/sendMessage",
+		bytes.NewReader(byt),
+	)
+	if err != nil {
+		return t.hideToken(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	clt := util.CreateHTTPClient()
+	resp, err := clt.Do(req)
+	_, err = util.GetHTTPResponseOrg(resp, err)
+	return t.hideToken(err)
+}
+
+// hideToken 异常信息中可能包含Token
+func (t telegramNotifier) hideToken(err error) error {
+	if err == nil {
+		return nil
+	}
+	return errors.New(strings.ReplaceAll(err.Error(), t.TelegramBotToken, "***"))
+}
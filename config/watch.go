@@ -0,0 +1,47 @@
+package config
+
+import (
+	"os"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// 读取或写入配置文件时的修改时间, 需持有 cache.Lock
+var configModTime time.Time
+
+// updateConfigModTime 记录配置文件当前的修改时间, 需持有 cache.Lock
+func updateConfigModTime() {
+	if info, err := os.Stat(util.GetConfigFilePath()); err == nil {
+		configModTime = info.ModTime()
+	}
+}
+
+// WatchConfig 定时检查配置文件, 被修改时清空配置缓存并执行 onChange
+func WatchConfig(interval time.Duration, onChange func()) {
+	for {
+		time.Sleep(interval)
+		if reloadIfChanged() {
+			onChange()
+		}
+	}
+}
+
+// reloadIfChanged 配置文件在读取后被其它程序修改时清空配置缓存, 返回是否被修改
+// 正在运行的更新使用的是之前读取的配置, 不受影响
+func reloadIfChanged() bool {
+	info, err := os.Stat(util.GetConfigFilePath())
+	if err != nil {
+		return false
+	}
+
+	cache.Lock.Lock()
+	defer cache.Lock.Unlock()
+	// 还未读取过配置
+	if cache.ConfigSingle == nil || info.ModTime().Equal(configModTime) {
+		return false
+	}
+	cache.ConfigSingle = nil
+	util.Log("配置文件已被修改, 将重新加载")
+	return true
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// TestReloadIfChanged 测试配置文件被其它程序修改时清空缓存, 自己保存时不清空
+func TestReloadIfChanged(t *testing.T) {
+	conf, configFilePath := loadTestConfig(t, "version: 2\nlang: en\n")
+	if reloadIfChanged() {
+		t.Error("未修改时不应重新加载")
+	}
+
+	conf.Lang = "zh"
+	if err := conf.SaveConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetConfigCached(); err != nil {
+		t.Fatal(err)
+	}
+	if reloadIfChanged() {
+		t.Error("自己保存时不应重新加载")
+	}
+
+	if err := os.WriteFile(configFilePath, []byte("version: 2\nlang: zh\ndryrun: true\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(configFilePath, future, future); err != nil {
+		t.Fatal(err)
+	}
+	if !reloadIfChanged() {
+		t.Fatal("修改后应重新加载")
+	}
+	conf, err := GetConfigCached()
+	if err != nil || !conf.DryRun {
+		t.Errorf("重新加载失败: %+v %v", conf, err)
+	}
+	if reloadIfChanged() {
+		t.Error("重新加载后不应再次重新加载")
+	}
+}
@@ -1,7 +1,9 @@
 package config
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -15,8 +17,22 @@ type Webhook struct {
 	WebhookURL         string
 	WebhookRequestBody string
 	WebhookHeaders     string
+	// 请求方法, 为空时 RequestBody 为空则为 GET, 否则为 POST
+	WebhookMethod string
+	// 触发条件, 见 TriggerOnChange/TriggerOnFailure/TriggerAlways
+	WebhookTrigger string
 }
 
+// 通知的触发条件
+const (
+	// TriggerOnChange 更新成功或失败时触发, 默认
+	TriggerOnChange = ""
+	// TriggerOnFailure 仅更新失败时触发
+	TriggerOnFailure = "failed"
+	// TriggerAlways 每次运行都触发
+	TriggerAlways = "always"
+)
+
 // updateStatusType 更新状态
 type updateStatusType string
 
@@ -29,73 +45,69 @@ const (
 	UpdatedSuccess = "成功"
 )
 
-// 更新失败次数
-var updatedFailedTimes = 0
-
 // hasJSONPrefix returns true if the string starts with a JSON open brace.
 func hasJSONPrefix(s string) bool {
 	return strings.HasPrefix(s, "{") || strings.HasPrefix(s, "[")
 }
 
-// ExecWebhook 添加或更新IPv4/IPv6记录, 返回是否有更新失败的
-func ExecWebhook(domains *Domains, conf *Config) (v4Status updateStatusType, v6Status updateStatusType) {
-	v4Status = getDomainsStatus(domains.Ipv4Domains)
-	v6Status = getDomainsStatus(domains.Ipv6Domains)
-
-	if conf.WebhookURL != "" && (v4Status != UpdatedNothing || v6Status != UpdatedNothing) {
-		// 第3次失败才触发一次webhook
-		if v4Status == UpdatedFailed || v6Status == UpdatedFailed {
-			updatedFailedTimes++
-			if updatedFailedTimes != 3 {
-				util.Log("将不会触发Webhook, 仅在第 3 次失败时触发一次Webhook, 当前失败次数：%d", updatedFailedTimes)
-				return
-			}
-		} else {
-			updatedFailedTimes = 0
-		}
+// webhookNotifier Webhook通知
+type webhookNotifier struct {
+	Webhook
+}
 
-		// 成功和失败都要触发webhook
-		method := "GET"
-		postPara := ""
-		contentType := "application/x-www-form-urlencoded"
-		if conf.WebhookRequestBody != "" {
-			method = "POST"
-			postPara = replacePara(domains, conf.WebhookRequestBody, v4Status, v6Status)
-			if json.Valid([]byte(postPara)) {
-				contentType = "application/json"
-			} else if hasJSONPrefix(postPara) {
-				// 如果 RequestBody 的 JSON 无效但前缀为 JSON，提示无效
-				util.Log("Webhook中的 RequestBody JSON 无效")
-			}
-		}
-		requestURL := replacePara(domains, conf.WebhookURL, v4Status, v6Status)
-		u, err := url.Parse(requestURL)
-		if err != nil {
-			util.Log("Webhook配置中的URL不正确")
-			return
-		}
-		req, err := http.NewRequest(method, fmt.Sprintf("%s://%s%s?%s", u.Scheme, u.Host, u.Path, u.Query().Encode()), strings.NewReader(postPara))
-		if err != nil {
-			util.Log("Webhook调用失败! 异常信息：%s", err)
-			return
-		}
+// NewWebhookNotifier 创建Webhook通知, 用于模拟测试
+func NewWebhookNotifier(webhook Webhook) Notifier {
+	return webhookNotifier{webhook}
+}
 
-		headers := extractHeaders(conf.WebhookHeaders)
-		for key, value := range headers {
-			req.Header.Add(key, value)
-		}
-		req.Header.Add("content-type", contentType)
-
-		clt := util.CreateHTTPClient()
-		resp, err := clt.Do(req)
-		body, err := util.GetHTTPResponseOrg(resp, err)
-		if err == nil {
-			util.Log("Webhook调用成功! 返回数据：%s", string(body))
-		} else {
-			util.Log("Webhook调用失败! 异常信息：%s", err)
+// Notify 调用Webhook
+func (w webhookNotifier) Notify(ctx context.Context, results []DomainResult) error {
+	// 成功和失败都要触发webhook
+	method := "GET"
+	postPara := ""
+	contentType := "application/x-www-form-urlencoded"
+	requestBody := w.WebhookRequestBody
+	if requestBody == "" && w.WebhookMethod != "" && w.WebhookMethod != "GET" {
+		// 指定了方法但没有 RequestBody 时, 发送所有域名的更新结果
+		requestBody = "#{domainsJSON}"
+	}
+	if requestBody != "" {
+		method = "POST"
+		postPara = replacePara(results, requestBody)
+		if json.Valid([]byte(postPara)) {
+			contentType = "application/json"
+		} else if hasJSONPrefix(postPara) {
+			// 如果 RequestBody 的 JSON 无效但前缀为 JSON，提示无效
+			util.LogWarn("Webhook中的 RequestBody JSON 无效")
 		}
 	}
-	return
+	if w.WebhookMethod != "" {
+		method = w.WebhookMethod
+	}
+	requestURL := replacePara(results, w.WebhookURL)
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return errors.New(util.LogStr("Webhook配置中的URL不正确"))
+	}
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s://%s%s?%s", u.Scheme, u.Host, u.Path, u.Query().Encode()), strings.NewReader(postPara))
+	if err != nil {
+		return err
+	}
+
+	headers := extractHeaders(w.WebhookHeaders)
+	for key, value := range headers {
+		req.Header.Add(key, value)
+	}
+	req.Header.Add("content-type", contentType)
+
+	clt := util.CreateHTTPClient()
+	resp, err := clt.Do(req)
+	body, err := util.GetHTTPResponseOrg(resp, err)
+	if err != nil {
+		return err
+	}
+	util.LogDebug("Webhook返回数据：%s", string(body))
+	return nil
 }
 
 // getDomainsStatus 获取域名状态
@@ -119,28 +131,28 @@ func getDomainsStatus(domains []*Domain) updateStatusType {
 }
 
 // replacePara 替换参数
-func replacePara(domains *Domains, orgPara string, ipv4Result updateStatusType, ipv6Result updateStatusType) string {
-	return strings.NewReplacer(
-		"#{ipv4Addr}", domains.Ipv4Addr,
-		"#{ipv4Result}", util.LogStr(string(ipv4Result)), // i18n
-		"#{ipv4Domains}", getDomainsStr(domains.Ipv4Domains),
-		"#{ipv6Addr}", domains.Ipv6Addr,
-		"#{ipv6Result}", util.LogStr(string(ipv6Result)), // i18n
-		"#{ipv6Domains}", getDomainsStr(domains.Ipv6Domains),
-	).Replace(orgPara)
-}
-
-// getDomainsStr 用逗号分割域名
-func getDomainsStr(domains []*Domain) string {
-	str := ""
-	for i, v46 := range domains {
-		str += v46.String()
-		if i != len(domains)-1 {
-			str += ","
+func replacePara(results []DomainResult, orgPara string) string {
+	var ipv4Addr, ipv6Addr string
+	var ipv4Domains, ipv6Domains []string
+	for _, result := range results {
+		switch result.RecordType {
+		case "A":
+			ipv4Addr = result.NewIP
+			ipv4Domains = append(ipv4Domains, result.Domain)
+		case "AAAA":
+			ipv6Addr = result.NewIP
+			ipv6Domains = append(ipv6Domains, result.Domain)
 		}
 	}
-
-	return str
+	return strings.NewReplacer(
+		"#{ipv4Addr}", ipv4Addr,
+		"#{ipv4Result}", util.LogStr(string(getResultsStatus(results, "A"))), // i18n
+		"#{ipv4Domains}", strings.Join(ipv4Domains, ","),
+		"#{ipv6Addr}", ipv6Addr,
+		"#{ipv6Result}", util.LogStr(string(getResultsStatus(results, "AAAA"))), // i18n
+		"#{ipv6Domains}", strings.Join(ipv6Domains, ","),
+		"#{domainsJSON}", getResultsJSON(results),
+	).Replace(orgPara)
 }
 
 // extractHeaders converts s into a map of headers.
@@ -158,7 +170,7 @@ func extractHeaders(s string) map[string]string {
 
 		parts := strings.Split(line, ":")
 		if len(parts) != 2 {
-			util.Log("Webhook Header不正确: %s", line)
+			util.LogWarn("Webhook Header不正确: %s", line)
 			continue
 		}
 
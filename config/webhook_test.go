@@ -20,3 +20,62 @@ b: bar`
 		t.Errorf("Expected %v, got %v", expected, parsedHeaders)
 	}
 }
+
+// TestShouldNotify 测试通知的触发条件
+func TestShouldNotify(t *testing.T) {
+	tests := []struct {
+		trigger string
+		v4, v6  updateStatusType
+		want    bool
+	}{
+		{TriggerOnChange, UpdatedNothing, UpdatedNothing, false},
+		{TriggerOnChange, UpdatedSuccess, UpdatedNothing, true},
+		{TriggerOnChange, UpdatedNothing, UpdatedFailed, true},
+		{TriggerOnFailure, UpdatedSuccess, UpdatedNothing, false},
+		{TriggerOnFailure, UpdatedSuccess, UpdatedFailed, true},
+		{TriggerAlways, UpdatedNothing, UpdatedNothing, true},
+	}
+	for _, tt := range tests {
+		if got := shouldNotify(tt.trigger, tt.v4, tt.v6); got != tt.want {
+			t.Errorf("%q %s/%s 期待 %v, 得到 %v", tt.trigger, tt.v4, tt.v6, tt.want, got)
+		}
+	}
+}
+
+// TestGetResultsJSON 测试 #{domainsJSON} 变量
+func TestGetResultsJSON(t *testing.T) {
+	domains := &Domains{
+		Ipv4Addr:     "1.2.3.5",
+		Ipv4PrevAddr: "1.2.3.4",
+		Ipv4Domains: []*Domain{
+			{DomainName: "example.com", SubDomain: "www", UpdateStatus: UpdatedSuccess},
+			{DomainName: "example.com", UpdateStatus: UpdatedFailed},
+		},
+		CnameDomains: []*Domain{
+			{DomainName: "example.com", SubDomain: "blog", Target: "example.github.io"},
+		},
+	}
+	expected := `[{"domain":"www.example.com","recordType":"A","oldIP":"1.2.3.4","newIP":"1.2.3.5","status":"success"},` +
+		`{"domain":"example.com","recordType":"A","oldIP":"1.2.3.4","newIP":"1.2.3.5","status":"failed"},` +
+		`{"domain":"blog.example.com","recordType":"CNAME","oldIP":"","newIP":"example.github.io","status":"nothing"}]`
+	if got := getResultsJSON(GetDomainResults(domains)); got != expected {
+		t.Errorf("期待 %s, 得到 %s", expected, got)
+	}
+	if got := getResultsJSON(GetDomainResults(&Domains{})); got != "[]" {
+		t.Errorf("期待 [], 得到 %s", got)
+	}
+}
+
+// TestReplacePara 测试Webhook变量替换
+func TestReplacePara(t *testing.T) {
+	results := []DomainResult{
+		{Domain: "a.example.com", RecordType: "A", NewIP: "1.2.3.4", Status: "success"},
+		{Domain: "b.example.com", RecordType: "A", NewIP: "1.2.3.4", Status: "nothing"},
+		{Domain: "c.example.com", RecordType: "AAAA", NewIP: "::1", Status: "failed"},
+	}
+	got := replacePara(results, "#{ipv4Addr} #{ipv4Result} #{ipv4Domains} #{ipv6Addr} #{ipv6Result} #{ipv6Domains}")
+	expected := "1.2.3.4 success a.example.com,b.example.com ::1 failed c.example.com"
+	if got != expected {
+		t.Errorf("期待 %q, 得到 %q", expected, got)
+	}
+}
@@ -2,6 +2,7 @@ package dns
 
 import (
 	"bytes"
+	"context"
 	"net/http"
 	"net/url"
 
@@ -19,6 +20,7 @@ type Alidns struct {
 	DNS     config.DNS
 	Domains config.Domains
 	TTL     string
+	client  *http.Client
 }
 
 // AlidnsRecord record
@@ -42,12 +44,17 @@ type AlidnsResp struct {
 	RequestID string
 }
 
+func init() {
+	RegisterDNS("alidns", func() DNS { return &Alidns{} })
+}
+
 // Init 初始化
 func (ali *Alidns) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
 	ali.Domains.Ipv4Cache = ipv4cache
 	ali.Domains.Ipv6Cache = ipv6cache
 	ali.DNS = dnsConf.DNS
 	ali.Domains.GetNewIp(dnsConf)
+	ali.client = util.CreateHTTPClientTimeout(dnsConf.GetHTTPTimeout())
 	if dnsConf.TTL == "" {
 		// 默认600s
 		ali.TTL = "600"
@@ -57,20 +64,20 @@ func (ali *Alidns) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6
 }
 
 // AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
-func (ali *Alidns) AddUpdateDomainRecords() config.Domains {
-	ali.addUpdateDomainRecords("A")
-	ali.addUpdateDomainRecords("AAAA")
+func (ali *Alidns) AddUpdateDomainRecords(ctx context.Context) config.Domains {
+	ali.addUpdateDomainRecords(ctx, "A")
+	ali.addUpdateDomainRecords(ctx, "AAAA")
 	return ali.Domains
 }
 
-func (ali *Alidns) addUpdateDomainRecords(recordType string) {
+func (ali *Alidns) addUpdateDomainRecords(ctx context.Context, recordType string) {
 	ipAddr, domains := ali.Domains.GetNewIpResult(recordType)
 
 	if ipAddr == "" {
 		return
 	}
 
-	for _, domain := range domains {
+	forEachDomain(domains, func(domain *config.Domain) {
 		var records AlidnsSubDomainRecords
 		// 获取当前域名信息
 		params := domain.GetCustomParams()
@@ -78,10 +85,10 @@ func (ali *Alidns) addUpdateDomainRecords(recordType string) {
 		params.Set("DomainName", domain.DomainName)
 		params.Set("SubDomain", domain.GetFullDomain())
 		params.Set("Type", recordType)
-		err := ali.request(params, &records)
+		err := ali.request(ctx, params, &records)
 
 		if err != nil {
-			util.Log("查询域名信息发生异常! %s", err)
+			util.LogError("查询域名信息发生异常! %s", err)
 			domain.UpdateStatus = config.UpdatedFailed
 			return
 		}
@@ -97,17 +104,20 @@ func (ali *Alidns) addUpdateDomainRecords(recordType string) {
 				}
 			}
 			// 存在，更新
-			ali.modify(recordSelected, domain, recordType, ipAddr)
+			ali.modify(ctx, recordSelected, domain, recordType, ipAddr)
 		} else {
 			// 不存在，创建
-			ali.create(domain, recordType, ipAddr)
+			ali.create(ctx, domain, recordType, ipAddr)
 		}
 
-	}
+	})
 }
 
 // 创建
-func (ali *Alidns) create(domain *config.Domain, recordType string, ipAddr string) {
+func (ali *Alidns) create(ctx context.Context, domain *config.Domain, recordType string, ipAddr string) {
+	if skipCreate(domain) {
+		return
+	}
 	params := domain.GetCustomParams()
 	params.Set("Action", "AddDomainRecord")
 	params.Set("DomainName", domain.DomainName)
@@ -116,11 +126,15 @@ func (ali *Alidns) create(domain *config.Domain, recordType string, ipAddr strin
 	params.Set("Value", ipAddr)
 	params.Set("TTL", ali.TTL)
 
+	if dryRun(domain, "GET", alidnsEndpoint, params) {
+		return
+	}
+
 	var result AlidnsResp
-	err := ali.request(params, &result)
+	err := ali.request(ctx, params, &result)
 
 	if err != nil {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogError("新增域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
@@ -129,17 +143,18 @@ func (ali *Alidns) create(domain *config.Domain, recordType string, ipAddr strin
 		util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, "返回RecordId为空")
+		util.LogError("新增域名解析 %s 失败! 异常信息: %s", domain, "返回RecordId为空")
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
 
 // 修改
-func (ali *Alidns) modify(recordSelected AlidnsRecord, domain *config.Domain, recordType string, ipAddr string) {
+func (ali *Alidns) modify(ctx context.Context, recordSelected AlidnsRecord, domain *config.Domain, recordType string, ipAddr string) {
 
 	// 相同不修改
 	if recordSelected.Value == ipAddr {
-		util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		util.LogDebug("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		domain.UpdateStatus = config.UpdatedNothing
 		return
 	}
 
@@ -151,11 +166,15 @@ func (ali *Alidns) modify(recordSelected AlidnsRecord, domain *config.Domain, re
 	params.Set("Value", ipAddr)
 	params.Set("TTL", ali.TTL)
 
+	if dryRun(domain, "GET", alidnsEndpoint, params) {
+		return
+	}
+
 	var result AlidnsResp
-	err := ali.request(params, &result)
+	err := ali.request(ctx, params, &result)
 
 	if err != nil {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
@@ -164,17 +183,26 @@ func (ali *Alidns) modify(recordSelected AlidnsRecord, domain *config.Domain, re
 		util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, "返回RecordId为空")
+		util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, "返回RecordId为空")
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
 
+// Check 获取域名列表, 检查AccessKey是否正确
+func (ali *Alidns) Check(ctx context.Context) error {
+	params := url.Values{}
+	params.Set("Action", "DescribeDomains")
+	params.Set("PageSize", "1")
+	return ali.request(ctx, params, nil)
+}
+
 // request 统一请求接口
-func (ali *Alidns) request(params url.Values, result interface{}) (err error) {
+func (ali *Alidns) request(ctx context.Context, params url.Values, result interface{}) (err error) {
 
 	util.AliyunSigner(ali.DNS.ID, ali.DNS.Secret, &params)
 
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"GET",
 		alidnsEndpoint,
 		bytes.NewBuffer(nil),
@@ -185,8 +213,7 @@ func (ali *Alidns) request(params url.Values, result interface{}) (err error) {
 		return
 	}
 
-	client := util.CreateHTTPClient()
-	resp, err := client.Do(req)
+	resp, err := ali.client.Do(req)
 	err = util.GetHTTPResponse(resp, err, result)
 
 	return
@@ -0,0 +1,270 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+const (
+	azureEndpoint      = "https://management.azure.com"
+	azureLoginEndpoint = "https://login.microsoftonline.com/%s/oauth2/v2.0/token"
+	azureAPIVersion    = "2018-05-01"
+)
+
+// Azure Azure DNS实现
+// https://learn.microsoft.com/en-us/rest/api/dns/record-sets
+type Azure struct {
+	DNS     config.DNS
+	Domains config.Domains
+	TTL     int
+	client  *http.Client
+}
+
+// AzureTokenResp client_credentials 返回结果
+type AzureTokenResp struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// AzureARecord A记录
+type AzureARecord struct {
+	Ipv4Address string `json:"ipv4Address"`
+}
+
+// AzureAAAARecord AAAA记录
+type AzureAAAARecord struct {
+	Ipv6Address string `json:"ipv6Address"`
+}
+
+// AzureRecordSet 记录集
+type AzureRecordSet struct {
+	Properties struct {
+		TTL         int               `json:"TTL"`
+		ARecords    []AzureARecord    `json:"ARecords,omitempty"`
+		AAAARecords []AzureAAAARecord `json:"AAAARecords,omitempty"`
+	} `json:"properties"`
+}
+
+// azureToken 缓存的访问令牌
+type azureToken struct {
+	accessToken string
+	expiresAt   time.Time
+}
+
+// 每次运行都会重新创建 Azure, 令牌按租户和客户端缓存到过期前
+var (
+	azureTokens   = map[string]azureToken{}
+	azureTokensMu sync.Mutex
+)
+
+func init() {
+	RegisterDNS("azure", func() DNS { return &Azure{} })
+}
+
+// Init 初始化
+func (az *Azure) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
+	az.Domains.Ipv4Cache = ipv4cache
+	az.Domains.Ipv6Cache = ipv6cache
+	az.DNS = dnsConf.DNS
+	az.Domains.GetNewIp(dnsConf)
+	az.client = util.CreateHTTPClientTimeout(dnsConf.GetHTTPTimeout())
+	if dnsConf.TTL == "" {
+		// 默认300s
+		az.TTL = 300
+	} else {
+		ttl, err := strconv.Atoi(dnsConf.TTL)
+		if err != nil {
+			az.TTL = 300
+		} else {
+			az.TTL = ttl
+		}
+	}
+}
+
+// AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
+func (az *Azure) AddUpdateDomainRecords(ctx context.Context) config.Domains {
+	az.addUpdateDomainRecords(ctx, "A")
+	az.addUpdateDomainRecords(ctx, "AAAA")
+	return az.Domains
+}
+
+func (az *Azure) addUpdateDomainRecords(ctx context.Context, recordType string) {
+	ipAddr, domains := az.Domains.GetNewIpResult(recordType)
+
+	if ipAddr == "" {
+		return
+	}
+
+	// Zone ID 为 DNS 区域的资源ID, 如 /subscriptions/{sub}/resourceGroups/{rg}/providers/Microsoft.Network/dnsZones/{zone}
+	resourceID := "/" + strings.Trim(az.DNS.ZoneID, "/")
+	zoneName := resourceID[strings.LastIndex(resourceID, "/")+1:]
+
+	forEachDomain(domains, func(domain *config.Domain) {
+		name, ok := azureRelativeName(domain.String(), zoneName)
+		if !ok || !strings.Contains(strings.ToLower(resourceID), "/dnszones/") {
+			util.LogError("在DNS服务商中未找到根域名: %s", domain.DomainName)
+			domain.UpdateStatus = config.UpdatedFailed
+			return
+		}
+
+		recordURL := fmt.Sprintf("%s%s/%s/%s?api-version=%s", azureEndpoint, resourceID, recordType, url.PathEscape(name), azureAPIVersion)
+
+		existing, err := az.getRecordSet(ctx, recordURL)
+		if err != nil {
+			util.LogError("查询域名信息发生异常! %s", err)
+			domain.UpdateStatus = config.UpdatedFailed
+			return
+		}
+
+		az.createOrModify(ctx, recordURL, existing, domain, recordType, ipAddr)
+	})
+}
+
+// azureRelativeName 获得相对区域的记录名, 区域本身为@
+func azureRelativeName(fqdn, zoneName string) (string, bool) {
+	fqdn = strings.ToLower(fqdn)
+	zoneName = strings.ToLower(zoneName)
+	if fqdn == zoneName {
+		return "@", true
+	}
+	if strings.HasSuffix(fqdn, "."+zoneName) {
+		return strings.TrimSuffix(fqdn, "."+zoneName), true
+	}
+	return "", false
+}
+
+// getRecordSet 获得记录集, 不存在时返回nil
+func (az *Azure) getRecordSet(ctx context.Context, recordURL string) (*AzureRecordSet, error) {
+	req, err := az.newRequest(ctx, http.MethodGet, recordURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := az.client.Do(req)
+	if err == nil && resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, nil
+	}
+
+	var result AzureRecordSet
+	err = util.GetHTTPResponse(resp, err, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// createOrModify 记录集不存在时新增, 存在时整体替换
+func (az *Azure) createOrModify(ctx context.Context, recordURL string, existing *AzureRecordSet, domain *config.Domain, recordType string, ipAddr string) {
+	if existing == nil && skipCreate(domain) {
+		return
+	}
+	operation := "新增"
+	if existing != nil {
+		// 相同不修改
+		props := existing.Properties
+		if props.TTL == az.TTL &&
+			((recordType == "A" && len(props.ARecords) == 1 && props.ARecords[0].Ipv4Address == ipAddr) ||
+				(recordType == "AAAA" && len(props.AAAARecords) == 1 && props.AAAARecords[0].Ipv6Address == ipAddr)) {
+			util.LogDebug("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+			domain.UpdateStatus = config.UpdatedNothing
+			return
+		}
+		operation = "更新"
+	}
+
+	var recordSet AzureRecordSet
+	recordSet.Properties.TTL = az.TTL
+	if recordType == "A" {
+		recordSet.Properties.ARecords = []AzureARecord{{Ipv4Address: ipAddr}}
+	} else {
+		recordSet.Properties.AAAARecords = []AzureAAAARecord{{Ipv6Address: ipAddr}}
+	}
+	if dryRun(domain, http.MethodPut, recordURL, recordSet) {
+		return
+	}
+
+	req, err := az.newRequest(ctx, http.MethodPut, recordURL, recordSet)
+	if err == nil {
+		var result AzureRecordSet
+		var resp *http.Response
+		resp, err = az.client.Do(req)
+		err = util.GetHTTPResponse(resp, err, &result)
+	}
+	if err != nil {
+		util.LogError(operation+"域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+
+	util.Log(operation+"域名解析 %s 成功! IP: %s", domain, ipAddr)
+	domain.UpdateStatus = config.UpdatedSuccess
+}
+
+// newRequest 创建带访问令牌的请求
+func (az *Azure) newRequest(ctx context.Context, method, url string, body interface{}) (*http.Request, error) {
+	token, err := az.getToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := util.NewJSONRequest(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// getToken 通过 client_credentials 获得访问令牌, 过期前复用
+func (az *Azure) getToken(ctx context.Context) (string, error) {
+	key := az.DNS.TenantID + "/" + az.DNS.ID + "/" + az.DNS.Secret
+
+	azureTokensMu.Lock()
+	defer azureTokensMu.Unlock()
+
+	// 提前1分钟刷新
+	if token, ok := azureTokens[key]; ok && time.Now().Add(time.Minute).Before(token.expiresAt) {
+		return token.accessToken, nil
+	}
+
+	params := url.Values{}
+	params.Set("grant_type", "client_credentials")
+	params.Set("client_id", az.DNS.ID)
+	params.Set("client_secret", az.DNS.Secret)
+	params.Set("scope", azureEndpoint+"/.default")
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		fmt.Sprintf(azureLoginEndpoint, url.PathEscape(az.DNS.TenantID)),
+		strings.NewReader(params.Encode()),
+	)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var result AzureTokenResp
+	resp, err := az.client.Do(req)
+	err = util.GetHTTPResponse(resp, err, &result)
+	if err != nil {
+		return "", err
+	}
+
+	azureTokens[key] = azureToken{
+		accessToken: result.AccessToken,
+		expiresAt:   time.Now().Add(time.Duration(result.ExpiresIn) * time.Second),
+	}
+	return result.AccessToken, nil
+}
@@ -2,6 +2,7 @@ package dns
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"strconv"
@@ -20,6 +21,7 @@ type BaiduCloud struct {
 	DNS     config.DNS
 	Domains config.Domains
 	TTL     int
+	client  *http.Client
 }
 
 // BaiduRecord 单条解析记录
@@ -67,11 +69,16 @@ type BaiduCreateRequest struct {
 	ZoneName string `json:"zoneName"`
 }
 
+func init() {
+	RegisterDNS("baiducloud", func() DNS { return &BaiduCloud{} })
+}
+
 func (baidu *BaiduCloud) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
 	baidu.Domains.Ipv4Cache = ipv4cache
 	baidu.Domains.Ipv6Cache = ipv6cache
 	baidu.DNS = dnsConf.DNS
 	baidu.Domains.GetNewIp(dnsConf)
+	baidu.client = util.CreateHTTPClientTimeout(dnsConf.GetHTTPTimeout())
 	if dnsConf.TTL == "" {
 		// 默认300s
 		baidu.TTL = 300
@@ -86,19 +93,19 @@ func (baidu *BaiduCloud) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache
 }
 
 // AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
-func (baidu *BaiduCloud) AddUpdateDomainRecords() config.Domains {
-	baidu.addUpdateDomainRecords("A")
-	baidu.addUpdateDomainRecords("AAAA")
+func (baidu *BaiduCloud) AddUpdateDomainRecords(ctx context.Context) config.Domains {
+	baidu.addUpdateDomainRecords(ctx, "A")
+	baidu.addUpdateDomainRecords(ctx, "AAAA")
 	return baidu.Domains
 }
 
-func (baidu *BaiduCloud) addUpdateDomainRecords(recordType string) {
+func (baidu *BaiduCloud) addUpdateDomainRecords(ctx context.Context, recordType string) {
 	ipAddr, domains := baidu.Domains.GetNewIpResult(recordType)
 	if ipAddr == "" {
 		return
 	}
 
-	for _, domain := range domains {
+	forEachDomain(domains, func(domain *config.Domain) {
 		var records BaiduRecordsResp
 
 		requestBody := BaiduListRequest{
@@ -107,9 +114,9 @@ func (baidu *BaiduCloud) addUpdateDomainRecords(recordType string) {
 			PageSize: 1000,
 		}
 
-		err := baidu.request("POST", baiduEndpoint+"/v1/domain/resolve/list", requestBody, &records)
+		err := baidu.request(ctx, "POST", baiduEndpoint+"/v1/domain/resolve/list", requestBody, &records)
 		if err != nil {
-			util.Log("查询域名信息发生异常! %s", err)
+			util.LogError("查询域名信息发生异常! %s", err)
 			domain.UpdateStatus = config.UpdatedFailed
 			return
 		}
@@ -118,20 +125,23 @@ func (baidu *BaiduCloud) addUpdateDomainRecords(recordType string) {
 		for _, record := range records.Result {
 			if record.Domain == domain.GetSubDomain() {
 				//存在就去更新
-				baidu.modify(record, domain, recordType, ipAddr)
+				baidu.modify(ctx, record, domain, recordType, ipAddr)
 				find = true
 				break
 			}
 		}
 		if !find {
 			//没找到，去创建
-			baidu.create(domain, recordType, ipAddr)
+			baidu.create(ctx, domain, recordType, ipAddr)
 		}
-	}
+	})
 }
 
 // create 创建新的解析
-func (baidu *BaiduCloud) create(domain *config.Domain, recordType string, ipAddr string) {
+func (baidu *BaiduCloud) create(ctx context.Context, domain *config.Domain, recordType string, ipAddr string) {
+	if skipCreate(domain) {
+		return
+	}
 	var baiduCreateRequest = BaiduCreateRequest{
 		Domain:   domain.GetSubDomain(), //处理一下@
 		RdType:   recordType,
@@ -139,23 +149,28 @@ func (baidu *BaiduCloud) create(domain *config.Domain, recordType string, ipAddr
 		Rdata:    ipAddr,
 		ZoneName: domain.DomainName,
 	}
+	if dryRun(domain, "POST", baiduEndpoint+"/v1/domain/resolve/add", baiduCreateRequest) {
+		return
+	}
+
 	var result BaiduRecordsResp
 
-	err := baidu.request("POST", baiduEndpoint+"/v1/domain/resolve/add", baiduCreateRequest, &result)
+	err := baidu.request(ctx, "POST", baiduEndpoint+"/v1/domain/resolve/add", baiduCreateRequest, &result)
 	if err == nil {
 		util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogError("新增域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
 
 // modify 更新解析
-func (baidu *BaiduCloud) modify(record BaiduRecord, domain *config.Domain, rdType string, ipAddr string) {
+func (baidu *BaiduCloud) modify(ctx context.Context, record BaiduRecord, domain *config.Domain, rdType string, ipAddr string) {
 	//没有变化直接跳过
 	if record.Rdata == ipAddr {
-		util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		util.LogDebug("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		domain.UpdateStatus = config.UpdatedNothing
 		return
 	}
 	var baiduModifyRequest = BaiduModifyRequest{
@@ -167,26 +182,31 @@ func (baidu *BaiduCloud) modify(record BaiduRecord, domain *config.Domain, rdTyp
 		Rdata:    ipAddr,
 		ZoneName: record.ZoneName,
 	}
+	if dryRun(domain, "POST", baiduEndpoint+"/v1/domain/resolve/edit", baiduModifyRequest) {
+		return
+	}
+
 	var result BaiduRecordsResp
 
-	err := baidu.request("POST", baiduEndpoint+"/v1/domain/resolve/edit", baiduModifyRequest, &result)
+	err := baidu.request(ctx, "POST", baiduEndpoint+"/v1/domain/resolve/edit", baiduModifyRequest, &result)
 	if err == nil {
 		util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
 
 // request 统一请求接口
-func (baidu *BaiduCloud) request(method string, url string, data interface{}, result interface{}) (err error) {
+func (baidu *BaiduCloud) request(ctx context.Context, method string, url string, data interface{}, result interface{}) (err error) {
 	jsonStr := make([]byte, 0)
 	if data != nil {
 		jsonStr, _ = json.Marshal(data)
 	}
 
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		method,
 		url,
 		bytes.NewBuffer(jsonStr),
@@ -198,8 +218,7 @@ func (baidu *BaiduCloud) request(method string, url string, data interface{}, re
 
 	util.BaiduSigner(baidu.DNS.ID, baidu.DNS.Secret, req)
 
-	client := util.CreateHTTPClient()
-	resp, err := client.Do(req)
+	resp, err := baidu.client.Do(req)
 	err = util.GetHTTPResponse(resp, err, result)
 
 	return
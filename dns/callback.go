@@ -1,6 +1,7 @@
 package dns
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -17,6 +18,11 @@ type Callback struct {
 	TTL      string
 	lastIpv4 string
 	lastIpv6 string
+	client   *http.Client
+}
+
+func init() {
+	RegisterDNS("callback", func() DNS { return &Callback{} })
 }
 
 // Init 初始化
@@ -28,6 +34,7 @@ func (cb *Callback) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv
 
 	cb.DNS = dnsConf.DNS
 	cb.Domains.GetNewIp(dnsConf)
+	cb.client = util.CreateHTTPClientTimeout(dnsConf.GetHTTPTimeout())
 	if dnsConf.TTL == "" {
 		// 默认600
 		cb.TTL = "600"
@@ -37,13 +44,13 @@ func (cb *Callback) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv
 }
 
 // AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
-func (cb *Callback) AddUpdateDomainRecords() config.Domains {
-	cb.addUpdateDomainRecords("A")
-	cb.addUpdateDomainRecords("AAAA")
+func (cb *Callback) AddUpdateDomainRecords(ctx context.Context) config.Domains {
+	cb.addUpdateDomainRecords(ctx, "A")
+	cb.addUpdateDomainRecords(ctx, "AAAA")
 	return cb.Domains
 }
 
-func (cb *Callback) addUpdateDomainRecords(recordType string) {
+func (cb *Callback) addUpdateDomainRecords(ctx context.Context, recordType string) {
 	ipAddr, domains := cb.Domains.GetNewIpResult(recordType)
 
 	if ipAddr == "" {
@@ -53,12 +60,12 @@ func (cb *Callback) addUpdateDomainRecords(recordType string) {
 	// 防止多次发送Webhook通知
 	if recordType == "A" {
 		if cb.lastIpv4 == ipAddr {
-			util.Log("你的IPv4未变化, 未触发 %s 请求", "Callback")
+			util.LogDebug("你的IPv4未变化, 未触发 %s 请求", "Callback")
 			return
 		}
 	} else {
 		if cb.lastIpv6 == ipAddr {
-			util.Log("你的IPv6未变化, 未触发 %s 请求", "Callback")
+			util.LogDebug("你的IPv6未变化, 未触发 %s 请求", "Callback")
 			return
 		}
 	}
@@ -77,25 +84,27 @@ func (cb *Callback) addUpdateDomainRecords(recordType string) {
 		requestURL := replacePara(cb.DNS.ID, ipAddr, domain, recordType, cb.TTL)
 		u, err := url.Parse(requestURL)
 		if err != nil {
-			util.Log("Callback的URL不正确")
+			util.LogError("Callback的URL不正确")
 			return
 		}
-		req, err := http.NewRequest(method, u.String(), strings.NewReader(postPara))
+		if dryRun(domain, method, u.String(), postPara) {
+			continue
+		}
+		req, err := http.NewRequestWithContext(ctx, method, u.String(), strings.NewReader(postPara))
 		if err != nil {
-			util.Log("异常信息: %s", err)
+			util.LogError("异常信息: %s", err)
 			domain.UpdateStatus = config.UpdatedFailed
 			return
 		}
 		req.Header.Add("content-type", contentType)
 
-		clt := util.CreateHTTPClient()
-		resp, err := clt.Do(req)
+		resp, err := cb.client.Do(req)
 		body, err := util.GetHTTPResponseOrg(resp, err)
 		if err == nil {
 			util.Log("Callback调用成功, 域名: %s, IP: %s, 返回数据: %s", domain, ipAddr, string(body))
 			domain.UpdateStatus = config.UpdatedSuccess
 		} else {
-			util.Log("Callback调用失败, 异常信息: %s", err)
+			util.LogError("Callback调用失败, 异常信息: %s", err)
 			domain.UpdateStatus = config.UpdatedFailed
 		}
 	}
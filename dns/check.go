@@ -0,0 +1,64 @@
+package dns
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// Checker 支持测试配置的DNS服务商
+type Checker interface {
+	DNS
+	// Check 调用只读的查询接口, 检查认证信息是否正确
+	Check(ctx context.Context) error
+}
+
+// DomainValidator 支持检查根域名是否在账号中的DNS服务商
+type DomainValidator interface {
+	DNS
+	// ValidateDomains 一次列出账号下的全部根域名, 返回配置中不在账号下的根域名
+	ValidateDomains(ctx context.Context) (missing []string, err error)
+}
+
+// ValidateDomains 启动时检查配置的根域名是否在DNS服务商的账号中, 不在时警告, 便于在首次更新前发现拼写错误
+func ValidateDomains(ctx context.Context) {
+	conf, err := config.GetConfigCached()
+	if err != nil {
+		return
+	}
+	for _, dc := range conf.DnsConf {
+		validator, ok := newDNS(dc.DNS.Name).(DomainValidator)
+		if !ok || !dc.Enabled {
+			continue
+		}
+
+		// 只解析域名, 不需要获取IP
+		dc.Ipv4.Enable = false
+		dc.Ipv6.Enable = false
+		validator.Init(&dc, &util.IpCache{}, &util.IpCache{})
+		missing, err := validator.ValidateDomains(ctx)
+		if err != nil {
+			util.LogWarn("检查 %s 的根域名失败! 异常信息: %s", dc.DNS.Name, err)
+			continue
+		}
+		for _, name := range missing {
+			util.LogWarn("根域名 %s 不在 %s 的账号中, 请检查域名是否正确及令牌是否有权限", name, dc.DNS.Name)
+		}
+	}
+}
+
+// CheckDnsConfig 测试DNS服务商的认证信息, 不会修改任何记录
+func CheckDnsConfig(ctx context.Context, dc config.DnsConfig) error {
+	checker, ok := newDNS(dc.DNS.Name).(Checker)
+	if !ok {
+		return errors.New(util.LogStr("该DNS服务商暂不支持测试"))
+	}
+
+	// 不需要获取IP
+	dc.Ipv4.Enable = false
+	dc.Ipv6.Enable = false
+	checker.Init(&dc, &util.IpCache{}, &util.IpCache{})
+	return checker.Check(ctx)
+}
@@ -0,0 +1,43 @@
+package dns
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+)
+
+// TestCheckDnsConfigNotSupported 测试不支持测试的DNS服务商
+func TestCheckDnsConfigNotSupported(t *testing.T) {
+	dc := config.DnsConfig{}
+	dc.DNS.Name = "callback"
+	if err := CheckDnsConfig(context.Background(), dc); err == nil {
+		t.Error("callback 不支持测试, 应返回错误")
+	}
+
+	for _, name := range []string{"alidns", "cloudflare", "porkbun", "hetzner", "digitalocean"} {
+		if _, ok := newDNS(name).(Checker); !ok {
+			t.Errorf("%s 应支持测试", name)
+		}
+	}
+}
+
+// TestRegisterDNS 测试注册DNS服务商, 未知名称使用阿里云
+func TestRegisterDNS(t *testing.T) {
+	if _, ok := newDNS("cloudflare").(*Cloudflare); !ok {
+		t.Error("cloudflare 应已注册")
+	}
+	if _, ok := newDNS("unknown").(*Alidns); !ok {
+		t.Error("未知名称应使用阿里云")
+	}
+
+	RegisterDNS("test", func() DNS { return &fakeDeleter{} })
+	defer func() {
+		providersLock.Lock()
+		delete(providers, "test")
+		providersLock.Unlock()
+	}()
+	if _, ok := newDNS("test").(*fakeDeleter); !ok {
+		t.Error("test 应已注册")
+	}
+}
@@ -1,215 +1,621 @@
 package dns
 
 import (
-    "fmt"
-    "net/http"
-    "strings"
-    "time"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
-    "github.com/jeessy2/ddns-go/config"
-    "github.com/jeessy2/ddns-go/util"
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
 )
 
 const (
-    zonesAPI = "https://api.cloudflare.com/client/v4/zones"
+	zonesAPI = "https://api.cloudflare.com/client/v4/zones"
+	// Cloudflare 的TTL为1时表示自动
+	cloudflareAutoTTL = 1
 )
 
+// Cloudflare 表示zone不存在或无权访问的错误码
+var cloudflareZoneNotFoundCodes = map[int]bool{
+	1001: true, // Invalid zone identifier
+	7003: true, // Could not route to ..., perhaps your object identifier is invalid?
+}
+
+// errCloudflareZoneNotFound zone不存在, 需重新查询zone ID
+var errCloudflareZoneNotFound = errors.New("zone not found")
+
+// cloudflareZoneKey 不同的令牌可访问的zone不同, 按令牌和根域名缓存
+type cloudflareZoneKey struct {
+	id, secret, domainName string
+}
+
+// 根域名对应的zone ID, 跨运行周期复用, zone不存在时删除
+var cloudflareZones = struct {
+	sync.Mutex
+	ids map[cloudflareZoneKey]string
+}{ids: map[cloudflareZoneKey]string{}}
+
+// Cloudflare Cloudflare实现
+// DNS.ID 为Email时使用 Global API Key 认证, 为空时使用 API Token 认证
 type Cloudflare struct {
-    DNSConfig
-    Domains config.Domains
+	DNS     config.DNS
+	Domains config.Domains
+	TTL     int
+	Proxied bool
+	Comment string
+	Tags    []string
+	// 是否删除重复的记录
+	CleanDuplicates bool
+	client          *http.Client
 }
 
+// CloudflareResponse zones返回结果
 type CloudflareResponse struct {
-    Success  bool                   `json:"success"`
-    Messages []string               `json:"messages"`
-    Errors   []CloudflareError      `json:"errors"`
-    Result   []CloudflareZoneResult `json:"result"`
+	Success    bool                   `json:"success"`
+	Messages   []string               `json:"messages"`
+	Errors     []CloudflareError      `json:"errors"`
+	Result     []CloudflareZoneResult `json:"result"`
+	ResultInfo CloudflareResultInfo   `json:"result_info"`
 }
 
+// CloudflareRecordsResp records返回结果
 type CloudflareRecordsResp struct {
-    Success  bool                     `json:"success"`
-    Messages []string                 `json:"messages"`
-    Errors   []CloudflareError        `json:"errors"`
-    Result   []CloudflareRecordResult `json:"result"`
+	Success    bool                     `json:"success"`
+	Messages   []string                 `json:"messages"`
+	Errors     []CloudflareError        `json:"errors"`
+	Result     []CloudflareRecordResult `json:"result"`
+	ResultInfo CloudflareResultInfo     `json:"result_info"`
+}
+
+// CloudflareResultInfo 分页信息
+type CloudflareResultInfo struct {
+	Page       int `json:"page"`
+	PerPage    int `json:"per_page"`
+	TotalCount int `json:"total_count"`
+	TotalPages int `json:"total_pages"`
 }
 
+// CloudflareError 错误信息
 type CloudflareError struct {
-    Code    int    `json:"code"`
-    Message string `json:"message"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
 }
 
+// CloudflareZoneResult zone实体
 type CloudflareZoneResult struct {
-    ID   string `json:"id"`
-    Name string `json:"name"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
 }
 
+// CloudflareRecordResult 记录实体
 type CloudflareRecordResult struct {
-    ID        string `json:"id"`
-    Type      string `json:"type"`
-    Name      string `json:"name"`
-    Content   string `json:"content"`
-    Proxied   bool   `json:"proxied"`
-    CreatedOn string `json:"created_on"`
-    ModifiedOn string `json:"modified_on"`
-}
-
-func NewCloudflare(dnsConfig DNSConfig) *Cloudflare {
-    return &Cloudflare{DNSConfig: dnsConfig}
-}
-
-func (cf *Cloudflare) AddUpdateDomainRecords() config.Domains {
-    cf.addUpdateDomainRecords("A")
-    cf.addUpdateDomainRecords("AAAA")
-    return cf.Domains
-}
-
-func (cf *Cloudflare) addUpdateDomainRecords(recordType string) {
-    ipAddr, domains := cf.Domains.GetNewIpResult(recordType)
-    if ipAddr == "" {
-        return
-    }
-
-    for _, domain := range domains {
-        // get zone
-        result, err := cf.getZones(domain)
-        if err != nil {
-            util.Log("查询域名信息发生异常! %s", err)
-            domain.UpdateStatus = config.UpdatedFailed
-            continue
-        }
-        if len(result.Result) == 0 {
-            util.Log("在DNS服务商中未找到根域名: %s", domain.DomainName)
-            domain.UpdateStatus = config.UpdatedFailed
-            continue
-        }
-
-        zoneID := result.Result[0].ID
-        var records CloudflareRecordsResp
-        // 获取现有记录
-        err = cf.request(
-            "GET",
-            fmt.Sprintf(zonesAPI+"/%s/dns_records?type=%s&name=%s&per_page=50", zoneID, recordType, domain.GetSubDomain()+"."+domain.GetTopDomain()),
-            nil, &records,
-        )
-        if err != nil {
-            util.Log("查询域名信息发生异常! %s", err)
-            domain.UpdateStatus = config.UpdatedFailed
-            continue
-        }
-        if !records.Success {
-            util.Log("查询域名信息发生异常! %s", strings.Join(records.Messages, ", "))
-            domain.UpdateStatus = config.UpdatedFailed
-            continue
-        }
-
-        // 根据记录存在与否决定添加或更新
-        if len(records.Result) > 0 {
-            cf.modify(records, zoneID, domain, ipAddr)
-        } else {
-            cf.create(zoneID, domain, recordType, ipAddr)
-        }
-
-        // 清理多余的相同解析记录
-        cf.cleanDuplicateRecords(zoneID, recordType, domain, records)
-    }
-}
-
-func (cf *Cloudflare) getZones(domain config.Domain) (*CloudflareResponse, error) {
-    var result CloudflareResponse
-    err := cf.request("GET", zonesAPI+"?name="+domain.GetTopDomain(), nil, &result)
-    return &result, err
-}
-
-func (cf *Cloudflare) create(zoneID string, domain config.Domain, recordType, ipAddr string) {
-    record := map[string]interface{}{
-        "type":    recordType,
-        "name":    domain.GetSubDomain(),
-        "content": ipAddr,
-        "ttl":     cf.TTL,
-        "proxied": cf.Proxy,
-    }
-
-    var result CloudflareResponse
-    err := cf.request("POST", fmt.Sprintf(zonesAPI+"/%s/dns_records", zoneID), record, &result)
-    if err != nil || !result.Success {
-        util.Log("添加DNS记录失败! %s", strings.Join(result.Messages, ", "))
-        domain.UpdateStatus = config.UpdatedFailed
-    } else {
-        util.Log("添加DNS记录成功!")
-        domain.UpdateStatus = config.UpdatedSuccess
-    }
-}
-
-func (cf *Cloudflare) modify(records CloudflareRecordsResp, zoneID string, domain config.Domain, ipAddr string) {
-    record := map[string]interface{}{
-        "type":    records.Result[0].Type,
-        "name":    records.Result[0].Name,
-        "content": ipAddr,
-        "ttl":     cf.TTL,
-        "proxied": records.Result[0].Proxied,
-    }
-
-    var result CloudflareResponse
-    err := cf.request("PUT", fmt.Sprintf(zonesAPI+"/%s/dns_records/%s", zoneID, records.Result[0].ID), record, &result)
-    if err != nil || !result.Success {
-        util.Log("更新DNS记录失败! %s", strings.Join(result.Messages, ", "))
-        domain.UpdateStatus = config.UpdatedFailed
-    } else {
-        util.Log("更新DNS记录成功!")
-        domain.UpdateStatus = config.UpdatedSuccess
-    }
-}
-
-func (cf *Cloudflare) cleanDuplicateRecords(zoneID, recordType string, domain config.Domain, records CloudflareRecordsResp) {
-    // 获取最新的解析记录ID
-    var latestRecordID string
-    latestTime := time.Time{}
-    for _, record := range records.Result {
-        // 比较解析记录的创建时间或修改时间，找到最新的记录
-        recordTime, err := time.Parse(time.RFC3339, record.CreatedOn)
-        if err != nil {
-            recordTime, err = time.Parse(time.RFC3339, record.ModifiedOn)
-            if err != nil {
-                continue
-            }
-        }
-        if recordTime.After(latestTime) {
-            latestTime = recordTime
-            latestRecordID = record.ID
-        }
-    }
-
-    // 删除多余的相同解析记录
-    for _, record := range records.Result {
-        if record.ID != latestRecordID {
-            var result CloudflareResponse
-            err := cf.request("DELETE", fmt.Sprintf(zonesAPI+"/%s/dns_records/%s", zoneID, record.ID), nil, &result)
-            if err != nil || !result.Success {
-                util.Log("删除多余DNS记录失败! %s", strings.Join(result.Messages, ", "))
-            } else {
-                util.Log("删除多余DNS记录成功!")
-            }
-        }
-    }
-}
-
-func (cf *Cloudflare) request(method, url string, body interface{}, result interface{}) error {
-    client := &http.Client{
-        Timeout: time.Second * 30,
-    }
-    req, err := util.NewJSONRequest(method, url, body)
-    if err != nil {
-        return err
-    }
-
-    req.Header.Set("Authorization", "Bearer "+cf.DNSConfig.Secret)
-    req.Header.Set("Content-Type", "application/json")
-
-    resp, err := client.Do(req)
-    if err != nil {
-        return err
-    }
-    defer resp.Body.Close()
-
-    return util.ParseJSONResponse(resp.Body, result)
+	ID         string   `json:"id"`
+	Type       string   `json:"type"`
+	Name       string   `json:"name"`
+	Content    string   `json:"content"`
+	Priority   int      `json:"priority"`
+	Proxied    bool     `json:"proxied"`
+	TTL        int      `json:"ttl"`
+	Comment    string   `json:"comment"`
+	Tags       []string `json:"tags"`
+	CreatedOn  string   `json:"created_on"`
+	ModifiedOn string   `json:"modified_on"`
+}
+
+func init() {
+	RegisterDNS("cloudflare", func() DNS { return &Cloudflare{} })
+}
+
+// Init 初始化
+func (cf *Cloudflare) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
+	cf.Domains.Ipv4Cache = ipv4cache
+	cf.Domains.Ipv6Cache = ipv6cache
+	cf.DNS = dnsConf.DNS
+	cf.Proxied = dnsConf.Proxied
+	cf.Comment = dnsConf.Comment
+	cf.CleanDuplicates = dnsConf.CleanDuplicates
+	for _, tag := range strings.Split(dnsConf.Tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			cf.Tags = append(cf.Tags, tag)
+		}
+	}
+	cf.Domains.GetNewIp(dnsConf)
+	cf.client = util.CreateHTTPClientTimeout(dnsConf.GetHTTPTimeout())
+	// 默认自动, 1 原样传递, 不调整为最小值
+	cf.TTL = cloudflareAutoTTL
+	if ttl, err := strconv.Atoi(dnsConf.TTL); err == nil && ttl > 0 {
+		cf.TTL = ttl
+	}
+}
+
+// AddUpdateDomainRecords 添加或更新IPv4/IPv6/CNAME/MX记录
+func (cf *Cloudflare) AddUpdateDomainRecords(ctx context.Context) config.Domains {
+	cf.addUpdateDomainRecords(ctx, "A")
+	cf.addUpdateDomainRecords(ctx, "AAAA")
+	cf.addUpdateDomainRecords(ctx, "CNAME")
+	cf.addUpdateDomainRecords(ctx, "MX")
+	return cf.Domains
+}
+
+func (cf *Cloudflare) addUpdateDomainRecords(ctx context.Context, recordType string) {
+	ipAddr, domains := cf.Domains.GetNewIpResult(recordType)
+	// CNAME/MX/TXT 使用每个域名自己的值, 不需要IP
+	if ipAddr == "" && recordType != "CNAME" && recordType != "MX" && recordType != "TXT" {
+		return
+	}
+
+	// 按根域名分组, 每个根域名只查询一次zone
+	groups := make(map[*config.Domain][]*config.Domain)
+	var roots []*config.Domain
+	for _, group := range groupByRootDomain(domains) {
+		roots = append(roots, group[0])
+		groups[group[0]] = group
+	}
+
+	// 按zone分组, 同一zone下的记录只查询一次
+	var zoneIDs []string
+	zoneDomains := make(map[string][]*config.Domain)
+	var zoneLock sync.Mutex
+	forEachDomain(roots, func(root *config.Domain) {
+		group := groups[root]
+		// get zone
+		zoneID, err := cf.getZoneID(ctx, root)
+		if err != nil {
+			util.LogError("查询域名信息发生异常! %s", err)
+			for _, domain := range group {
+				domain.UpdateStatus = config.UpdatedFailed
+			}
+			return
+		}
+		if zoneID == "" {
+			util.LogError("在DNS服务商中未找到根域名: %s", root.DomainName)
+			for _, domain := range group {
+				domain.UpdateStatus = config.UpdatedFailed
+			}
+			return
+		}
+		zoneLock.Lock()
+		defer zoneLock.Unlock()
+		if _, ok := zoneDomains[zoneID]; !ok {
+			zoneIDs = append(zoneIDs, zoneID)
+		}
+		zoneDomains[zoneID] = append(zoneDomains[zoneID], group...)
+	})
+
+	for _, zoneID := range zoneIDs {
+		// 获取zone下的现有记录
+		records, err := cf.getRecords(ctx, zoneID, recordType)
+		if err != nil {
+			if errors.Is(err, errCloudflareZoneNotFound) {
+				forgetZoneID(zoneID)
+			}
+			util.LogError("查询域名信息发生异常! %s", err)
+			for _, domain := range zoneDomains[zoneID] {
+				domain.UpdateStatus = config.UpdatedFailed
+			}
+			continue
+		}
+
+		// 按记录名称索引
+		recordsByName := make(map[string][]CloudflareRecordResult)
+		for _, record := range records.Result {
+			name := strings.ToLower(record.Name)
+			recordsByName[name] = append(recordsByName[name], record)
+		}
+
+		forEachDomain(zoneDomains[zoneID], func(domain *config.Domain) {
+			existing := recordsByName[strings.ToLower(domain.String())]
+			content := ipAddr
+			if recordType == "CNAME" || recordType == "MX" || recordType == "TXT" {
+				content = domain.Target
+			}
+
+			// 根据记录存在与否决定添加或更新
+			if len(existing) > 0 {
+				cf.modify(ctx, existing, zoneID, domain, content)
+			} else {
+				cf.create(ctx, zoneID, domain, recordType, content)
+			}
+
+			// 开启后清理多余的相同解析记录
+			if cf.CleanDuplicates {
+				cf.cleanDuplicateRecords(ctx, zoneID, domain, existing, content)
+			}
+		})
+	}
+}
+
+// SetTXTRecord 设置或替换TXT记录, 不需要获取IP
+func (cf *Cloudflare) SetTXTRecord(ctx context.Context, domain *config.Domain, value string) {
+	domain.Target = value
+	cf.Domains.TxtDomains = []*config.Domain{domain}
+	cf.addUpdateDomainRecords(ctx, "TXT")
+}
+
+// getZoneID 获得根域名的zone ID, 已配置 Zone ID 时直接使用, 不再查询
+// 仅有单个区域权限的令牌无法列出zones, 需填写 Zone ID
+// 查询到的zone ID会被缓存, 之后的运行不再查询
+func (cf *Cloudflare) getZoneID(ctx context.Context, domain *config.Domain) (string, error) {
+	if cf.DNS.ZoneID != "" {
+		return cf.DNS.ZoneID, nil
+	}
+
+	key := cloudflareZoneKey{id: cf.DNS.ID, secret: cf.DNS.Secret, domainName: domain.DomainName}
+	cloudflareZones.Lock()
+	zoneID, ok := cloudflareZones.ids[key]
+	cloudflareZones.Unlock()
+	if ok {
+		return zoneID, nil
+	}
+
+	result, err := cf.getZones(ctx, domain)
+	if err != nil {
+		return "", err
+	}
+	if len(result.Result) == 0 {
+		return "", nil
+	}
+
+	cloudflareZones.Lock()
+	cloudflareZones.ids[key] = result.Result[0].ID
+	cloudflareZones.Unlock()
+	return result.Result[0].ID, nil
+}
+
+// forgetZoneID zone不存在时删除缓存, 下次运行重新查询
+func forgetZoneID(zoneID string) {
+	cloudflareZones.Lock()
+	defer cloudflareZones.Unlock()
+	for key, id := range cloudflareZones.ids {
+		if id == zoneID {
+			delete(cloudflareZones.ids, key)
+		}
+	}
+}
+
+// cloudflareStatus 所有接口返回结果中的状态部分
+type cloudflareStatus struct {
+	Success  bool              `json:"success"`
+	Messages []string          `json:"messages"`
+	Errors   []CloudflareError `json:"errors"`
+}
+
+// cloudflareError 合并HTTP状态码及返回的错误码和错误信息, 如 HTTP 403: [9109] Invalid access token
+// zone不存在时返回 errCloudflareZoneNotFound
+func cloudflareError(statusCode int, errs []CloudflareError, messages []string) error {
+	details := make([]string, 0, len(errs)+len(messages))
+	notFound := false
+	for _, e := range errs {
+		notFound = notFound || cloudflareZoneNotFoundCodes[e.Code]
+		details = append(details, fmt.Sprintf("[%d] %s", e.Code, e.Message))
+	}
+	details = append(details, messages...)
+
+	msg := fmt.Sprintf("HTTP %d: %s", statusCode, strings.Join(details, ", "))
+	if notFound {
+		return fmt.Errorf("%w: %s", errCloudflareZoneNotFound, msg)
+	}
+	return errors.New(msg)
+}
+
+func (cf *Cloudflare) getZones(ctx context.Context, domain *config.Domain) (*CloudflareResponse, error) {
+	var result CloudflareResponse
+	err := cf.request(ctx, "GET", zonesAPI+"?name="+domain.DomainName, nil, &result)
+	return &result, err
+}
+
+// ValidateDomains 列出令牌可访问的全部zone, 返回不在其中的根域名
+// 查询到的zone ID会被缓存, 首次更新时不再逐个查询
+// 已配置 Zone ID 时令牌可能只有单个区域的权限, 不检查
+func (cf *Cloudflare) ValidateDomains(ctx context.Context) (missing []string, err error) {
+	if cf.DNS.ZoneID != "" {
+		return nil, nil
+	}
+
+	zones := make(map[string]string)
+	for page := 1; ; page++ {
+		var result CloudflareResponse
+		err := cf.request(ctx, "GET", fmt.Sprintf(zonesAPI+"?per_page=50&page=%d", page), nil, &result)
+		if err != nil {
+			return nil, err
+		}
+		for _, zone := range result.Result {
+			zones[strings.ToLower(zone.Name)] = zone.ID
+		}
+		if page >= result.ResultInfo.TotalPages {
+			break
+		}
+	}
+
+	cloudflareZones.Lock()
+	defer cloudflareZones.Unlock()
+	checked := make(map[string]bool)
+	for _, list := range [][]*config.Domain{cf.Domains.Ipv4Domains, cf.Domains.Ipv6Domains, cf.Domains.CnameDomains, cf.Domains.MxDomains} {
+		for _, domain := range list {
+			name := strings.ToLower(domain.DomainName)
+			if checked[name] {
+				continue
+			}
+			checked[name] = true
+			if zoneID, ok := zones[name]; ok {
+				cloudflareZones.ids[cloudflareZoneKey{id: cf.DNS.ID, secret: cf.DNS.Secret, domainName: domain.DomainName}] = zoneID
+			} else {
+				missing = append(missing, domain.DomainName)
+			}
+		}
+	}
+	return missing, nil
+}
+
+// getRecords 获得zone下指定类型的全部记录, 超过一页时继续获取后续页
+func (cf *Cloudflare) getRecords(ctx context.Context, zoneID, recordType string) (*CloudflareRecordsResp, error) {
+	var records CloudflareRecordsResp
+	for page := 1; ; page++ {
+		var pageRecords CloudflareRecordsResp
+		err := cf.request(
+			ctx,
+			"GET",
+			fmt.Sprintf(zonesAPI+"/%s/dns_records?type=%s&per_page=100&page=%d", zoneID, recordType, page),
+			nil, &pageRecords,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		records.Success = true
+		records.Result = append(records.Result, pageRecords.Result...)
+		records.ResultInfo = pageRecords.ResultInfo
+		if page >= pageRecords.ResultInfo.TotalPages {
+			return &records, nil
+		}
+	}
+}
+
+func (cf *Cloudflare) create(ctx context.Context, zoneID string, domain *config.Domain, recordType, ipAddr string) {
+	if skipCreate(domain) {
+		return
+	}
+	// 使用完整域名, 根域名和泛解析 * 都不会被误认为相对名称
+	record := map[string]interface{}{
+		"type":    recordType,
+		"name":    domain.String(),
+		"content": ipAddr,
+		"ttl":     domain.GetTTL(cf.TTL),
+		"proxied": domain.GetProxied(cf.Proxied),
+	}
+	// MX记录需要优先级, 且不能开启代理
+	if recordType == "MX" {
+		record["priority"] = domain.Priority
+		record["proxied"] = false
+	}
+	if cf.Comment != "" {
+		record["comment"] = cf.Comment
+	}
+	if len(cf.Tags) > 0 {
+		record["tags"] = cf.Tags
+	}
+
+	if dryRun(domain, "POST", fmt.Sprintf(zonesAPI+"/%s/dns_records", zoneID), record) {
+		return
+	}
+
+	var result CloudflareResponse
+	err := cf.request(ctx, "POST", fmt.Sprintf(zonesAPI+"/%s/dns_records", zoneID), record, &result)
+	if err != nil {
+		util.LogError("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+	} else {
+		util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
+		domain.UpdateStatus = config.UpdatedSuccess
+	}
+}
+
+func (cf *Cloudflare) modify(ctx context.Context, records []CloudflareRecordResult, zoneID string, domain *config.Domain, ipAddr string) {
+	// 保留用户已设置的备注和标签, 为空时才使用配置的值
+	comment := records[0].Comment
+	if comment == "" {
+		comment = cf.Comment
+	}
+	tags := records[0].Tags
+	if len(tags) == 0 {
+		tags = cf.Tags
+	}
+
+	// 相同不修改, 开启代理的记录TTL固定为自动, 不参与比较
+	proxied := domain.GetProxied(cf.Proxied)
+	if records[0].Type == "MX" {
+		proxied = false
+	}
+	if records[0].Content == ipAddr && records[0].Proxied == proxied && records[0].Priority == domain.Priority &&
+		(records[0].TTL == domain.GetTTL(cf.TTL) || records[0].Proxied) &&
+		comment == records[0].Comment && len(tags) == len(records[0].Tags) {
+		util.LogDebug("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		domain.UpdateStatus = config.UpdatedNothing
+		return
+	}
+
+	record := map[string]interface{}{
+		"type":    records[0].Type,
+		"name":    records[0].Name,
+		"content": ipAddr,
+		"ttl":     domain.GetTTL(cf.TTL),
+		"proxied": proxied,
+	}
+	if records[0].Type == "MX" {
+		record["priority"] = domain.Priority
+	}
+	if comment != "" {
+		record["comment"] = comment
+	}
+	if len(tags) > 0 {
+		record["tags"] = tags
+	}
+
+	if dryRun(domain, "PUT", fmt.Sprintf(zonesAPI+"/%s/dns_records/%s", zoneID, records[0].ID), record) {
+		return
+	}
+
+	var result CloudflareResponse
+	err := cf.request(ctx, "PUT", fmt.Sprintf(zonesAPI+"/%s/dns_records/%s", zoneID, records[0].ID), record, &result)
+	if err != nil {
+		util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+	} else {
+		util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+		domain.UpdateStatus = config.UpdatedSuccess
+	}
+}
+
+func (cf *Cloudflare) cleanDuplicateRecords(ctx context.Context, zoneID string, domain *config.Domain, records []CloudflareRecordResult, ipAddr string) {
+	keepID := keepRecordID(records, ipAddr)
+	if keepID == "" {
+		return
+	}
+
+	// 删除多余的相同解析记录
+	for _, record := range records {
+		if record.ID == keepID {
+			continue
+		}
+		if dryRun(domain, "DELETE", fmt.Sprintf(zonesAPI+"/%s/dns_records/%s", zoneID, record.ID), nil) {
+			continue
+		}
+		var result cloudflareStatus
+		err := cf.request(ctx, "DELETE", fmt.Sprintf(zonesAPI+"/%s/dns_records/%s", zoneID, record.ID), nil, &result)
+		if err != nil {
+			util.LogError("删除多余域名解析 %s 失败! 异常信息: %s", domain, err)
+		} else {
+			util.Log("删除多余域名解析 %s 成功!", domain)
+		}
+	}
+}
+
+// DeleteRecords 删除域名名称相同的全部 recordType 记录
+func (cf *Cloudflare) DeleteRecords(ctx context.Context, recordType string, domains []*config.Domain) {
+	forEachDomain(domains, func(domain *config.Domain) {
+		zoneID, err := cf.getZoneID(ctx, domain)
+		if err != nil {
+			util.LogError("查询域名信息发生异常! %s", err)
+			return
+		}
+		if zoneID == "" {
+			util.LogError("在DNS服务商中未找到根域名: %s", domain.DomainName)
+			return
+		}
+
+		records, err := cf.getRecords(ctx, zoneID, recordType)
+		if err != nil {
+			util.LogError("查询域名信息发生异常! %s", err)
+			return
+		}
+		for _, record := range records.Result {
+			if !strings.EqualFold(record.Name, domain.String()) {
+				continue
+			}
+			recordURL := fmt.Sprintf(zonesAPI+"/%s/dns_records/%s", zoneID, record.ID)
+			if dryRun(domain, "DELETE", recordURL, nil) {
+				continue
+			}
+			var result cloudflareStatus
+			err := cf.request(ctx, "DELETE", recordURL, nil, &result)
+			if err != nil {
+				util.LogError("删除域名解析 %s 失败! 异常信息: %s", domain, err)
+			} else {
+				util.Log("删除域名解析 %s 成功!", domain)
+			}
+		}
+	})
+}
+
+// keepRecordID 获得需要保留的记录ID, 优先保留创建或修改时间最新的记录
+// 时间都无法解析时保留内容为当前IP的记录, 都不是则保留第一条
+func keepRecordID(records []CloudflareRecordResult, ipAddr string) string {
+	var keepID string
+	var latestTime time.Time
+	for _, record := range records {
+		recordTime, err := time.Parse(time.RFC3339, record.CreatedOn)
+		if err != nil {
+			recordTime, err = time.Parse(time.RFC3339, record.ModifiedOn)
+		}
+		if err == nil && recordTime.After(latestTime) {
+			latestTime = recordTime
+			keepID = record.ID
+		}
+	}
+	if keepID != "" {
+		return keepID
+	}
+
+	for _, record := range records {
+		if record.Content == ipAddr {
+			return record.ID
+		}
+	}
+	if len(records) > 0 {
+		return records[0].ID
+	}
+	return ""
+}
+
+// Check 获取zone, 检查令牌是否正确
+func (cf *Cloudflare) Check(ctx context.Context) error {
+	url := zonesAPI + "?per_page=1"
+	if cf.DNS.ZoneID != "" {
+		url = zonesAPI + "/" + cf.DNS.ZoneID
+	}
+	var result cloudflareStatus
+	return cf.request(ctx, "GET", url, nil, &result)
+}
+
+// request 统一请求接口, success 为 false 时返回包含HTTP状态码和错误码的错误
+func (cf *Cloudflare) request(ctx context.Context, method, url string, body interface{}, result interface{}) error {
+	req, err := util.NewJSONRequest(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+
+	if cf.DNS.AuthMode == "global" {
+		// 使用 Global API Key 认证, ID 为账号Email
+		req.Header.Set("X-Auth-Email", cf.DNS.ID)
+		req.Header.Set("X-Auth-Key", cf.DNS.Secret)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+cf.DNS.Secret)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := cf.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var raw json.RawMessage
+	err = util.ParseJSONResponse(resp, &raw)
+	var statusErr *util.HTTPStatusError
+	if errors.As(err, &statusErr) {
+		// 返回内容不是Cloudflare的错误格式时, 如代理返回的502页面, 直接返回状态码和内容
+		var status cloudflareStatus
+		if json.Unmarshal(statusErr.Body, &status) != nil || len(status.Errors)+len(status.Messages) == 0 {
+			return err
+		}
+		return cloudflareError(resp.StatusCode, status.Errors, status.Messages)
+	}
+	if err != nil || len(raw) == 0 {
+		return err
+	}
+
+	var status cloudflareStatus
+	if err = json.Unmarshal(raw, &status); err != nil {
+		return err
+	}
+	if !status.Success {
+		return cloudflareError(resp.StatusCode, status.Errors, status.Messages)
+	}
+	return json.Unmarshal(raw, result)
 }
@@ -0,0 +1,233 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// TestKeepRecordID 测试 keepRecordID
+func TestKeepRecordID(t *testing.T) {
+	tests := []struct {
+		name    string
+		records []CloudflareRecordResult
+		ipAddr  string
+		want    string
+	}{
+		{
+			name: "latest created",
+			records: []CloudflareRecordResult{
+				{ID: "1", Content: "1.1.1.1", CreatedOn: "2023-01-01T00:00:00Z"},
+				{ID: "2", Content: "2.2.2.2", CreatedOn: "2023-06-01T00:00:00Z"},
+			},
+			ipAddr: "1.1.1.1",
+			want:   "2",
+		},
+		{
+			name: "fallback to modified",
+			records: []CloudflareRecordResult{
+				{ID: "1", CreatedOn: "bad", ModifiedOn: "2023-06-01T00:00:00Z"},
+				{ID: "2", CreatedOn: "2023-01-01T00:00:00Z"},
+			},
+			want: "1",
+		},
+		{
+			name: "malformed keeps current ip",
+			records: []CloudflareRecordResult{
+				{ID: "1", Content: "1.1.1.1", CreatedOn: "bad", ModifiedOn: "bad"},
+				{ID: "2", Content: "2.2.2.2", CreatedOn: "", ModifiedOn: ""},
+			},
+			ipAddr: "2.2.2.2",
+			want:   "2",
+		},
+		{
+			name: "malformed keeps first",
+			records: []CloudflareRecordResult{
+				{ID: "1", Content: "1.1.1.1", CreatedOn: "bad"},
+				{ID: "2", Content: "2.2.2.2", ModifiedOn: "bad"},
+			},
+			ipAddr: "3.3.3.3",
+			want:   "1",
+		},
+		{
+			name: "empty",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := keepRecordID(tt.records, tt.ipAddr); got != tt.want {
+			t.Errorf("%s: 期待 %s, 得到 %s", tt.name, tt.want, got)
+		}
+	}
+}
+
+// TestCloudflareRequestCanceled 测试 ctx 取消后结束进行中的请求
+func TestCloudflareRequestCanceled(t *testing.T) {
+	block := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-block:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(block)
+
+	cf := &Cloudflare{client: util.CreateHTTPClient()}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	var result CloudflareResponse
+	err := cf.request(ctx, http.MethodGet, server.URL, nil, &result)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("期待 context.DeadlineExceeded, 得到 %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("请求未被及时取消")
+	}
+}
+
+// TestCloudflareZoneCache 测试zone ID缓存, zone不存在时删除
+func TestCloudflareZoneCache(t *testing.T) {
+	cf := &Cloudflare{DNS: config.DNS{Secret: "token"}}
+	domain := &config.Domain{DomainName: "example.com"}
+	key := cloudflareZoneKey{secret: "token", domainName: "example.com"}
+	cloudflareZones.Lock()
+	cloudflareZones.ids[key] = "zone1"
+	cloudflareZones.Unlock()
+
+	// 已缓存时不再请求
+	zoneID, err := cf.getZoneID(context.Background(), domain)
+	if err != nil || zoneID != "zone1" {
+		t.Fatalf("期待缓存的 zone1, 得到 %q %v", zoneID, err)
+	}
+
+	err = cloudflareError(http.StatusBadRequest, []CloudflareError{{Code: 7003, Message: "Could not route"}}, nil)
+	if !errors.Is(err, errCloudflareZoneNotFound) {
+		t.Fatalf("期待 zone 不存在, 得到 %v", err)
+	}
+	if err := cloudflareError(http.StatusForbidden, []CloudflareError{{Code: 9109, Message: "Unauthorized"}}, nil); errors.Is(err, errCloudflareZoneNotFound) {
+		t.Errorf("期待 Unauthorized, 得到 %v", err)
+	}
+
+	forgetZoneID("zone1")
+	cloudflareZones.Lock()
+	_, ok := cloudflareZones.ids[key]
+	cloudflareZones.Unlock()
+	if ok {
+		t.Error("zone 不存在时应删除缓存")
+	}
+}
+
+// TestCloudflareRequestErrors 测试 success 为 false 时返回HTTP状态码、错误码和错误信息
+func TestCloudflareRequestErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"success":false,"errors":[{"code":9109,"message":"Invalid access token"}],"messages":[]}`))
+	}))
+	defer server.Close()
+
+	cf := &Cloudflare{client: util.CreateHTTPClient()}
+	var result CloudflareResponse
+	err := cf.request(context.Background(), http.MethodGet, server.URL, nil, &result)
+	want := "HTTP 403: [9109] Invalid access token"
+	if err == nil || err.Error() != want {
+		t.Errorf("期待 %s, 得到 %v", want, err)
+	}
+}
+
+// TestCloudflareAuthMode 测试只有 global 时使用 Global API Key 认证, 即使填写了ID
+func TestCloudflareAuthMode(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		w.Write([]byte(`{"success":true}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		authMode, auth, email, key string
+	}{
+		{"token", "Bearer secret", "", ""},
+		{"", "Bearer secret", "", ""},
+		{"global", "", "user@example.com", "secret"},
+	}
+	for _, tt := range tests {
+		cf := &Cloudflare{DNS: config.DNS{ID: "user@example.com", Secret: "secret", AuthMode: tt.authMode}, client: util.CreateHTTPClient()}
+		var result cloudflareStatus
+		if err := cf.request(context.Background(), http.MethodGet, server.URL, nil, &result); err != nil {
+			t.Fatal(err)
+		}
+		if header.Get("Authorization") != tt.auth || header.Get("X-Auth-Email") != tt.email || header.Get("X-Auth-Key") != tt.key {
+			t.Errorf("AuthMode %q 请求头不正确: %v", tt.authMode, header)
+		}
+	}
+}
+
+// TestCloudflareTTL 测试TTL为空或1时为自动, 其它值原样传递
+func TestCloudflareTTL(t *testing.T) {
+	tests := map[string]int{"": cloudflareAutoTTL, "1": cloudflareAutoTTL, "abc": cloudflareAutoTTL, "30": 30, "120": 120}
+	for ttl, want := range tests {
+		cf := &Cloudflare{}
+		cf.Init(&config.DnsConfig{TTL: ttl}, &util.IpCache{}, &util.IpCache{})
+		if cf.TTL != want {
+			t.Errorf("TTL %q 期待 %d, 得到 %d", ttl, want, cf.TTL)
+		}
+	}
+	if got := checkMinTTL("Porkbun", 60, porkbunMinTTL); got != porkbunMinTTL {
+		t.Errorf("期待调整为 %d, 得到 %d", porkbunMinTTL, got)
+	}
+}
+
+// rewriteTransport 将所有请求发送到测试服务器
+type rewriteTransport struct {
+	url *url.URL
+}
+
+func (rt rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = rt.url.Scheme
+	req.URL.Host = rt.url.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// TestCloudflareValidateDomains 测试列出全部zone, 返回不在账号中的根域名并缓存zone ID
+func TestCloudflareValidateDomains(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "1" {
+			w.Write([]byte(`{"success":true,"result":[{"id":"zone1","name":"example.com"}],"result_info":{"total_pages":2}}`))
+			return
+		}
+		w.Write([]byte(`{"success":true,"result":[{"id":"zone2","name":"Example.NET"}],"result_info":{"total_pages":2}}`))
+	}))
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+
+	cf := &Cloudflare{
+		DNS:    config.DNS{Secret: "validate"},
+		client: &http.Client{Transport: rewriteTransport{serverURL}},
+	}
+	cf.Domains.Ipv4Domains = []*config.Domain{{DomainName: "example.com", SubDomain: "www"}, {DomainName: "exmaple.com"}}
+	cf.Domains.Ipv6Domains = []*config.Domain{{DomainName: "example.net"}, {DomainName: "exmaple.com", SubDomain: "www"}}
+
+	missing, err := cf.ValidateDomains(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(missing) != 1 || missing[0] != "exmaple.com" {
+		t.Errorf("期待 [exmaple.com], 得到 %v", missing)
+	}
+
+	zoneID, err := cf.getZoneID(context.Background(), &config.Domain{DomainName: "example.net"})
+	if err != nil || zoneID != "zone2" {
+		t.Errorf("期待缓存的 zone2, 得到 %q %v", zoneID, err)
+	}
+}
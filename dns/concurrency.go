@@ -0,0 +1,57 @@
+package dns
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+)
+
+// forEachDomain 同时对最多 Concurrency 个域名调用 fn, 全部完成后返回
+// 每个域名只在一个 goroutine 中处理, fn 可直接修改 domain.UpdateStatus
+// 请求频率仍由 util 中按主机的限速控制
+func forEachDomain(domains []*config.Domain, fn func(domain *config.Domain)) {
+	workers := Concurrency
+	if workers > len(domains) {
+		workers = len(domains)
+	}
+	if workers <= 1 {
+		for _, domain := range domains {
+			fn(domain)
+		}
+		return
+	}
+
+	ch := make(chan *config.Domain)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for domain := range ch {
+				fn(domain)
+			}
+		}()
+	}
+	for _, domain := range domains {
+		ch <- domain
+	}
+	close(ch)
+	wg.Wait()
+}
+
+// groupByRootDomain 按根域名(不区分大小写)分组, 保持域名首次出现的顺序
+func groupByRootDomain(domains []*config.Domain) (groups [][]*config.Domain) {
+	index := make(map[string]int)
+	for _, domain := range domains {
+		name := strings.ToLower(domain.DomainName)
+		i, ok := index[name]
+		if !ok {
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], domain)
+	}
+	return
+}
@@ -0,0 +1,110 @@
+package dns
+
+import (
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+)
+
+func testDomains(n int) []*config.Domain {
+	domains := make([]*config.Domain, n)
+	for i := range domains {
+		domains[i] = &config.Domain{DomainName: "example.com", SubDomain: "d" + strconv.Itoa(i)}
+	}
+	return domains
+}
+
+// TestForEachDomain 测试每个域名都只处理一次, 且同时处理的数量不超过 Concurrency
+func TestForEachDomain(t *testing.T) {
+	defer func(c int) { Concurrency = c }(Concurrency)
+	Concurrency = 3
+
+	domains := testDomains(20)
+	var running, maxRunning int32
+	forEachDomain(domains, func(domain *config.Domain) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		domain.UpdateStatus = config.UpdatedSuccess
+		atomic.AddInt32(&running, -1)
+	})
+
+	for _, domain := range domains {
+		if domain.UpdateStatus != config.UpdatedSuccess {
+			t.Errorf("域名 %s 未处理", domain)
+		}
+	}
+	if maxRunning > 3 {
+		t.Errorf("期待最多同时处理 3 个, 得到 %d 个", maxRunning)
+	}
+}
+
+// TestGroupByRootDomain 测试按根域名分组, 不区分大小写并保持顺序
+func TestGroupByRootDomain(t *testing.T) {
+	domains := []*config.Domain{
+		{DomainName: "example.com", SubDomain: "a"},
+		{DomainName: "example.org", SubDomain: "b"},
+		{DomainName: "Example.com", SubDomain: "c"},
+		{DomainName: "example.com"},
+	}
+	groups := groupByRootDomain(domains)
+	if len(groups) != 2 || len(groups[0]) != 3 || len(groups[1]) != 1 {
+		t.Fatalf("期待 2 组 3/1 个域名, 得到 %v", groups)
+	}
+	if groups[0][1] != domains[2] || groups[1][0] != domains[1] {
+		t.Error("分组后应保持原有顺序")
+	}
+}
+
+// benchmarkForEachDomain 模拟每个域名需要 5ms 的请求
+func benchmarkForEachDomain(b *testing.B, concurrency int) {
+	defer func(c int) { Concurrency = c }(Concurrency)
+	Concurrency = concurrency
+
+	domains := testDomains(50)
+	for i := 0; i < b.N; i++ {
+		forEachDomain(domains, func(domain *config.Domain) {
+			time.Sleep(5 * time.Millisecond)
+		})
+	}
+}
+
+func BenchmarkForEachDomainSequential(b *testing.B) { benchmarkForEachDomain(b, 1) }
+
+func BenchmarkForEachDomainConcurrency5(b *testing.B) { benchmarkForEachDomain(b, 5) }
+
+// TestWithJitter 测试随机偏移在 ±Jitter 内, 且不超过间隔时间的1/10
+func TestWithJitter(t *testing.T) {
+	defer func(j time.Duration) { Jitter = j }(Jitter)
+
+	Jitter = 15 * time.Second
+	tests := []struct {
+		interval time.Duration
+		max      time.Duration
+	}{
+		{300 * time.Second, 15 * time.Second},
+		{10 * time.Second, time.Second},
+		{0, 0},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			got := withJitter(tt.interval)
+			if got < tt.interval-tt.max || got > tt.interval+tt.max {
+				t.Fatalf("间隔 %s 期待偏移不超过 %s, 得到 %s", tt.interval, tt.max, got)
+			}
+		}
+	}
+
+	Jitter = 0
+	if got := withJitter(300 * time.Second); got != 300*time.Second {
+		t.Errorf("期待不偏移, 得到 %s", got)
+	}
+}
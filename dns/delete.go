@@ -0,0 +1,38 @@
+package dns
+
+import (
+	"context"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// RecordDeleter 支持获取不到IP时删除记录的DNS服务商
+type RecordDeleter interface {
+	DNS
+	// DeleteRecords 删除域名名称相同的全部 recordType 记录
+	DeleteRecords(ctx context.Context, recordType string, domains []*config.Domain)
+}
+
+// deleteUnavailable 开启后连续3次获取不到IP时删除A/AAAA记录
+// 此时域名已被标记为失败, IP缓存会被重置, 再次获取到IP后重新新增记录
+func deleteUnavailable(ctx context.Context, dnsSelected DNS, dc *config.DnsConfig, domains *config.Domains) {
+	if !dc.DeleteOnNoIP {
+		return
+	}
+
+	del := func(ipType, recordType, ipAddr string, cache *util.IpCache, list []*config.Domain) {
+		if ipAddr != "" || cache.TimesFailedIP != 3 || len(list) == 0 {
+			return
+		}
+		deleter, ok := dnsSelected.(RecordDeleter)
+		if !ok {
+			util.LogWarn("%s 不支持获取不到IP时删除记录", dc.DNS.Name)
+			return
+		}
+		util.LogWarn("连续3次未能获取%s地址, 将删除%s记录", ipType, recordType)
+		deleter.DeleteRecords(ctx, recordType, list)
+	}
+	del("IPv4", "A", domains.Ipv4Addr, domains.Ipv4Cache, domains.Ipv4Domains)
+	del("IPv6", "AAAA", domains.Ipv6Addr, domains.Ipv6Cache, domains.Ipv6Domains)
+}
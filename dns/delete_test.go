@@ -0,0 +1,55 @@
+package dns
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// fakeDeleter 记录被删除的记录类型
+type fakeDeleter struct {
+	deleted []string
+}
+
+func (f *fakeDeleter) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
+}
+
+func (f *fakeDeleter) AddUpdateDomainRecords(ctx context.Context) config.Domains {
+	return config.Domains{}
+}
+
+func (f *fakeDeleter) DeleteRecords(ctx context.Context, recordType string, domains []*config.Domain) {
+	f.deleted = append(f.deleted, recordType)
+}
+
+// TestDeleteUnavailable 测试连续3次获取不到IP时才删除记录
+func TestDeleteUnavailable(t *testing.T) {
+	domain := &config.Domain{DomainName: "example.com"}
+	newDomains := func(timesFailedIP int) *config.Domains {
+		return &config.Domains{
+			Ipv4Addr:    "1.2.3.4",
+			Ipv4Cache:   &util.IpCache{},
+			Ipv4Domains: []*config.Domain{domain},
+			Ipv6Cache:   &util.IpCache{TimesFailedIP: timesFailedIP},
+			Ipv6Domains: []*config.Domain{domain},
+		}
+	}
+
+	deleter := &fakeDeleter{}
+	deleteUnavailable(context.Background(), deleter, &config.DnsConfig{}, newDomains(3))
+	if len(deleter.deleted) != 0 {
+		t.Fatalf("未开启时不应删除, 得到 %v", deleter.deleted)
+	}
+
+	dc := &config.DnsConfig{DeleteOnNoIP: true}
+	deleteUnavailable(context.Background(), deleter, dc, newDomains(2))
+	if len(deleter.deleted) != 0 {
+		t.Fatalf("未满3次不应删除, 得到 %v", deleter.deleted)
+	}
+	deleteUnavailable(context.Background(), deleter, dc, newDomains(3))
+	if len(deleter.deleted) != 1 || deleter.deleted[0] != "AAAA" {
+		t.Errorf("期待删除 AAAA 记录, 得到 %v", deleter.deleted)
+	}
+}
@@ -0,0 +1,151 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+const (
+	desecEndpoint = "https://desec.io/api/v1/domains"
+	// deSEC 允许的最小TTL
+	desecMinTTL = 3600
+)
+
+// DeSEC deSEC实现
+// https://desec.readthedocs.io/en/latest/dns/rrsets.html
+type DeSEC struct {
+	DNS     config.DNS
+	Domains config.Domains
+	TTL     int
+	client  *http.Client
+}
+
+// DeSECRRset 记录集, 同一名称和类型的记录为一组
+type DeSECRRset struct {
+	Subname string   `json:"subname"`
+	Type    string   `json:"type"`
+	TTL     int      `json:"ttl"`
+	Records []string `json:"records"`
+}
+
+func init() {
+	RegisterDNS("desec", func() DNS { return &DeSEC{} })
+}
+
+// Init 初始化
+func (desec *DeSEC) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
+	desec.Domains.Ipv4Cache = ipv4cache
+	desec.Domains.Ipv6Cache = ipv6cache
+	desec.DNS = dnsConf.DNS
+	desec.Domains.GetNewIp(dnsConf)
+	desec.client = util.CreateHTTPClientTimeout(dnsConf.GetHTTPTimeout())
+
+	desec.TTL = desecMinTTL
+	if dnsConf.TTL != "" {
+		ttl, err := strconv.Atoi(dnsConf.TTL)
+		if err == nil {
+			desec.TTL = ttl
+		}
+	}
+	desec.TTL = checkMinTTL("deSEC", desec.TTL, desecMinTTL)
+}
+
+// AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
+func (desec *DeSEC) AddUpdateDomainRecords(ctx context.Context) config.Domains {
+	desec.addUpdateDomainRecords(ctx, "A")
+	desec.addUpdateDomainRecords(ctx, "AAAA")
+	return desec.Domains
+}
+
+func (desec *DeSEC) addUpdateDomainRecords(ctx context.Context, recordType string) {
+	ipAddr, domains := desec.Domains.GetNewIpResult(recordType)
+
+	if ipAddr == "" {
+		return
+	}
+
+	forEachDomain(domains, func(domain *config.Domain) {
+		rrset, err := desec.getRRset(ctx, domain, recordType)
+		if err != nil {
+			util.LogError("查询域名信息发生异常! %s", err)
+			domain.UpdateStatus = config.UpdatedFailed
+			return
+		}
+
+		// 相同不修改
+		if rrset != nil && len(rrset.Records) == 1 && rrset.Records[0] == ipAddr && rrset.TTL == desec.TTL {
+			util.LogDebug("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+			domain.UpdateStatus = config.UpdatedNothing
+			return
+		}
+
+		desec.upsert(ctx, domain, recordType, ipAddr, rrset == nil)
+	})
+}
+
+// getRRset 获得记录集, 不存在时返回nil
+func (desec *DeSEC) getRRset(ctx context.Context, domain *config.Domain, recordType string) (*DeSECRRset, error) {
+	var rrsets []DeSECRRset
+	err := desec.request(
+		ctx,
+		http.MethodGet,
+		fmt.Sprintf(desecEndpoint+"/%s/rrsets/?subname=%s&type=%s", domain.DomainName, domain.SubDomain, recordType),
+		nil,
+		&rrsets,
+	)
+	if err != nil || len(rrsets) == 0 {
+		return nil, err
+	}
+	return &rrsets[0], nil
+}
+
+// upsert 通过批量修改接口新增或替换记录集
+func (desec *DeSEC) upsert(ctx context.Context, domain *config.Domain, recordType string, ipAddr string, isAdd bool) {
+	if isAdd && skipCreate(domain) {
+		return
+	}
+	operation := "更新"
+	if isAdd {
+		operation = "新增"
+	}
+
+	requestURL := fmt.Sprintf(desecEndpoint+"/%s/rrsets/", domain.DomainName)
+	rrsets := []DeSECRRset{{
+		Subname: domain.SubDomain,
+		Type:    recordType,
+		TTL:     desec.TTL,
+		Records: []string{ipAddr},
+	}}
+	if dryRun(domain, http.MethodPatch, requestURL, rrsets) {
+		return
+	}
+
+	var result []DeSECRRset
+	err := desec.request(ctx, http.MethodPatch, requestURL, rrsets, &result)
+	if err != nil {
+		util.LogError(operation+"域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+
+	util.Log(operation+"域名解析 %s 成功! IP: %s", domain, ipAddr)
+	domain.UpdateStatus = config.UpdatedSuccess
+}
+
+// request 统一请求接口
+func (desec *DeSEC) request(ctx context.Context, method, url string, body interface{}, result interface{}) error {
+	req, err := util.NewJSONRequest(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Token "+desec.DNS.Secret)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := desec.client.Do(req)
+	return util.GetHTTPResponse(resp, err, result)
+}
@@ -0,0 +1,238 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+const (
+	digitalOceanEndpoint = "https://api.digitalocean.com/v2/domains"
+	// DigitalOcean 允许的最小TTL
+	digitalOceanMinTTL = 30
+)
+
+// DigitalOcean DigitalOcean实现
+// https://docs.digitalocean.com/reference/api/api-reference/#tag/Domain-Records
+type DigitalOcean struct {
+	DNS             config.DNS
+	Domains         config.Domains
+	TTL             int
+	CleanDuplicates bool
+	client          *http.Client
+}
+
+// DigitalOceanRecord 记录实体
+type DigitalOceanRecord struct {
+	ID   int    `json:"id,omitempty"`
+	Type string `json:"type"`
+	Name string `json:"name"`
+	Data string `json:"data"`
+	TTL  int    `json:"ttl"`
+}
+
+// DigitalOceanRecordsResp records返回结果
+type DigitalOceanRecordsResp struct {
+	DomainRecords []DigitalOceanRecord `json:"domain_records"`
+	Links         struct {
+		Pages struct {
+			Next string `json:"next"`
+		} `json:"pages"`
+	} `json:"links"`
+}
+
+// DigitalOceanRecordResp 新增或更新记录返回结果
+type DigitalOceanRecordResp struct {
+	DomainRecord DigitalOceanRecord `json:"domain_record"`
+}
+
+func init() {
+	RegisterDNS("digitalocean", func() DNS { return &DigitalOcean{} })
+}
+
+// Init 初始化
+func (do *DigitalOcean) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
+	do.Domains.Ipv4Cache = ipv4cache
+	do.Domains.Ipv6Cache = ipv6cache
+	do.DNS = dnsConf.DNS
+	do.CleanDuplicates = dnsConf.CleanDuplicates
+	do.Domains.GetNewIp(dnsConf)
+	do.client = util.CreateHTTPClientTimeout(dnsConf.GetHTTPTimeout())
+	if dnsConf.TTL == "" {
+		// 默认300s
+		do.TTL = 300
+	} else {
+		ttl, err := strconv.Atoi(dnsConf.TTL)
+		if err != nil {
+			do.TTL = 300
+		} else {
+			do.TTL = checkMinTTL("DigitalOcean", ttl, digitalOceanMinTTL)
+		}
+	}
+}
+
+// AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
+func (do *DigitalOcean) AddUpdateDomainRecords(ctx context.Context) config.Domains {
+	do.addUpdateDomainRecords(ctx, "A")
+	do.addUpdateDomainRecords(ctx, "AAAA")
+	return do.Domains
+}
+
+func (do *DigitalOcean) addUpdateDomainRecords(ctx context.Context, recordType string) {
+	ipAddr, domains := do.Domains.GetNewIpResult(recordType)
+
+	if ipAddr == "" {
+		return
+	}
+
+	forEachDomain(domains, func(domain *config.Domain) {
+		records, err := do.getRecords(ctx, domain, recordType)
+		if err != nil {
+			util.LogError("查询域名信息发生异常! %s", err)
+			domain.UpdateStatus = config.UpdatedFailed
+			return
+		}
+
+		if len(records) > 0 {
+			do.modify(ctx, records[0], domain, ipAddr)
+			// 开启后清理多余的相同解析记录
+			if do.CleanDuplicates {
+				do.cleanDuplicateRecords(ctx, records[1:], domain)
+			}
+		} else {
+			do.create(ctx, domain, recordType, ipAddr)
+		}
+	})
+}
+
+// getRecords 获得名称和类型相同的记录, 默认每页20条, 有下一页时继续获取
+func (do *DigitalOcean) getRecords(ctx context.Context, domain *config.Domain, recordType string) ([]DigitalOceanRecord, error) {
+	var records []DigitalOceanRecord
+	nextURL := fmt.Sprintf(digitalOceanEndpoint+"/%s/records?type=%s&name=%s", domain.DomainName, recordType, domain.String())
+	for nextURL != "" {
+		var result DigitalOceanRecordsResp
+		err := do.request(ctx, http.MethodGet, nextURL, nil, &result)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, result.DomainRecords...)
+		nextURL = result.Links.Pages.Next
+	}
+	return records, nil
+}
+
+func (do *DigitalOcean) create(ctx context.Context, domain *config.Domain, recordType string, ipAddr string) {
+	if skipCreate(domain) {
+		return
+	}
+	recordsURL := fmt.Sprintf(digitalOceanEndpoint+"/%s/records", domain.DomainName)
+	record := &DigitalOceanRecord{
+		Type: recordType,
+		Name: domain.GetSubDomain(),
+		Data: ipAddr,
+		TTL:  do.TTL,
+	}
+	if dryRun(domain, http.MethodPost, recordsURL, record) {
+		return
+	}
+
+	var result DigitalOceanRecordResp
+	err := do.request(ctx, http.MethodPost, recordsURL, record, &result)
+	if err != nil {
+		util.LogError("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+
+	util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
+	domain.UpdateStatus = config.UpdatedSuccess
+}
+
+func (do *DigitalOcean) modify(ctx context.Context, record DigitalOceanRecord, domain *config.Domain, ipAddr string) {
+	// 相同不修改
+	if record.Data == ipAddr && record.TTL == do.TTL {
+		util.LogDebug("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		domain.UpdateStatus = config.UpdatedNothing
+		return
+	}
+
+	recordURL := fmt.Sprintf(digitalOceanEndpoint+"/%s/records/%d", domain.DomainName, record.ID)
+	record.Data = ipAddr
+	record.TTL = do.TTL
+	if dryRun(domain, http.MethodPut, recordURL, record) {
+		return
+	}
+
+	var result DigitalOceanRecordResp
+	err := do.request(ctx, http.MethodPut, recordURL, record, &result)
+	if err != nil {
+		util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+
+	util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+	domain.UpdateStatus = config.UpdatedSuccess
+}
+
+// cleanDuplicateRecords 删除多余的相同解析记录
+func (do *DigitalOcean) cleanDuplicateRecords(ctx context.Context, records []DigitalOceanRecord, domain *config.Domain) {
+	for _, record := range records {
+		recordURL := fmt.Sprintf(digitalOceanEndpoint+"/%s/records/%d", domain.DomainName, record.ID)
+		if dryRun(domain, http.MethodDelete, recordURL, nil) {
+			continue
+		}
+
+		err := do.request(ctx, http.MethodDelete, recordURL, nil, nil)
+		if err != nil {
+			util.LogError("删除多余域名解析 %s 失败! 异常信息: %s", domain, err)
+		} else {
+			util.Log("删除多余域名解析 %s 成功!", domain)
+		}
+	}
+}
+
+// DeleteRecords 删除域名名称相同的全部 recordType 记录
+func (do *DigitalOcean) DeleteRecords(ctx context.Context, recordType string, domains []*config.Domain) {
+	forEachDomain(domains, func(domain *config.Domain) {
+		records, err := do.getRecords(ctx, domain, recordType)
+		if err != nil {
+			util.LogError("查询域名信息发生异常! %s", err)
+			return
+		}
+		for _, record := range records {
+			recordURL := fmt.Sprintf(digitalOceanEndpoint+"/%s/records/%d", domain.DomainName, record.ID)
+			if dryRun(domain, http.MethodDelete, recordURL, nil) {
+				continue
+			}
+			err := do.request(ctx, http.MethodDelete, recordURL, nil, nil)
+			if err != nil {
+				util.LogError("删除域名解析 %s 失败! 异常信息: %s", domain, err)
+			} else {
+				util.Log("删除域名解析 %s 成功!", domain)
+			}
+		}
+	})
+}
+
+// Check 获取域名列表, 检查令牌是否正确
+func (do *DigitalOcean) Check(ctx context.Context) error {
+	return do.request(ctx, http.MethodGet, digitalOceanEndpoint+"?per_page=1", nil, nil)
+}
+
+// request 统一请求接口
+func (do *DigitalOcean) request(ctx context.Context, method, url string, body interface{}, result interface{}) error {
+	req, err := util.NewJSONRequest(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+do.DNS.Secret)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := do.client.Do(req)
+	return util.GetHTTPResponse(resp, err, result)
+}
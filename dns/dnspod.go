@@ -1,7 +1,10 @@
 package dns
 
 import (
+	"context"
+	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/jeessy2/ddns-go/v6/config"
 	"github.com/jeessy2/ddns-go/v6/util"
@@ -19,6 +22,7 @@ type Dnspod struct {
 	DNS     config.DNS
 	Domains config.Domains
 	TTL     string
+	client  *http.Client
 }
 
 // DnspodRecord DnspodRecord
@@ -44,12 +48,17 @@ type DnspodStatus struct {
 	}
 }
 
+func init() {
+	RegisterDNS("dnspod", func() DNS { return &Dnspod{} })
+}
+
 // Init 初始化
 func (dnspod *Dnspod) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
 	dnspod.Domains.Ipv4Cache = ipv4cache
 	dnspod.Domains.Ipv6Cache = ipv6cache
 	dnspod.DNS = dnsConf.DNS
 	dnspod.Domains.GetNewIp(dnsConf)
+	dnspod.client = util.CreateHTTPClientTimeout(dnsConf.GetHTTPTimeout())
 	if dnsConf.TTL == "" {
 		// 默认600s
 		dnspod.TTL = "600"
@@ -59,23 +68,23 @@ func (dnspod *Dnspod) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, i
 }
 
 // AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
-func (dnspod *Dnspod) AddUpdateDomainRecords() config.Domains {
-	dnspod.addUpdateDomainRecords("A")
-	dnspod.addUpdateDomainRecords("AAAA")
+func (dnspod *Dnspod) AddUpdateDomainRecords(ctx context.Context) config.Domains {
+	dnspod.addUpdateDomainRecords(ctx, "A")
+	dnspod.addUpdateDomainRecords(ctx, "AAAA")
 	return dnspod.Domains
 }
 
-func (dnspod *Dnspod) addUpdateDomainRecords(recordType string) {
+func (dnspod *Dnspod) addUpdateDomainRecords(ctx context.Context, recordType string) {
 	ipAddr, domains := dnspod.Domains.GetNewIpResult(recordType)
 
 	if ipAddr == "" {
 		return
 	}
 
-	for _, domain := range domains {
-		result, err := dnspod.getRecordList(domain, recordType)
+	forEachDomain(domains, func(domain *config.Domain) {
+		result, err := dnspod.getRecordList(ctx, domain, recordType)
 		if err != nil {
-			util.Log("查询域名信息发生异常! %s", err)
+			util.LogError("查询域名信息发生异常! %s", err)
 			domain.UpdateStatus = config.UpdatedFailed
 			return
 		}
@@ -92,16 +101,19 @@ func (dnspod *Dnspod) addUpdateDomainRecords(recordType string) {
 				}
 			}
 			// 更新
-			dnspod.modify(recordSelected, domain, recordType, ipAddr)
+			dnspod.modify(ctx, recordSelected, domain, recordType, ipAddr)
 		} else {
 			// 新增
-			dnspod.create(domain, recordType, ipAddr)
+			dnspod.create(ctx, domain, recordType, ipAddr)
 		}
-	}
+	})
 }
 
 // 创建
-func (dnspod *Dnspod) create(domain *config.Domain, recordType string, ipAddr string) {
+func (dnspod *Dnspod) create(ctx context.Context, domain *config.Domain, recordType string, ipAddr string) {
+	if skipCreate(domain) {
+		return
+	}
 	params := domain.GetCustomParams()
 	params.Set("login_token", dnspod.DNS.ID+","+dnspod.DNS.Secret)
 	params.Set("domain", domain.DomainName)
@@ -115,10 +127,14 @@ func (dnspod *Dnspod) create(domain *config.Domain, recordType string, ipAddr st
 		params.Set("record_line", "默认")
 	}
 
-	status, err := dnspod.request(recordCreateAPI, params)
+	if dryRun(domain, "POST", recordCreateAPI, params, dnspod.DNS.Secret) {
+		return
+	}
+
+	status, err := dnspod.request(ctx, recordCreateAPI, params)
 
 	if err != nil {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogError("新增域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
@@ -127,17 +143,18 @@ func (dnspod *Dnspod) create(domain *config.Domain, recordType string, ipAddr st
 		util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, status.Status.Message)
+		util.LogError("新增域名解析 %s 失败! 异常信息: %s", domain, status.Status.Message)
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
 
 // 修改
-func (dnspod *Dnspod) modify(record DnspodRecord, domain *config.Domain, recordType string, ipAddr string) {
+func (dnspod *Dnspod) modify(ctx context.Context, record DnspodRecord, domain *config.Domain, recordType string, ipAddr string) {
 
 	// 相同不修改
 	if record.Value == ipAddr {
-		util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		util.LogDebug("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		domain.UpdateStatus = config.UpdatedNothing
 		return
 	}
 
@@ -155,10 +172,14 @@ func (dnspod *Dnspod) modify(record DnspodRecord, domain *config.Domain, recordT
 		params.Set("record_line", "默认")
 	}
 
-	status, err := dnspod.request(recordModifyURL, params)
+	if dryRun(domain, "POST", recordModifyURL, params, dnspod.DNS.Secret) {
+		return
+	}
+
+	status, err := dnspod.request(ctx, recordModifyURL, params)
 
 	if err != nil {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
@@ -167,26 +188,31 @@ func (dnspod *Dnspod) modify(record DnspodRecord, domain *config.Domain, recordT
 		util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, status.Status.Message)
+		util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, status.Status.Message)
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
 
 // request sends a POST request to the given API with the given values.
-func (dnspod *Dnspod) request(apiAddr string, values url.Values) (status DnspodStatus, err error) {
-	client := util.CreateHTTPClient()
-	resp, err := client.PostForm(
-		apiAddr,
-		values,
-	)
-
+func (dnspod *Dnspod) request(ctx context.Context, apiAddr string, values url.Values) (status DnspodStatus, err error) {
+	resp, err := dnspod.postForm(ctx, apiAddr, values)
 	err = util.GetHTTPResponse(resp, err, &status)
 
 	return
 }
 
+// postForm 以表单方式发送POST请求
+func (dnspod *Dnspod) postForm(ctx context.Context, apiAddr string, values url.Values) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiAddr, strings.NewReader(values.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return dnspod.client.Do(req)
+}
+
 // 获得域名记录列表
-func (dnspod *Dnspod) getRecordList(domain *config.Domain, typ string) (result DnspodRecordListResp, err error) {
+func (dnspod *Dnspod) getRecordList(ctx context.Context, domain *config.Domain, typ string) (result DnspodRecordListResp, err error) {
 
 	params := domain.GetCustomParams()
 	params.Set("login_token", dnspod.DNS.ID+","+dnspod.DNS.Secret)
@@ -195,11 +221,7 @@ func (dnspod *Dnspod) getRecordList(domain *config.Domain, typ string) (result D
 	params.Set("sub_domain", domain.GetSubDomain())
 	params.Set("format", "json")
 
-	client := util.CreateHTTPClient()
-	resp, err := client.PostForm(
-		recordListAPI,
-		params,
-	)
+	resp, err := dnspod.postForm(ctx, recordListAPI, params)
 
 	err = util.GetHTTPResponse(resp, err, &result)
 
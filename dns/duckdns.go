@@ -0,0 +1,126 @@
+package dns
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+const (
+	duckDNSEndpoint = "https://www.duckdns.org/update"
+)
+
+// DuckDNS DuckDNS实现, 没有记录接口, 通过更新地址直接修改
+// https://www.duckdns.org/spec.jsp
+type DuckDNS struct {
+	DNS      config.DNS
+	Domains  config.Domains
+	lastIpv4 string
+	lastIpv6 string
+	client   *http.Client
+}
+
+func init() {
+	RegisterDNS("duckdns", func() DNS { return &DuckDNS{} })
+}
+
+// Init 初始化
+func (duck *DuckDNS) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
+	duck.Domains.Ipv4Cache = ipv4cache
+	duck.Domains.Ipv6Cache = ipv6cache
+	duck.lastIpv4 = ipv4cache.Addr
+	duck.lastIpv6 = ipv6cache.Addr
+	duck.DNS = dnsConf.DNS
+	duck.Domains.GetNewIp(dnsConf)
+	duck.client = util.CreateHTTPClientTimeout(dnsConf.GetHTTPTimeout())
+}
+
+// AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
+func (duck *DuckDNS) AddUpdateDomainRecords(ctx context.Context) config.Domains {
+	duck.addUpdateDomainRecords(ctx, "A")
+	duck.addUpdateDomainRecords(ctx, "AAAA")
+	return duck.Domains
+}
+
+func (duck *DuckDNS) addUpdateDomainRecords(ctx context.Context, recordType string) {
+	ipAddr, domains := duck.Domains.GetNewIpResult(recordType)
+
+	if ipAddr == "" {
+		return
+	}
+
+	// 防止多次发送Webhook通知
+	if recordType == "A" {
+		if duck.lastIpv4 == ipAddr {
+			util.LogDebug("你的IPv4未变化, 未触发 %s 请求", "DuckDNS")
+			return
+		}
+	} else {
+		if duck.lastIpv6 == ipAddr {
+			util.LogDebug("你的IPv6未变化, 未触发 %s 请求", "DuckDNS")
+			return
+		}
+	}
+
+	// 一次请求更新全部域名, 只需要 duckdns.org 前的部分
+	var labels []string
+	for _, domain := range domains {
+		labels = append(labels, strings.TrimSuffix(domain.String(), ".duckdns.org"))
+	}
+
+	params := url.Values{}
+	params.Set("domains", strings.Join(labels, ","))
+	params.Set("token", duck.DNS.Secret)
+	if recordType == "A" {
+		params.Set("ip", ipAddr)
+	} else {
+		params.Set("ipv6", ipAddr)
+	}
+
+	if dryRunEnabled {
+		for _, domain := range domains {
+			dryRun(domain, http.MethodGet, duckDNSEndpoint+"?"+params.Encode(), nil, duck.DNS.Secret)
+		}
+		return
+	}
+
+	status, err := duck.request(ctx, params)
+	for _, domain := range domains {
+		if err != nil {
+			util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+			domain.UpdateStatus = config.UpdatedFailed
+		} else if status == "OK" {
+			util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+			domain.UpdateStatus = config.UpdatedSuccess
+		} else {
+			util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, status)
+			domain.UpdateStatus = config.UpdatedFailed
+		}
+	}
+}
+
+// request 统一请求接口, 返回纯文本 OK 或 KO
+func (duck *DuckDNS) request(ctx context.Context, params url.Values) (status string, err error) {
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		duckDNSEndpoint,
+		http.NoBody,
+	)
+	if err != nil {
+		return
+	}
+	req.URL.RawQuery = params.Encode()
+
+	resp, err := duck.client.Do(req)
+	body, err := util.GetHTTPResponseOrg(resp, err)
+	if err != nil {
+		return
+	}
+
+	return strings.TrimSpace(string(body)), nil
+}
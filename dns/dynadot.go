@@ -2,6 +2,7 @@ package dns
 
 import (
 	"bytes"
+	"context"
 	"github.com/jeessy2/ddns-go/v6/config"
 	"github.com/jeessy2/ddns-go/v6/util"
 	"net/http"
@@ -22,6 +23,7 @@ type Dynadot struct {
 	TTL      string
 	LastIpv4 string
 	LastIpv6 string
+	client   *http.Client
 }
 
 // DynadotRecord record
@@ -40,6 +42,10 @@ type DynadotResp struct {
 	Content   []string `json:"content"`
 }
 
+func init() {
+	RegisterDNS("dynadot", func() DNS { return &Dynadot{} })
+}
+
 // Init 初始化
 func (dynadot *Dynadot) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
 	dynadot.Domains.Ipv4Cache = ipv4cache
@@ -48,6 +54,7 @@ func (dynadot *Dynadot) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache,
 	dynadot.LastIpv6 = ipv6cache.Addr
 	dynadot.DNS = dnsConf.DNS
 	dynadot.Domains.GetNewIp(dnsConf)
+	dynadot.client = util.CreateHTTPClientTimeout(dnsConf.GetHTTPTimeout())
 	if dnsConf.TTL == "" {
 		// 默认600s
 		dynadot.TTL = "600"
@@ -57,14 +64,14 @@ func (dynadot *Dynadot) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache,
 }
 
 // AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
-func (dynadot *Dynadot) AddUpdateDomainRecords() config.Domains {
-	dynadot.addOrUpdateDomainRecords("A")
-	dynadot.addOrUpdateDomainRecords("AAAA")
+func (dynadot *Dynadot) AddUpdateDomainRecords(ctx context.Context) config.Domains {
+	dynadot.addOrUpdateDomainRecords(ctx, "A")
+	dynadot.addOrUpdateDomainRecords(ctx, "AAAA")
 	return dynadot.Domains
 }
 
 // addOrUpdateDomainRecords 添加或更新记录
-func (dynadot *Dynadot) addOrUpdateDomainRecords(recordType string) {
+func (dynadot *Dynadot) addOrUpdateDomainRecords(ctx context.Context, recordType string) {
 	ipAddr, domains := dynadot.Domains.GetNewIpResult(recordType)
 
 	if len(ipAddr) == 0 {
@@ -74,12 +81,12 @@ func (dynadot *Dynadot) addOrUpdateDomainRecords(recordType string) {
 	// 防止多次发送Webhook通知
 	if recordType == "A" {
 		if dynadot.LastIpv4 == ipAddr {
-			util.Log("你的IPv4未变化, 未触发 %s 请求", "dynadot")
+			util.LogDebug("你的IPv4未变化, 未触发 %s 请求", "dynadot")
 			return
 		}
 	} else {
 		if dynadot.LastIpv6 == ipAddr {
-			util.Log("你的IPv6未变化, 未触发 %s 请求", "dynadot")
+			util.LogDebug("你的IPv6未变化, 未触发 %s 请求", "dynadot")
 			return
 		}
 	}
@@ -87,12 +94,12 @@ func (dynadot *Dynadot) addOrUpdateDomainRecords(recordType string) {
 	records := mergeDomains(domains)
 	// dynadot 仅支持一个域名对应一个dynamic password
 	if len(records) != 1 {
-		util.Log("dynadot仅支持单域名配置，多个域名请添加更多配置")
+		util.LogWarn("dynadot仅支持单域名配置，多个域名请添加更多配置")
 		return
 	}
 	for _, record := range records {
 		// 创建或更新
-		dynadot.createOrModify(record, recordType, ipAddr)
+		dynadot.createOrModify(ctx, record, recordType, ipAddr)
 	}
 }
 
@@ -131,7 +138,7 @@ func mergeDomains(domains []*config.Domain) (records []*DynadotRecord) {
 }
 
 // 创建或变更记录
-func (dynadot *Dynadot) createOrModify(record *DynadotRecord, recordType string, ipAddr string) {
+func (dynadot *Dynadot) createOrModify(ctx context.Context, record *DynadotRecord, recordType string, ipAddr string) {
 	params := record.CustomParams
 	params.Set("domain", record.DomainName)
 	params.Set("subDomain", strings.Join(record.SubDomainNames, ","))
@@ -141,14 +148,21 @@ func (dynadot *Dynadot) createOrModify(record *DynadotRecord, recordType string,
 	params.Set("ttl", dynadot.TTL)
 	params.Set("containRoot", strconv.FormatBool(record.ContainRoot))
 
+	if dryRunEnabled {
+		for _, domain := range record.Domains {
+			dryRun(domain, "GET", dynadotEndpoint, params, dynadot.DNS.Secret)
+		}
+		return
+	}
+
 	var result DynadotResp
-	err := dynadot.request(params, &result)
+	err := dynadot.request(ctx, params, &result)
 
 	domains := record.Domains
 	for _, domain := range domains {
 
 		if err != nil {
-			util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+			util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, err)
 			domain.UpdateStatus = config.UpdatedFailed
 			return
 		}
@@ -157,7 +171,7 @@ func (dynadot *Dynadot) createOrModify(record *DynadotRecord, recordType string,
 			util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
 			domain.UpdateStatus = config.UpdatedSuccess
 		} else {
-			util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, strings.Join(result.Content, ","))
+			util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, strings.Join(result.Content, ","))
 			domain.UpdateStatus = config.UpdatedFailed
 		}
 	}
@@ -165,9 +179,10 @@ func (dynadot *Dynadot) createOrModify(record *DynadotRecord, recordType string,
 }
 
 // request 统一请求接口
-func (dynadot *Dynadot) request(params url.Values, result interface{}) (err error) {
+func (dynadot *Dynadot) request(ctx context.Context, params url.Values, result interface{}) (err error) {
 
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"GET",
 		dynadotEndpoint,
 		bytes.NewBuffer(nil),
@@ -178,8 +193,7 @@ func (dynadot *Dynadot) request(params url.Values, result interface{}) (err erro
 		return
 	}
 
-	client := util.CreateHTTPClient()
-	resp, err := client.Do(req)
+	resp, err := dynadot.client.Do(req)
 	err = util.GetHTTPResponse(resp, err, result)
 
 	return
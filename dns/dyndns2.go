@@ -0,0 +1,157 @@
+package dns
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+const (
+	// 未填写更新地址时默认使用 No-IP
+	dynDNS2DefaultEndpoint = "https://dynupdate.no-ip.com/nic/update"
+)
+
+// DynDNS2 通用的DynDNS2协议实现, 适用于 No-IP、FreeDNS 等
+// https://help.dyn.com/remote-access-api/perform-update/
+type DynDNS2 struct {
+	DNS      config.DNS
+	Domains  config.Domains
+	endpoint string
+	lastIpv4 string
+	lastIpv6 string
+	client   *http.Client
+}
+
+func init() {
+	RegisterDNS("dyndns2", func() DNS { return &DynDNS2{} })
+}
+
+// Init 初始化
+func (dyn *DynDNS2) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
+	dyn.Domains.Ipv4Cache = ipv4cache
+	dyn.Domains.Ipv6Cache = ipv6cache
+	dyn.lastIpv4 = ipv4cache.Addr
+	dyn.lastIpv6 = ipv6cache.Addr
+	dyn.DNS = dnsConf.DNS
+	dyn.Domains.GetNewIp(dnsConf)
+	dyn.client = util.CreateHTTPClientTimeout(dnsConf.GetHTTPTimeout())
+	dyn.endpoint = dnsConf.DNS.Endpoint
+	if dyn.endpoint == "" {
+		dyn.endpoint = dynDNS2DefaultEndpoint
+	}
+}
+
+// AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
+func (dyn *DynDNS2) AddUpdateDomainRecords(ctx context.Context) config.Domains {
+	dyn.addUpdateDomainRecords(ctx, "A")
+	dyn.addUpdateDomainRecords(ctx, "AAAA")
+	return dyn.Domains
+}
+
+func (dyn *DynDNS2) addUpdateDomainRecords(ctx context.Context, recordType string) {
+	ipAddr, domains := dyn.Domains.GetNewIpResult(recordType)
+
+	if ipAddr == "" {
+		return
+	}
+
+	// 防止多次发送Webhook通知
+	if recordType == "A" {
+		if dyn.lastIpv4 == ipAddr {
+			util.LogDebug("你的IPv4未变化, 未触发 %s 请求", "DynDNS2")
+			return
+		}
+	} else {
+		if dyn.lastIpv6 == ipAddr {
+			util.LogDebug("你的IPv6未变化, 未触发 %s 请求", "DynDNS2")
+			return
+		}
+	}
+
+	for _, domain := range domains {
+		dyn.modify(ctx, domain, ipAddr)
+	}
+}
+
+// 修改
+func (dyn *DynDNS2) modify(ctx context.Context, domain *config.Domain, ipAddr string) {
+	params := domain.GetCustomParams()
+	params.Set("hostname", domain.String())
+	params.Set("myip", ipAddr)
+
+	req, err := dyn.newRequest(ctx, params)
+	if err != nil {
+		util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+	if dryRun(domain, http.MethodGet, req.URL.Redacted(), nil) {
+		return
+	}
+
+	status, err := dyn.request(ctx, req)
+	if err != nil {
+		util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+
+	// 返回内容如 good 1.2.3.4, 只取第一个返回码
+	switch code := strings.Fields(status + " ")[0]; code {
+	case "nochg":
+		util.LogDebug("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		domain.UpdateStatus = config.UpdatedNothing
+	case "good":
+		util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+		domain.UpdateStatus = config.UpdatedSuccess
+	case "badauth":
+		util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, util.LogStr("DynDNS2 认证失败"))
+		domain.UpdateStatus = config.UpdatedFailed
+	case "!yours":
+		util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, util.LogStr("域名不属于该帐号"))
+		domain.UpdateStatus = config.UpdatedFailed
+	default:
+		util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, status)
+		domain.UpdateStatus = config.UpdatedFailed
+	}
+}
+
+// newRequest 创建更新请求, 保留更新地址中已有的参数
+// 未填写用户名时使用更新地址中的 user:pass@host
+func (dyn *DynDNS2) newRequest(ctx context.Context, params url.Values) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		dyn.endpoint,
+		http.NoBody,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	query := req.URL.Query()
+	for k, v := range params {
+		query[k] = v
+	}
+	req.URL.RawQuery = query.Encode()
+	if dyn.DNS.ID != "" {
+		req.SetBasicAuth(dyn.DNS.ID, dyn.DNS.Secret)
+	}
+	req.Header.Set("User-Agent", "ddns-go")
+	return req, nil
+}
+
+// request 统一请求接口, 返回纯文本的返回码
+func (dyn *DynDNS2) request(ctx context.Context, req *http.Request) (status string, err error) {
+	resp, err := dyn.client.Do(req)
+	body, err := util.GetHTTPResponseOrg(resp, err)
+	if err != nil {
+		return
+	}
+
+	return strings.TrimSpace(string(body)), nil
+}
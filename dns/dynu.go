@@ -0,0 +1,180 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+const (
+	dynuEndpoint = "https://api.dynu.com/v2/dns"
+)
+
+// Dynu Dynu实现
+// https://www.dynu.com/en-US/Support/API
+type Dynu struct {
+	DNS     config.DNS
+	Domains config.Domains
+	TTL     int
+	client  *http.Client
+}
+
+// DynuRootResp getroot返回结果, node为相对根域名的主机名
+type DynuRootResp struct {
+	ID         int    `json:"id"`
+	DomainName string `json:"domainName"`
+	Node       string `json:"node"`
+}
+
+// DynuRecord 记录实体
+type DynuRecord struct {
+	ID          int    `json:"id,omitempty"`
+	NodeName    string `json:"nodeName"`
+	RecordType  string `json:"recordType"`
+	TTL         int    `json:"ttl"`
+	State       bool   `json:"state"`
+	Ipv4Address string `json:"ipv4Address,omitempty"`
+	Ipv6Address string `json:"ipv6Address,omitempty"`
+}
+
+// DynuRecordsResp records返回结果
+type DynuRecordsResp struct {
+	DNSRecords []DynuRecord `json:"dnsRecords"`
+}
+
+func init() {
+	RegisterDNS("dynu", func() DNS { return &Dynu{} })
+}
+
+// Init 初始化
+func (dynu *Dynu) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
+	dynu.Domains.Ipv4Cache = ipv4cache
+	dynu.Domains.Ipv6Cache = ipv6cache
+	dynu.DNS = dnsConf.DNS
+	dynu.Domains.GetNewIp(dnsConf)
+	dynu.client = util.CreateHTTPClientTimeout(dnsConf.GetHTTPTimeout())
+	if dnsConf.TTL == "" {
+		// 默认300s
+		dynu.TTL = 300
+	} else {
+		ttl, err := strconv.Atoi(dnsConf.TTL)
+		if err != nil {
+			dynu.TTL = 300
+		} else {
+			dynu.TTL = ttl
+		}
+	}
+}
+
+// AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
+func (dynu *Dynu) AddUpdateDomainRecords(ctx context.Context) config.Domains {
+	dynu.addUpdateDomainRecords(ctx, "A")
+	dynu.addUpdateDomainRecords(ctx, "AAAA")
+	return dynu.Domains
+}
+
+func (dynu *Dynu) addUpdateDomainRecords(ctx context.Context, recordType string) {
+	ipAddr, domains := dynu.Domains.GetNewIpResult(recordType)
+
+	if ipAddr == "" {
+		return
+	}
+
+	forEachDomain(domains, func(domain *config.Domain) {
+		// Dynu 的根域名可能是 xxx.dynu.net 这样的子域名, 通过getroot获取
+		var root DynuRootResp
+		err := dynu.request(ctx, http.MethodGet, dynuEndpoint+"/getroot/"+domain.String(), nil, &root)
+		if err != nil {
+			util.LogError("查询域名信息发生异常! %s", err)
+			domain.UpdateStatus = config.UpdatedFailed
+			return
+		}
+		if root.ID == 0 {
+			util.LogError("在DNS服务商中未找到根域名: %s", domain.DomainName)
+			domain.UpdateStatus = config.UpdatedFailed
+			return
+		}
+
+		var records DynuRecordsResp
+		err = dynu.request(ctx, http.MethodGet, fmt.Sprintf(dynuEndpoint+"/%d/record", root.ID), nil, &records)
+		if err != nil {
+			util.LogError("查询域名信息发生异常! %s", err)
+			domain.UpdateStatus = config.UpdatedFailed
+			return
+		}
+
+		var existing *DynuRecord
+		for i := range records.DNSRecords {
+			if records.DNSRecords[i].RecordType == recordType && records.DNSRecords[i].NodeName == root.Node {
+				existing = &records.DNSRecords[i]
+				break
+			}
+		}
+
+		dynu.createOrModify(ctx, root, existing, domain, recordType, ipAddr)
+	})
+}
+
+// createOrModify 不存在时新增, 存在时更新
+func (dynu *Dynu) createOrModify(ctx context.Context, root DynuRootResp, existing *DynuRecord, domain *config.Domain, recordType string, ipAddr string) {
+	if existing == nil && skipCreate(domain) {
+		return
+	}
+	operation := "新增"
+	recordURL := fmt.Sprintf(dynuEndpoint+"/%d/record", root.ID)
+	record := DynuRecord{NodeName: root.Node, RecordType: recordType}
+	if existing != nil {
+		// 相同不修改
+		current := existing.Ipv4Address
+		if recordType == "AAAA" {
+			current = existing.Ipv6Address
+		}
+		if current == ipAddr {
+			util.LogDebug("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+			domain.UpdateStatus = config.UpdatedNothing
+			return
+		}
+		operation = "更新"
+		recordURL = fmt.Sprintf(dynuEndpoint+"/%d/record/%d", root.ID, existing.ID)
+		record = *existing
+	}
+
+	record.TTL = dynu.TTL
+	record.State = true
+	if recordType == "A" {
+		record.Ipv4Address = ipAddr
+	} else {
+		record.Ipv6Address = ipAddr
+	}
+	if dryRun(domain, http.MethodPost, recordURL, record) {
+		return
+	}
+
+	var result DynuRecord
+	err := dynu.request(ctx, http.MethodPost, recordURL, record, &result)
+	if err != nil {
+		util.LogError(operation+"域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+
+	util.Log(operation+"域名解析 %s 成功! IP: %s", domain, ipAddr)
+	domain.UpdateStatus = config.UpdatedSuccess
+}
+
+// request 统一请求接口
+func (dynu *Dynu) request(ctx context.Context, method, url string, body interface{}, result interface{}) error {
+	req, err := util.NewJSONRequest(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("API-Key", dynu.DNS.Secret)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := dynu.client.Do(req)
+	return util.GetHTTPResponse(resp, err, result)
+}
@@ -0,0 +1,155 @@
+package dns
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+const (
+	gandiEndpoint = "https://api.gandi.net/v5/livedns/domains"
+	// Gandi 允许的最小TTL
+	gandiMinTTL = 300
+)
+
+// Gandi Gandi LiveDNS实现
+// DNS.ID 为API Key时使用 Apikey 认证, 为空时使用 PAT 认证
+// https://api.gandi.net/docs/livedns/
+type Gandi struct {
+	DNS     config.DNS
+	Domains config.Domains
+	TTL     int
+	client  *http.Client
+}
+
+// GandiRRset 记录集
+type GandiRRset struct {
+	RRsetValues []string `json:"rrset_values"`
+	RRsetTTL    int      `json:"rrset_ttl"`
+}
+
+// GandiResponse 修改返回结果, 失败时包含异常信息
+type GandiResponse struct {
+	Message string `json:"message"`
+}
+
+func init() {
+	RegisterDNS("gandi", func() DNS { return &Gandi{} })
+}
+
+// Init 初始化
+func (gandi *Gandi) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
+	gandi.Domains.Ipv4Cache = ipv4cache
+	gandi.Domains.Ipv6Cache = ipv6cache
+	gandi.DNS = dnsConf.DNS
+	gandi.Domains.GetNewIp(dnsConf)
+	gandi.client = util.CreateHTTPClientTimeout(dnsConf.GetHTTPTimeout())
+	if dnsConf.TTL == "" {
+		// 默认300s
+		gandi.TTL = gandiMinTTL
+	} else {
+		ttl, err := strconv.Atoi(dnsConf.TTL)
+		if err != nil {
+			gandi.TTL = 300
+		} else {
+			gandi.TTL = checkMinTTL("Gandi", ttl, gandiMinTTL)
+		}
+	}
+}
+
+// AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
+func (gandi *Gandi) AddUpdateDomainRecords(ctx context.Context) config.Domains {
+	gandi.addUpdateDomainRecords(ctx, "A")
+	gandi.addUpdateDomainRecords(ctx, "AAAA")
+	return gandi.Domains
+}
+
+func (gandi *Gandi) addUpdateDomainRecords(ctx context.Context, recordType string) {
+	ipAddr, domains := gandi.Domains.GetNewIpResult(recordType)
+
+	if ipAddr == "" {
+		return
+	}
+
+	forEachDomain(domains, func(domain *config.Domain) {
+		recordURL := fmt.Sprintf(gandiEndpoint+"/%s/records/%s/%s", domain.DomainName, domain.GetSubDomain(), recordType)
+
+		// 查询失败时记录可能不存在, 继续PUT
+		var existing GandiRRset
+		err := gandi.request(ctx, http.MethodGet, recordURL, nil, &existing)
+		if err == nil && len(existing.RRsetValues) == 1 && existing.RRsetValues[0] == ipAddr && existing.RRsetTTL == gandi.TTL {
+			util.LogDebug("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+			domain.UpdateStatus = config.UpdatedNothing
+			return
+		}
+		// 只更新不新增时需确定记录已存在, 不存在时返回404
+		if err != nil && domain.UpdateOnly {
+			var statusErr *util.HTTPStatusError
+			if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+				skipCreate(domain)
+			} else {
+				util.LogError("查询域名信息发生异常! %s", err)
+				domain.UpdateStatus = config.UpdatedFailed
+			}
+			return
+		}
+
+		// PUT 替换名称和类型相同的全部记录, 不存在时新增
+		rrset := &GandiRRset{
+			RRsetValues: []string{ipAddr},
+			RRsetTTL:    gandi.TTL,
+		}
+		if dryRun(domain, http.MethodPut, recordURL, rrset) {
+			return
+		}
+
+		var result GandiResponse
+		err = gandi.request(ctx, http.MethodPut, recordURL, rrset, &result)
+		if err != nil {
+			util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+			domain.UpdateStatus = config.UpdatedFailed
+		} else {
+			util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+			domain.UpdateStatus = config.UpdatedSuccess
+		}
+	})
+}
+
+// request 统一请求接口
+func (gandi *Gandi) request(ctx context.Context, method, url string, body interface{}, result interface{}) error {
+	req, err := util.NewJSONRequest(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+
+	if gandi.DNS.ID != "" {
+		// 填写了API Key, 使用 Apikey 认证
+		req.Header.Set("Authorization", "Apikey "+gandi.DNS.ID)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+gandi.DNS.Secret)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := gandi.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	err = util.ParseJSONResponse(resp, result)
+	var statusErr *util.HTTPStatusError
+	if errors.As(err, &statusErr) {
+		// 优先使用返回的错误信息
+		var errResp GandiResponse
+		if json.Unmarshal(statusErr.Body, &errResp) == nil && errResp.Message != "" {
+			return &util.HTTPStatusError{StatusCode: statusErr.StatusCode, Body: []byte(errResp.Message)}
+		}
+	}
+	return err
+}
@@ -2,8 +2,10 @@ package dns
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 
@@ -11,6 +13,9 @@ import (
 	"github.com/jeessy2/ddns-go/v6/util"
 )
 
+// GoDaddy 允许的最小TTL
+const godaddyMinTTL = 600
+
 type godaddyRecord struct {
 	Data string `json:"data"`
 	Name string `json:"name"`
@@ -30,6 +35,10 @@ type GoDaddyDNS struct {
 	lastIpv6 string
 }
 
+func init() {
+	RegisterDNS("godaddy", func() DNS { return &GoDaddyDNS{} })
+}
+
 func (g *GoDaddyDNS) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
 	g.domains.Ipv4Cache = ipv4cache
 	g.domains.Ipv6Cache = ipv6cache
@@ -38,19 +47,19 @@ func (g *GoDaddyDNS) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ip
 
 	g.dns = dnsConf.DNS
 	g.domains.GetNewIp(dnsConf)
-	g.ttl = 600
+	g.ttl = godaddyMinTTL
 	if val, err := strconv.Atoi(dnsConf.TTL); err == nil {
-		g.ttl = val
+		g.ttl = checkMinTTL("GoDaddy", val, godaddyMinTTL)
 	}
 	g.header = map[string][]string{
 		"Authorization": {fmt.Sprintf("sso-key %s:%s", g.dns.ID, g.dns.Secret)},
 		"Content-Type":  {"application/json"},
 	}
 
-	g.client = util.CreateHTTPClient()
+	g.client = util.CreateHTTPClientTimeout(dnsConf.GetHTTPTimeout())
 }
 
-func (g *GoDaddyDNS) updateDomainRecord(recordType string, ipAddr string, domains []*config.Domain) {
+func (g *GoDaddyDNS) updateDomainRecord(ctx context.Context, recordType string, ipAddr string, domains []*config.Domain) {
 	if ipAddr == "" {
 		return
 	}
@@ -58,46 +67,73 @@ func (g *GoDaddyDNS) updateDomainRecord(recordType string, ipAddr string, domain
 	// 防止多次发送Webhook通知
 	if recordType == "A" {
 		if g.lastIpv4 == ipAddr {
-			util.Log("你的IPv4未变化, 未触发 %s 请求", "godaddy")
+			util.LogDebug("你的IPv4未变化, 未触发 %s 请求", "godaddy")
 			return
 		}
 	} else {
 		if g.lastIpv6 == ipAddr {
-			util.Log("你的IPv6未变化, 未触发 %s 请求", "godaddy")
+			util.LogDebug("你的IPv6未变化, 未触发 %s 请求", "godaddy")
 			return
 		}
 	}
 
 	for _, domain := range domains {
-		err := g.sendReq(http.MethodPut, recordType, domain, &godaddyRecords{godaddyRecord{
+		// 查询现有记录, 相同不修改
+		var existing godaddyRecords
+		if err := g.sendReq(ctx, http.MethodGet, recordType, domain, nil, &existing); err != nil {
+			util.LogError("查询域名信息发生异常! %s", err)
+			domain.UpdateStatus = config.UpdatedFailed
+			continue
+		}
+		if len(existing) > 0 && existing[0].Data == ipAddr && existing[0].TTL == g.ttl {
+			util.LogDebug("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+			domain.UpdateStatus = config.UpdatedNothing
+			continue
+		}
+		if len(existing) == 0 && skipCreate(domain) {
+			continue
+		}
+
+		records := &godaddyRecords{godaddyRecord{
 			Data: ipAddr,
 			Name: domain.GetSubDomain(),
 			TTL:  g.ttl,
 			Type: recordType,
-		}})
+		}}
+		if dryRun(domain, http.MethodPut, fmt.Sprintf("https://api.godaddy.com/v1/domains/%s/records/%s/%s",
+			domain.DomainName, recordType, domain.GetSubDomain()), records) {
+			continue
+		}
+		// GoDaddy 按类型和名称整体替换记录, 不存在时会新增
+		err := g.sendReq(ctx, http.MethodPut, recordType, domain, records, nil)
+		operation := "更新"
+		if len(existing) == 0 {
+			operation = "新增"
+		}
 		if err == nil {
-			util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+			util.Log(operation+"域名解析 %s 成功! IP: %s", domain, ipAddr)
 			domain.UpdateStatus = config.UpdatedSuccess
 		} else {
-			util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+			util.LogError(operation+"域名解析 %s 失败! 异常信息: %s", domain, err)
 			domain.UpdateStatus = config.UpdatedFailed
 		}
 	}
 }
 
-func (g *GoDaddyDNS) AddUpdateDomainRecords() config.Domains {
+func (g *GoDaddyDNS) AddUpdateDomainRecords(ctx context.Context) config.Domains {
 	if ipv4Addr, ipv4Domains := g.domains.GetNewIpResult("A"); ipv4Addr != "" {
-		g.updateDomainRecord("A", ipv4Addr, ipv4Domains)
+		g.updateDomainRecord(ctx, "A", ipv4Addr, ipv4Domains)
 	}
 	if ipv6Addr, ipv6Domains := g.domains.GetNewIpResult("AAAA"); ipv6Addr != "" {
-		g.updateDomainRecord("AAAA", ipv6Addr, ipv6Domains)
+		g.updateDomainRecord(ctx, "AAAA", ipv6Addr, ipv6Domains)
 	}
 	return g.domains
 }
 
-func (g *GoDaddyDNS) sendReq(method string, rType string, domain *config.Domain, data *godaddyRecords) error {
+// sendReq 统一请求接口, result不为nil时解析返回的记录
+func (g *GoDaddyDNS) sendReq(ctx context.Context, method string, rType string, domain *config.Domain, data *godaddyRecords, result interface{}) error {
 
-	var body *bytes.Buffer
+	var body io.Reader = http.NoBody
 	if data != nil {
 		if buffer, err := json.Marshal(data); err != nil {
 			return err
@@ -108,12 +144,15 @@ func (g *GoDaddyDNS) sendReq(method string, rType string, domain *config.Domain,
 	path := fmt.Sprintf("https://api.godaddy.com/v1/domains/%s/records/%s/%s",
 		domain.DomainName, rType, domain.GetSubDomain())
 
-	req, err := http.NewRequest(method, path, body)
+	req, err := http.NewRequestWithContext(ctx, method, path, body)
 	if err != nil {
 		return err
 	}
 	req.Header = g.header
 	resp, err := g.client.Do(req)
-	_, err = util.GetHTTPResponseOrg(resp, err)
-	return err
+	if result == nil {
+		_, err = util.GetHTTPResponseOrg(resp, err)
+		return err
+	}
+	return util.GetHTTPResponse(resp, err, result)
 }
@@ -0,0 +1,215 @@
+package dns
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+const (
+	googleCloudDNSEndpoint = "https://dns.googleapis.com/dns/v1"
+	googleCloudDNSScope    = "https://www.googleapis.com/auth/ndev.clouddns.readwrite"
+)
+
+// GoogleCloudDNS Google Cloud DNS实现, 使用服务账号密钥
+// https://cloud.google.com/dns/docs/reference/rest/v1
+type GoogleCloudDNS struct {
+	DNS     config.DNS
+	Domains config.Domains
+	TTL     int
+	client  *http.Client
+}
+
+// GoogleCloudDNSRecordSet 记录集
+type GoogleCloudDNSRecordSet struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	TTL     int      `json:"ttl"`
+	Rrdatas []string `json:"rrdatas"`
+}
+
+// GoogleCloudDNSRecordSetsResp rrsets返回结果
+type GoogleCloudDNSRecordSetsResp struct {
+	Rrsets []GoogleCloudDNSRecordSet `json:"rrsets"`
+}
+
+// GoogleCloudDNSManagedZonesResp managedZones返回结果
+type GoogleCloudDNSManagedZonesResp struct {
+	ManagedZones []struct {
+		Name    string `json:"name"`
+		DNSName string `json:"dnsName"`
+	} `json:"managedZones"`
+}
+
+// GoogleCloudDNSChange changes请求, 删除和新增在同一个变更中原子执行
+type GoogleCloudDNSChange struct {
+	Additions []GoogleCloudDNSRecordSet `json:"additions,omitempty"`
+	Deletions []GoogleCloudDNSRecordSet `json:"deletions,omitempty"`
+}
+
+func init() {
+	RegisterDNS("googleclouddns", func() DNS { return &GoogleCloudDNS{} })
+}
+
+// Init 初始化
+func (gcd *GoogleCloudDNS) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
+	gcd.Domains.Ipv4Cache = ipv4cache
+	gcd.Domains.Ipv6Cache = ipv6cache
+	gcd.DNS = dnsConf.DNS
+	gcd.Domains.GetNewIp(dnsConf)
+	gcd.client = util.CreateHTTPClientTimeout(dnsConf.GetHTTPTimeout())
+	if dnsConf.TTL == "" {
+		// 默认300s
+		gcd.TTL = 300
+	} else {
+		ttl, err := strconv.Atoi(dnsConf.TTL)
+		if err != nil {
+			gcd.TTL = 300
+		} else {
+			gcd.TTL = ttl
+		}
+	}
+}
+
+// AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
+func (gcd *GoogleCloudDNS) AddUpdateDomainRecords(ctx context.Context) config.Domains {
+	gcd.addUpdateDomainRecords(ctx, "A")
+	gcd.addUpdateDomainRecords(ctx, "AAAA")
+	return gcd.Domains
+}
+
+func (gcd *GoogleCloudDNS) addUpdateDomainRecords(ctx context.Context, recordType string) {
+	ipAddr, domains := gcd.Domains.GetNewIpResult(recordType)
+
+	if ipAddr == "" {
+		return
+	}
+
+	key, err := util.ParseGoogleServiceAccountKey(gcd.DNS.Secret)
+	if err != nil {
+		util.LogError("查询域名信息发生异常! %s", err)
+		for _, domain := range domains {
+			domain.UpdateStatus = config.UpdatedFailed
+		}
+		return
+	}
+
+	// 项目ID为空时使用密钥中的 project_id
+	projectID := gcd.DNS.ID
+	if projectID == "" {
+		projectID = key.ProjectID
+	}
+	projectURL := googleCloudDNSEndpoint + "/projects/" + url.PathEscape(projectID)
+
+	for _, domain := range domains {
+		zone, err := gcd.getManagedZone(ctx, key, projectURL, domain)
+		if err != nil {
+			util.LogError("查询域名信息发生异常! %s", err)
+			domain.UpdateStatus = config.UpdatedFailed
+			continue
+		}
+		if zone == "" {
+			util.LogError("在DNS服务商中未找到根域名: %s", domain.DomainName)
+			domain.UpdateStatus = config.UpdatedFailed
+			continue
+		}
+		zoneURL := projectURL + "/managedZones/" + url.PathEscape(zone)
+
+		params := url.Values{}
+		params.Set("name", domain.String()+".")
+		params.Set("type", recordType)
+		var records GoogleCloudDNSRecordSetsResp
+		err = gcd.request(ctx, key, http.MethodGet, zoneURL+"/rrsets?"+params.Encode(), nil, &records)
+		if err != nil {
+			util.LogError("查询域名信息发生异常! %s", err)
+			domain.UpdateStatus = config.UpdatedFailed
+			continue
+		}
+
+		gcd.createOrModify(ctx, key, zoneURL, records.Rrsets, domain, recordType, ipAddr)
+	}
+}
+
+// getManagedZone 获得托管区域名称, 已配置 Zone ID 时直接使用
+func (gcd *GoogleCloudDNS) getManagedZone(ctx context.Context, key util.GoogleServiceAccountKey, projectURL string, domain *config.Domain) (string, error) {
+	if gcd.DNS.ZoneID != "" {
+		return gcd.DNS.ZoneID, nil
+	}
+
+	var result GoogleCloudDNSManagedZonesResp
+	err := gcd.request(ctx, key, http.MethodGet, projectURL+"/managedZones?dnsName="+url.QueryEscape(domain.DomainName+"."), nil, &result)
+	if err != nil {
+		return "", err
+	}
+	for _, zone := range result.ManagedZones {
+		if strings.EqualFold(strings.TrimSuffix(zone.DNSName, "."), domain.DomainName) {
+			return zone.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// createOrModify 先删除已有记录集再新增
+func (gcd *GoogleCloudDNS) createOrModify(ctx context.Context, key util.GoogleServiceAccountKey, zoneURL string, existing []GoogleCloudDNSRecordSet, domain *config.Domain, recordType string, ipAddr string) {
+	if len(existing) == 0 && skipCreate(domain) {
+		return
+	}
+	operation := "新增"
+	if len(existing) > 0 {
+		// 相同不修改
+		if len(existing[0].Rrdatas) == 1 && existing[0].Rrdatas[0] == ipAddr && existing[0].TTL == gcd.TTL {
+			util.LogDebug("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+			domain.UpdateStatus = config.UpdatedNothing
+			return
+		}
+		operation = "更新"
+	}
+
+	change := GoogleCloudDNSChange{
+		Additions: []GoogleCloudDNSRecordSet{{
+			Name:    domain.String() + ".",
+			Type:    recordType,
+			TTL:     gcd.TTL,
+			Rrdatas: []string{ipAddr},
+		}},
+		// 删除时必须和现有记录集完全一致
+		Deletions: existing,
+	}
+	if dryRun(domain, http.MethodPost, zoneURL+"/changes", change) {
+		return
+	}
+
+	var result GoogleCloudDNSChange
+	err := gcd.request(ctx, key, http.MethodPost, zoneURL+"/changes", change, &result)
+	if err != nil {
+		util.LogError(operation+"域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+
+	util.Log(operation+"域名解析 %s 成功! IP: %s", domain, ipAddr)
+	domain.UpdateStatus = config.UpdatedSuccess
+}
+
+// request 统一请求接口
+func (gcd *GoogleCloudDNS) request(ctx context.Context, key util.GoogleServiceAccountKey, method, url string, body interface{}, result interface{}) error {
+	token, err := util.GoogleServiceAccountToken(ctx, gcd.client, key, googleCloudDNSScope)
+	if err != nil {
+		return err
+	}
+
+	req, err := util.NewJSONRequest(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := gcd.client.Do(req)
+	return util.GetHTTPResponse(resp, err, result)
+}
@@ -1,6 +1,7 @@
 package dns
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"net/url"
@@ -21,6 +22,7 @@ type GoogleDomain struct {
 	Domains  config.Domains
 	lastIpv4 string
 	lastIpv6 string
+	client   *http.Client
 }
 
 // GoogleDomainResp 修改域名解析结果
@@ -29,22 +31,27 @@ type GoogleDomainResp struct {
 	SetedIP string
 }
 
+func init() {
+	RegisterDNS("googledomain", func() DNS { return &GoogleDomain{} })
+}
+
 // Init 初始化
 func (gd *GoogleDomain) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
 	gd.Domains.Ipv4Cache = ipv4cache
 	gd.Domains.Ipv6Cache = ipv6cache
 	gd.DNS = dnsConf.DNS
 	gd.Domains.GetNewIp(dnsConf)
+	gd.client = util.CreateHTTPClientTimeout(dnsConf.GetHTTPTimeout())
 }
 
 // AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
-func (gd *GoogleDomain) AddUpdateDomainRecords() config.Domains {
-	gd.addUpdateDomainRecords("A")
-	gd.addUpdateDomainRecords("AAAA")
+func (gd *GoogleDomain) AddUpdateDomainRecords(ctx context.Context) config.Domains {
+	gd.addUpdateDomainRecords(ctx, "A")
+	gd.addUpdateDomainRecords(ctx, "AAAA")
 	return gd.Domains
 }
 
-func (gd *GoogleDomain) addUpdateDomainRecords(recordType string) {
+func (gd *GoogleDomain) addUpdateDomainRecords(ctx context.Context, recordType string) {
 	ipAddr, domains := gd.Domains.GetNewIpResult(recordType)
 
 	if ipAddr == "" {
@@ -54,52 +61,58 @@ func (gd *GoogleDomain) addUpdateDomainRecords(recordType string) {
 	// 防止多次发送Webhook通知
 	if recordType == "A" {
 		if gd.lastIpv4 == ipAddr {
-			util.Log("你的IPv4未变化, 未触发 %s 请求", "GoogleDomain")
+			util.LogDebug("你的IPv4未变化, 未触发 %s 请求", "GoogleDomain")
 			return
 		}
 	} else {
 		if gd.lastIpv6 == ipAddr {
-			util.Log("你的IPv6未变化, 未触发 %s 请求", "GoogleDomain")
+			util.LogDebug("你的IPv6未变化, 未触发 %s 请求", "GoogleDomain")
 			return
 		}
 	}
 
 	for _, domain := range domains {
-		gd.modify(domain, ipAddr)
+		gd.modify(ctx, domain, ipAddr)
 	}
 }
 
 // 修改
-func (gd *GoogleDomain) modify(domain *config.Domain, ipAddr string) {
+func (gd *GoogleDomain) modify(ctx context.Context, domain *config.Domain, ipAddr string) {
 	params := domain.GetCustomParams()
 	params.Set("hostname", domain.GetFullDomain())
 	params.Set("myip", ipAddr)
 
+	if dryRun(domain, http.MethodPost, googleDomainEndpoint, params) {
+		return
+	}
+
 	var result GoogleDomainResp
-	err := gd.request(params, &result)
+	err := gd.request(ctx, params, &result)
 
 	if err != nil {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
 
 	switch result.Status {
 	case "nochg":
-		util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		util.LogDebug("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		domain.UpdateStatus = config.UpdatedNothing
 	case "good":
 		util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	default:
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, result.Status)
+		util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, result.Status)
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
 
 // request 统一请求接口
-func (gd *GoogleDomain) request(params url.Values, result *GoogleDomainResp) (err error) {
+func (gd *GoogleDomain) request(ctx context.Context, params url.Values, result *GoogleDomainResp) (err error) {
 
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodPost,
 		googleDomainEndpoint,
 		http.NoBody,
@@ -112,8 +125,7 @@ func (gd *GoogleDomain) request(params url.Values, result *GoogleDomainResp) (er
 	req.URL.RawQuery = params.Encode()
 	req.SetBasicAuth(gd.DNS.ID, gd.DNS.Secret)
 
-	client := util.CreateHTTPClient()
-	resp, err := client.Do(req)
+	resp, err := gd.client.Do(req)
 	if err != nil {
 		return
 	}
@@ -0,0 +1,281 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+const (
+	hetznerEndpoint = "https://dns.hetzner.com/api/v1"
+)
+
+// Hetzner Hetzner DNS实现
+// https://dns.hetzner.com/api-docs
+type Hetzner struct {
+	DNS             config.DNS
+	Domains         config.Domains
+	TTL             int
+	CleanDuplicates bool
+	client          *http.Client
+}
+
+// HetznerZonesResp zones返回结果
+type HetznerZonesResp struct {
+	Zones []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"zones"`
+}
+
+// HetznerRecord 记录实体
+type HetznerRecord struct {
+	ID     string `json:"id,omitempty"`
+	ZoneID string `json:"zone_id"`
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	TTL    int    `json:"ttl"`
+}
+
+// HetznerRecordsResp records返回结果
+type HetznerRecordsResp struct {
+	Records []HetznerRecord `json:"records"`
+}
+
+// HetznerRecordResp 新增或更新记录返回结果
+type HetznerRecordResp struct {
+	Record HetznerRecord `json:"record"`
+}
+
+func init() {
+	RegisterDNS("hetzner", func() DNS { return &Hetzner{} })
+}
+
+// Init 初始化
+func (hz *Hetzner) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
+	hz.Domains.Ipv4Cache = ipv4cache
+	hz.Domains.Ipv6Cache = ipv6cache
+	hz.DNS = dnsConf.DNS
+	hz.CleanDuplicates = dnsConf.CleanDuplicates
+	hz.Domains.GetNewIp(dnsConf)
+	hz.client = util.CreateHTTPClientTimeout(dnsConf.GetHTTPTimeout())
+	if dnsConf.TTL == "" {
+		// 默认300s
+		hz.TTL = 300
+	} else {
+		ttl, err := strconv.Atoi(dnsConf.TTL)
+		if err != nil {
+			hz.TTL = 300
+		} else {
+			hz.TTL = ttl
+		}
+	}
+}
+
+// AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
+func (hz *Hetzner) AddUpdateDomainRecords(ctx context.Context) config.Domains {
+	hz.addUpdateDomainRecords(ctx, "A")
+	hz.addUpdateDomainRecords(ctx, "AAAA")
+	return hz.Domains
+}
+
+func (hz *Hetzner) addUpdateDomainRecords(ctx context.Context, recordType string) {
+	ipAddr, domains := hz.Domains.GetNewIpResult(recordType)
+
+	if ipAddr == "" {
+		return
+	}
+
+	forEachDomain(domains, func(domain *config.Domain) {
+		zoneID, err := hz.getZoneID(ctx, domain)
+		if err != nil {
+			util.LogError("查询域名信息发生异常! %s", err)
+			domain.UpdateStatus = config.UpdatedFailed
+			return
+		}
+		if zoneID == "" {
+			util.LogError("在DNS服务商中未找到根域名: %s", domain.DomainName)
+			domain.UpdateStatus = config.UpdatedFailed
+			return
+		}
+
+		records, err := hz.getRecords(ctx, zoneID, domain, recordType)
+		if err != nil {
+			util.LogError("查询域名信息发生异常! %s", err)
+			domain.UpdateStatus = config.UpdatedFailed
+			return
+		}
+
+		if len(records) > 0 {
+			hz.modify(ctx, records[0], domain, ipAddr)
+			// 开启后清理多余的相同解析记录
+			if hz.CleanDuplicates {
+				hz.cleanDuplicateRecords(ctx, records[1:], domain)
+			}
+		} else {
+			hz.create(ctx, zoneID, domain, recordType, ipAddr)
+		}
+	})
+}
+
+// getZoneID 获得根域名的zone ID, 已配置 Zone ID 时直接使用
+func (hz *Hetzner) getZoneID(ctx context.Context, domain *config.Domain) (string, error) {
+	if hz.DNS.ZoneID != "" {
+		return hz.DNS.ZoneID, nil
+	}
+
+	var result HetznerZonesResp
+	err := hz.request(ctx, http.MethodGet, hetznerEndpoint+"/zones?name="+domain.DomainName, nil, &result)
+	if err != nil || len(result.Zones) == 0 {
+		return "", err
+	}
+	return result.Zones[0].ID, nil
+}
+
+// getRecords 获得zone下与域名和类型相同的记录
+func (hz *Hetzner) getRecords(ctx context.Context, zoneID string, domain *config.Domain, recordType string) ([]HetznerRecord, error) {
+	var result HetznerRecordsResp
+	err := hz.request(ctx, http.MethodGet, hetznerEndpoint+"/records?zone_id="+zoneID, nil, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []HetznerRecord
+	for _, record := range result.Records {
+		if record.Type == recordType && record.Name == domain.GetSubDomain() {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func (hz *Hetzner) create(ctx context.Context, zoneID string, domain *config.Domain, recordType string, ipAddr string) {
+	if skipCreate(domain) {
+		return
+	}
+	record := &HetznerRecord{
+		ZoneID: zoneID,
+		Type:   recordType,
+		Name:   domain.GetSubDomain(),
+		Value:  ipAddr,
+		TTL:    hz.TTL,
+	}
+	if dryRun(domain, http.MethodPost, hetznerEndpoint+"/records", record) {
+		return
+	}
+
+	var result HetznerRecordResp
+	err := hz.request(ctx, http.MethodPost, hetznerEndpoint+"/records", record, &result)
+	if err != nil {
+		util.LogError("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+
+	util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
+	domain.UpdateStatus = config.UpdatedSuccess
+}
+
+func (hz *Hetzner) modify(ctx context.Context, record HetznerRecord, domain *config.Domain, ipAddr string) {
+	// 相同不修改
+	if record.Value == ipAddr && record.TTL == hz.TTL {
+		util.LogDebug("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		domain.UpdateStatus = config.UpdatedNothing
+		return
+	}
+
+	recordURL := fmt.Sprintf(hetznerEndpoint+"/records/%s", record.ID)
+	record.Value = ipAddr
+	record.TTL = hz.TTL
+	if dryRun(domain, http.MethodPut, recordURL, record) {
+		return
+	}
+
+	var result HetznerRecordResp
+	err := hz.request(ctx, http.MethodPut, recordURL, record, &result)
+	if err != nil {
+		util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+
+	util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+	domain.UpdateStatus = config.UpdatedSuccess
+}
+
+// cleanDuplicateRecords 删除多余的相同解析记录
+func (hz *Hetzner) cleanDuplicateRecords(ctx context.Context, records []HetznerRecord, domain *config.Domain) {
+	for _, record := range records {
+		recordURL := fmt.Sprintf(hetznerEndpoint+"/records/%s", record.ID)
+		if dryRun(domain, http.MethodDelete, recordURL, nil) {
+			continue
+		}
+
+		err := hz.request(ctx, http.MethodDelete, recordURL, nil, nil)
+		if err != nil {
+			util.LogError("删除多余域名解析 %s 失败! 异常信息: %s", domain, err)
+		} else {
+			util.Log("删除多余域名解析 %s 成功!", domain)
+		}
+	}
+}
+
+// DeleteRecords 删除域名名称相同的全部 recordType 记录
+func (hz *Hetzner) DeleteRecords(ctx context.Context, recordType string, domains []*config.Domain) {
+	forEachDomain(domains, func(domain *config.Domain) {
+		zoneID, err := hz.getZoneID(ctx, domain)
+		if err != nil {
+			util.LogError("查询域名信息发生异常! %s", err)
+			return
+		}
+		if zoneID == "" {
+			util.LogError("在DNS服务商中未找到根域名: %s", domain.DomainName)
+			return
+		}
+
+		records, err := hz.getRecords(ctx, zoneID, domain, recordType)
+		if err != nil {
+			util.LogError("查询域名信息发生异常! %s", err)
+			return
+		}
+		for _, record := range records {
+			recordURL := fmt.Sprintf(hetznerEndpoint+"/records/%s", record.ID)
+			if dryRun(domain, http.MethodDelete, recordURL, nil) {
+				continue
+			}
+			err := hz.request(ctx, http.MethodDelete, recordURL, nil, nil)
+			if err != nil {
+				util.LogError("删除域名解析 %s 失败! 异常信息: %s", domain, err)
+			} else {
+				util.Log("删除域名解析 %s 成功!", domain)
+			}
+		}
+	})
+}
+
+// Check 获取zone, 检查令牌是否正确
+func (hz *Hetzner) Check(ctx context.Context) error {
+	url := hetznerEndpoint + "/zones?per_page=1"
+	if hz.DNS.ZoneID != "" {
+		url = hetznerEndpoint + "/zones/" + hz.DNS.ZoneID
+	}
+	return hz.request(ctx, http.MethodGet, url, nil, nil)
+}
+
+// request 统一请求接口
+func (hz *Hetzner) request(ctx context.Context, method, url string, body interface{}, result interface{}) error {
+	req, err := util.NewJSONRequest(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Auth-API-Token", hz.DNS.Secret)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := hz.client.Do(req)
+	return util.GetHTTPResponse(resp, err, result)
+}
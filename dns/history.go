@@ -0,0 +1,95 @@
+package dns
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// 最多保存的IP变化记录数
+const maxHistory = 200
+
+// 历史记录文件名, 与配置文件在同一目录
+const historyFileName = ".ddns_go_history.json"
+
+// HistoryEntry 一条IP变化记录
+type HistoryEntry struct {
+	Time     time.Time `json:"time"`
+	Provider string    `json:"provider"`
+	config.DomainResult
+}
+
+var (
+	history       []HistoryEntry
+	historyLoaded bool
+	historyLock   sync.Mutex
+)
+
+// getHistoryFilePath 获得历史记录文件路径
+func getHistoryFilePath() string {
+	return filepath.Join(filepath.Dir(util.GetConfigFilePath()), historyFileName)
+}
+
+// loadHistory 首次使用时从文件读取历史记录, 需持有 historyLock
+func loadHistory() {
+	if historyLoaded {
+		return
+	}
+	historyLoaded = true
+	byt, err := os.ReadFile(getHistoryFilePath())
+	if err != nil {
+		return
+	}
+	if err := json.Unmarshal(byt, &history); err != nil {
+		util.LogError("读取历史记录失败: %s", err)
+	}
+}
+
+// GetHistory 获得最近的 n 条IP变化记录, 最新的在前
+func GetHistory(n int) []HistoryEntry {
+	historyLock.Lock()
+	defer historyLock.Unlock()
+	loadHistory()
+
+	if n > len(history) {
+		n = len(history)
+	}
+	entries := make([]HistoryEntry, 0, n)
+	for i := len(history) - 1; i >= len(history)-n; i-- {
+		entries = append(entries, history[i])
+	}
+	return entries
+}
+
+// addHistory 记录更新成功或失败的域名, 超过 maxHistory 时删除最早的记录
+func addHistory(provider string, domains *config.Domains) {
+	historyLock.Lock()
+	defer historyLock.Unlock()
+	loadHistory()
+
+	now := time.Now()
+	added := false
+	for _, result := range config.GetDomainResults(domains) {
+		if result.Status == "nothing" {
+			continue
+		}
+		history = append(history, HistoryEntry{Time: now, Provider: provider, DomainResult: result})
+		added = true
+	}
+	if !added {
+		return
+	}
+	if len(history) > maxHistory {
+		history = history[len(history)-maxHistory:]
+	}
+
+	byt, _ := json.Marshal(history)
+	if err := os.WriteFile(getHistoryFilePath(), byt, 0600); err != nil {
+		util.LogError("保存历史记录失败: %s", err)
+	}
+}
@@ -0,0 +1,38 @@
+package dns
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// TestHistory 测试历史记录的保存、读取及数量限制
+func TestHistory(t *testing.T) {
+	t.Setenv(util.ConfigFilePathENV, filepath.Join(t.TempDir(), ".ddns_go_config.yaml"))
+	resetHistory := func() {
+		history, historyLoaded = nil, false
+	}
+	resetHistory()
+	defer resetHistory()
+
+	domain := &config.Domain{DomainName: "example.com", UpdateStatus: config.UpdatedSuccess}
+	nothing := &config.Domain{DomainName: "example.net", UpdateStatus: config.UpdatedNothing}
+	for i := 0; i < maxHistory+1; i++ {
+		addHistory("cloudflare", &config.Domains{Ipv4Addr: "1.2.3.4", Ipv4Domains: []*config.Domain{domain, nothing}})
+	}
+	domain.UpdateStatus = config.UpdatedFailed
+	addHistory("cloudflare", &config.Domains{Ipv4Addr: "5.6.7.8", Ipv4Domains: []*config.Domain{domain}})
+
+	// 重新从文件读取
+	resetHistory()
+	if got := len(GetHistory(maxHistory * 2)); got != maxHistory {
+		t.Errorf("期待 %d 条记录, 得到 %d", maxHistory, got)
+	}
+	entries := GetHistory(2)
+	if len(entries) != 2 || entries[0].NewIP != "5.6.7.8" || entries[0].Status != "failed" ||
+		entries[1].NewIP != "1.2.3.4" || entries[1].Domain != "example.com" {
+		t.Errorf("记录不正确: %+v", entries)
+	}
+}
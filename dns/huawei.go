@@ -2,6 +2,7 @@ package dns
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -21,6 +22,7 @@ type Huaweicloud struct {
 	DNS     config.DNS
 	Domains config.Domains
 	TTL     int
+	client  *http.Client
 }
 
 // HuaweicloudZonesResp zones response
@@ -48,12 +50,17 @@ type HuaweicloudRecordsets struct {
 	Records []string `json:"records"`
 }
 
+func init() {
+	RegisterDNS("huaweicloud", func() DNS { return &Huaweicloud{} })
+}
+
 // Init 初始化
 func (hw *Huaweicloud) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
 	hw.Domains.Ipv4Cache = ipv4cache
 	hw.Domains.Ipv6Cache = ipv6cache
 	hw.DNS = dnsConf.DNS
 	hw.Domains.GetNewIp(dnsConf)
+	hw.client = util.CreateHTTPClientTimeout(dnsConf.GetHTTPTimeout())
 	if dnsConf.TTL == "" {
 		// 默认300s
 		hw.TTL = 300
@@ -68,24 +75,25 @@ func (hw *Huaweicloud) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache,
 }
 
 // AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
-func (hw *Huaweicloud) AddUpdateDomainRecords() config.Domains {
-	hw.addUpdateDomainRecords("A")
-	hw.addUpdateDomainRecords("AAAA")
+func (hw *Huaweicloud) AddUpdateDomainRecords(ctx context.Context) config.Domains {
+	hw.addUpdateDomainRecords(ctx, "A")
+	hw.addUpdateDomainRecords(ctx, "AAAA")
 	return hw.Domains
 }
 
-func (hw *Huaweicloud) addUpdateDomainRecords(recordType string) {
+func (hw *Huaweicloud) addUpdateDomainRecords(ctx context.Context, recordType string) {
 	ipAddr, domains := hw.Domains.GetNewIpResult(recordType)
 
 	if ipAddr == "" {
 		return
 	}
 
-	for _, domain := range domains {
+	forEachDomain(domains, func(domain *config.Domain) {
 
 		var records HuaweicloudRecordsResp
 
 		err := hw.request(
+			ctx,
 			"GET",
 			fmt.Sprintf(huaweicloudEndpoint+"/v2/recordsets?type=%s&name=%s", recordType, domain),
 			nil,
@@ -93,7 +101,7 @@ func (hw *Huaweicloud) addUpdateDomainRecords(recordType string) {
 		)
 
 		if err != nil {
-			util.Log("查询域名信息发生异常! %s", err)
+			util.LogError("查询域名信息发生异常! %s", err)
 			domain.UpdateStatus = config.UpdatedFailed
 			return
 		}
@@ -103,7 +111,7 @@ func (hw *Huaweicloud) addUpdateDomainRecords(recordType string) {
 			// 名称相同才更新。华为云默认是模糊搜索
 			if record.Name == domain.String()+"." {
 				// 更新
-				hw.modify(record, domain, ipAddr)
+				hw.modify(ctx, record, domain, ipAddr)
 				find = true
 				break
 			}
@@ -111,23 +119,26 @@ func (hw *Huaweicloud) addUpdateDomainRecords(recordType string) {
 
 		if !find {
 			// 新增
-			hw.create(domain, recordType, ipAddr)
+			hw.create(ctx, domain, recordType, ipAddr)
 		}
 
-	}
+	})
 }
 
 // 创建
-func (hw *Huaweicloud) create(domain *config.Domain, recordType string, ipAddr string) {
-	zone, err := hw.getZones(domain)
+func (hw *Huaweicloud) create(ctx context.Context, domain *config.Domain, recordType string, ipAddr string) {
+	if skipCreate(domain) {
+		return
+	}
+	zone, err := hw.getZones(ctx, domain)
 	if err != nil {
-		util.Log("查询域名信息发生异常! %s", err)
+		util.LogError("查询域名信息发生异常! %s", err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
 
 	if len(zone.Zones) == 0 {
-		util.Log("在DNS服务商中未找到根域名: %s", domain.DomainName)
+		util.LogError("在DNS服务商中未找到根域名: %s", domain.DomainName)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
@@ -146,8 +157,13 @@ func (hw *Huaweicloud) create(domain *config.Domain, recordType string, ipAddr s
 		Records: []string{ipAddr},
 		TTL:     hw.TTL,
 	}
+	if dryRun(domain, "POST", fmt.Sprintf(huaweicloudEndpoint+"/v2/zones/%s/recordsets", zoneID), record) {
+		return
+	}
+
 	var result HuaweicloudRecordsets
 	err = hw.request(
+		ctx,
 		"POST",
 		fmt.Sprintf(huaweicloudEndpoint+"/v2/zones/%s/recordsets", zoneID),
 		record,
@@ -155,7 +171,7 @@ func (hw *Huaweicloud) create(domain *config.Domain, recordType string, ipAddr s
 	)
 
 	if err != nil {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogError("新增域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
@@ -164,17 +180,18 @@ func (hw *Huaweicloud) create(domain *config.Domain, recordType string, ipAddr s
 		util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, result.Status)
+		util.LogError("新增域名解析 %s 失败! 异常信息: %s", domain, result.Status)
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
 
 // 修改
-func (hw *Huaweicloud) modify(record HuaweicloudRecordsets, domain *config.Domain, ipAddr string) {
+func (hw *Huaweicloud) modify(ctx context.Context, record HuaweicloudRecordsets, domain *config.Domain, ipAddr string) {
 
 	// 相同不修改
 	if len(record.Records) > 0 && record.Records[0] == ipAddr {
-		util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		util.LogDebug("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		domain.UpdateStatus = config.UpdatedNothing
 		return
 	}
 
@@ -182,9 +199,14 @@ func (hw *Huaweicloud) modify(record HuaweicloudRecordsets, domain *config.Domai
 	request["records"] = []string{ipAddr}
 	request["ttl"] = hw.TTL
 
+	if dryRun(domain, "PUT", fmt.Sprintf(huaweicloudEndpoint+"/v2/zones/%s/recordsets/%s", record.ZoneID, record.ID), request) {
+		return
+	}
+
 	var result HuaweicloudRecordsets
 
 	err := hw.request(
+		ctx,
 		"PUT",
 		fmt.Sprintf(huaweicloudEndpoint+"/v2/zones/%s/recordsets/%s", record.ZoneID, record.ID),
 		&request,
@@ -192,7 +214,7 @@ func (hw *Huaweicloud) modify(record HuaweicloudRecordsets, domain *config.Domai
 	)
 
 	if err != nil {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
@@ -201,14 +223,15 @@ func (hw *Huaweicloud) modify(record HuaweicloudRecordsets, domain *config.Domai
 		util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, result.Status)
+		util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, result.Status)
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
 
 // 获得域名记录列表
-func (hw *Huaweicloud) getZones(domain *config.Domain) (result HuaweicloudZonesResp, err error) {
+func (hw *Huaweicloud) getZones(ctx context.Context, domain *config.Domain) (result HuaweicloudZonesResp, err error) {
 	err = hw.request(
+		ctx,
 		"GET",
 		fmt.Sprintf(huaweicloudEndpoint+"/v2/zones?name=%s", domain.DomainName),
 		nil,
@@ -219,13 +242,14 @@ func (hw *Huaweicloud) getZones(domain *config.Domain) (result HuaweicloudZonesR
 }
 
 // request 统一请求接口
-func (hw *Huaweicloud) request(method string, url string, data interface{}, result interface{}) (err error) {
+func (hw *Huaweicloud) request(ctx context.Context, method string, url string, data interface{}, result interface{}) (err error) {
 	jsonStr := make([]byte, 0)
 	if data != nil {
 		jsonStr, _ = json.Marshal(data)
 	}
 
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		method,
 		url,
 		bytes.NewBuffer(jsonStr),
@@ -243,8 +267,7 @@ func (hw *Huaweicloud) request(method string, url string, data interface{}, resu
 
 	req.Header.Add("content-type", "application/json")
 
-	client := util.CreateHTTPClient()
-	resp, err := client.Do(req)
+	resp, err := hw.client.Do(req)
 	err = util.GetHTTPResponse(resp, err, result)
 
 	return
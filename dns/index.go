@@ -1,6 +1,13 @@
 package dns
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/jeessy2/ddns-go/v6/config"
@@ -10,8 +17,8 @@ import (
 // DNS interface
 type DNS interface {
 	Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache)
-	// 添加或更新IPv4/IPv6记录
-	AddUpdateDomainRecords() (domains config.Domains)
+	// 添加或更新IPv4/IPv6记录, ctx 取消时结束进行中的请求
+	AddUpdateDomainRecords(ctx context.Context) (domains config.Domains)
 }
 
 var (
@@ -27,70 +34,142 @@ var (
 		porkbunEndpoint,
 		tencentCloudEndPoint,
 		dynadotEndpoint,
+		desecEndpoint,
+		hetznerEndpoint,
+		gandiEndpoint,
+		linodeEndpoint,
+		vultrEndpoint,
+		digitalOceanEndpoint,
+		dynuEndpoint,
+		duckDNSEndpoint,
+		dynDNS2DefaultEndpoint,
+		route53Endpoint,
+		azureEndpoint,
+		googleCloudDNSEndpoint,
+		ovhEndpoint,
 	}
 
 	Ipcache = [][2]util.IpCache{}
+
+	// 每个配置下次运行的时间, 与 Ipcache 一一对应
+	nextRunTimes = []time.Time{}
+
+	// DryRun 演练模式, 只记录将要进行的修改, 不调用修改接口
+	DryRun = false
+
+	// Concurrency 同时更新的域名数量, 小于等于1时逐个更新
+	Concurrency = 5
+
+	// Jitter 每次定时运行的随机偏移(±), 避免大量实例同时请求获取IP的接口, 为0时不偏移
+	Jitter = 15 * time.Second
+
+	// 本次运行是否为演练模式, 命令行参数或配置文件开启任一即可
+	dryRunEnabled = false
+
+	// 同一时间只运行一次, 保存或重新加载配置时触发的运行需等待正在运行的完成
+	runLock sync.Mutex
+
+	// 已注册的DNS服务商, 名称 => 创建函数
+	providers     = map[string]func() DNS{}
+	providersLock sync.RWMutex
 )
 
-// RunTimer 定时运行
+// RunTimer 定时运行, 每个配置按自己的间隔时间运行, 未设置时使用 delay
 func RunTimer(delay time.Duration) {
 	for {
-		RunOnce()
-		time.Sleep(delay)
+		_, wait := run(delay)
+		time.Sleep(wait)
 	}
 }
 
 // RunOnce RunOnce
 func RunOnce() {
+	RunOnceResult()
+}
+
+// RunOnceResult 运行一次, 返回所有配置的更新结果
+func RunOnceResult() (result config.UpdateResult) {
+	result, _ = run(0)
+	return
+}
+
+// run 运行到达间隔时间的配置, delay 为0时运行全部配置
+// 返回更新结果及距离下次有配置需要运行的时间
+func run(delay time.Duration) (result config.UpdateResult, wait time.Duration) {
+	runLock.Lock()
+	defer runLock.Unlock()
+	return runLocked(delay)
+}
+
+// RunNow 立即运行全部配置, 已有正在运行的更新时不运行并返回 false
+func RunNow() (result config.UpdateResult, ok bool) {
+	if !runLock.TryLock() {
+		return result, false
+	}
+	defer runLock.Unlock()
+	result, _ = runLocked(0)
+	return result, true
+}
+
+// runLocked 同 run, 调用方需持有 runLock
+func runLocked(delay time.Duration) (result config.UpdateResult, wait time.Duration) {
+	wait = delay
+	if stopping.Load() {
+		return
+	}
 	conf, err := config.GetConfigCached()
 	if err != nil {
 		return
 	}
 	if util.ForceCompareGlobal || len(Ipcache) != len(conf.DnsConf) {
 		Ipcache = [][2]util.IpCache{}
+		nextRunTimes = []time.Time{}
 		for range conf.DnsConf {
 			Ipcache = append(Ipcache, [2]util.IpCache{{}, {}})
+			nextRunTimes = append(nextRunTimes, time.Time{})
 		}
+		resetStatus(len(conf.DnsConf))
+	}
+	// 在开始时清除, 运行期间再次设置时下次运行仍会重置
+	util.ForceCompareGlobal = false
+
+	dryRunEnabled = DryRun || conf.DryRun
+	if dryRunEnabled {
+		util.Log("演练模式已开启, 不会修改任何解析记录")
 	}
 
+	// 退出时取消
+	ctx := util.ShutdownContext()
+	wait = 0
 	for i, dc := range conf.DnsConf {
-		var dnsSelected DNS
-		switch dc.DNS.Name {
-		case "alidns":
-			dnsSelected = &Alidns{}
-		case "tencentcloud":
-			dnsSelected = &TencentCloud{}
-		case "dnspod":
-			dnsSelected = &Dnspod{}
-		case "cloudflare":
-			dnsSelected = &Cloudflare{}
-		case "huaweicloud":
-			dnsSelected = &Huaweicloud{}
-		case "callback":
-			dnsSelected = &Callback{}
-		case "baiducloud":
-			dnsSelected = &BaiduCloud{}
-		case "porkbun":
-			dnsSelected = &Porkbun{}
-		case "godaddy":
-			dnsSelected = &GoDaddyDNS{}
-		case "googledomain":
-			dnsSelected = &GoogleDomain{}
-		case "namecheap":
-			dnsSelected = &NameCheap{}
-		case "namesilo":
-			dnsSelected = &NameSilo{}
-		case "vercel":
-			dnsSelected = &Vercel{}
-		case "dynadot":
-			dnsSelected = &Dynadot{}
-		default:
-			dnsSelected = &Alidns{}
+		if !dc.Enabled {
+			util.LogDebug("第 %s 个配置已禁用, 将不会更新", util.Ordinal(i+1, conf.Lang))
+			disableStatus(i)
+			continue
 		}
+		if delay > 0 {
+			if now := time.Now(); now.Before(nextRunTimes[i]) {
+				wait = minWait(wait, nextRunTimes[i].Sub(now))
+				continue
+			}
+		}
+
+		dnsSelected := newDNS(dc.DNS.Name)
 		dnsSelected.Init(&dc, &Ipcache[i][0], &Ipcache[i][1])
-		domains := dnsSelected.AddUpdateDomainRecords()
-		// webhook
-		v4Status, v6Status := config.ExecWebhook(&domains, &conf)
+		domains := dnsSelected.AddUpdateDomainRecords(ctx)
+		deleteUnavailable(ctx, dnsSelected, &dc, &domains)
+		result.Add(domains.Result())
+		recordMetrics(dc.DNS.Name, &domains)
+		updateStatus(i, dc.DNS.Name, &domains)
+		// 演练模式未实际修改, 不记录
+		if !dryRunEnabled {
+			addHistory(dc.DNS.Name, &domains)
+		}
+		// webhook 等通知
+		v4Status, v6Status := config.ExecNotify(&domains, &conf)
+		if !dryRunEnabled {
+			config.ExecHook(&domains, &conf)
+		}
 		// 重置单个cache
 		if v4Status == config.UpdatedFailed {
 			Ipcache[i][0] = util.IpCache{}
@@ -98,7 +177,124 @@ func RunOnce() {
 		if v6Status == config.UpdatedFailed {
 			Ipcache[i][1] = util.IpCache{}
 		}
+		// 演练模式未实际修改, 下次仍需比较
+		if dryRunEnabled {
+			Ipcache[i] = [2]util.IpCache{{}, {}}
+		}
+
+		interval := withJitter(dc.GetInterval(delay))
+		nextRunTimes[i] = time.Now().Add(interval)
+		wait = minWait(wait, interval)
 	}
+	if wait <= 0 {
+		wait = delay
+	}
+	return
+}
 
-	util.ForceCompareGlobal = false
+// recordMetrics 记录有更新的域名的指标
+func recordMetrics(provider string, domains *config.Domains) {
+	record := func(list []*config.Domain, recordType string) {
+		for _, domain := range list {
+			if domain.UpdateStatus == config.UpdatedNothing {
+				continue
+			}
+			util.RecordUpdate(provider, domain.String(), recordType, domain.UpdateStatus == config.UpdatedSuccess)
+		}
+	}
+	record(domains.Ipv4Domains, "A")
+	record(domains.Ipv6Domains, "AAAA")
+	record(domains.CnameDomains, "CNAME")
+	record(domains.MxDomains, "MX")
+}
+
+// withJitter 为间隔时间加上 ±Jitter 的随机偏移, 偏移不超过间隔时间的1/10
+func withJitter(interval time.Duration) time.Duration {
+	jitter := Jitter
+	if jitter > interval/10 {
+		jitter = interval / 10
+	}
+	if jitter <= 0 {
+		return interval
+	}
+	return interval - jitter + time.Duration(rand.Int63n(int64(2*jitter)+1))
+}
+
+// minWait 返回较小的等待时间, 0 表示未设置
+func minWait(wait, d time.Duration) time.Duration {
+	if wait == 0 || d < wait {
+		return d
+	}
+	return wait
+}
+
+// RegisterDNS 注册DNS服务商, name 为配置中的 DNS.Name, 已注册时覆盖
+// 每个DNS服务商在自己的 init 中注册
+func RegisterDNS(name string, newProvider func() DNS) {
+	providersLock.Lock()
+	defer providersLock.Unlock()
+	providers[name] = newProvider
+}
+
+// newDNS 根据名称创建DNS服务商, 未知名称使用阿里云
+func newDNS(name string) DNS {
+	providersLock.RLock()
+	defer providersLock.RUnlock()
+	newProvider, ok := providers[name]
+	if !ok {
+		newProvider = providers["alidns"]
+	}
+	return newProvider()
+}
+
+// checkMinTTL TTL小于DNS服务商允许的最小值时调整为最小值
+func checkMinTTL(provider string, ttl, minTTL int) int {
+	if ttl < minTTL {
+		util.LogWarn("%s 的TTL不能小于 %d, 已调整为 %d", provider, minTTL, minTTL)
+		return minTTL
+	}
+	return ttl
+}
+
+// skipCreate 只更新不新增时记录未找到并标记失败, 返回true时调用方不再新增
+func skipCreate(domain *config.Domain) bool {
+	if !domain.UpdateOnly {
+		return false
+	}
+	util.LogError("未找到域名 %s 的记录, 已开启只更新不新增, 将不会新增", domain)
+	domain.UpdateStatus = config.UpdatedFailed
+	return true
+}
+
+// dryRun 演练模式下记录将要发送的请求, 返回true时调用方不再发送请求
+// secrets 会在日志中被隐藏
+func dryRun(domain *config.Domain, method, requestURL string, body interface{}, secrets ...string) bool {
+	if !dryRunEnabled {
+		return false
+	}
+
+	var bodyStr string
+	switch b := body.(type) {
+	case nil:
+	case string:
+		bodyStr = b
+	case url.Values:
+		bodyStr = b.Encode()
+	default:
+		byt, _ := json.Marshal(b)
+		bodyStr = string(byt)
+	}
+
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+		requestURL = strings.ReplaceAll(requestURL, secret, "***")
+		bodyStr = strings.ReplaceAll(bodyStr, secret, "***")
+		bodyStr = strings.ReplaceAll(bodyStr, url.QueryEscape(secret), "***")
+	}
+
+	util.Log("演练模式, 域名 %s 将发送请求: %s", domain, fmt.Sprintf("%s %s %s", method, requestURL, bodyStr))
+	domain.UpdateStatus = config.UpdatedNothing
+	return true
 }
@@ -0,0 +1,228 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+const (
+	linodeEndpoint = "https://api.linode.com/v4/domains"
+)
+
+// Linode Linode(Akamai)实现
+// https://www.linode.com/docs/api/domains/
+type Linode struct {
+	DNS     config.DNS
+	Domains config.Domains
+	TTL     int
+	client  *http.Client
+}
+
+// LinodeDomainsResp domains返回结果
+type LinodeDomainsResp struct {
+	Data []struct {
+		ID     int    `json:"id"`
+		Domain string `json:"domain"`
+	} `json:"data"`
+}
+
+// LinodeRecord 记录实体
+type LinodeRecord struct {
+	ID     int    `json:"id,omitempty"`
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Target string `json:"target"`
+	TTLSec int    `json:"ttl_sec"`
+}
+
+// LinodeRecordsResp records返回结果
+type LinodeRecordsResp struct {
+	Data  []LinodeRecord `json:"data"`
+	Page  int            `json:"page"`
+	Pages int            `json:"pages"`
+}
+
+func init() {
+	RegisterDNS("linode", func() DNS { return &Linode{} })
+}
+
+// Init 初始化
+func (linode *Linode) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
+	linode.Domains.Ipv4Cache = ipv4cache
+	linode.Domains.Ipv6Cache = ipv6cache
+	linode.DNS = dnsConf.DNS
+	linode.Domains.GetNewIp(dnsConf)
+	linode.client = util.CreateHTTPClientTimeout(dnsConf.GetHTTPTimeout())
+	if dnsConf.TTL == "" {
+		// 默认300s
+		linode.TTL = 300
+	} else {
+		ttl, err := strconv.Atoi(dnsConf.TTL)
+		if err != nil {
+			linode.TTL = 300
+		} else {
+			linode.TTL = ttl
+		}
+	}
+}
+
+// AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
+func (linode *Linode) AddUpdateDomainRecords(ctx context.Context) config.Domains {
+	linode.addUpdateDomainRecords(ctx, "A")
+	linode.addUpdateDomainRecords(ctx, "AAAA")
+	return linode.Domains
+}
+
+func (linode *Linode) addUpdateDomainRecords(ctx context.Context, recordType string) {
+	ipAddr, domains := linode.Domains.GetNewIpResult(recordType)
+
+	if ipAddr == "" {
+		return
+	}
+
+	forEachDomain(domains, func(domain *config.Domain) {
+		domainID, err := linode.getDomainID(ctx, domain)
+		if err != nil {
+			util.LogError("查询域名信息发生异常! %s", err)
+			domain.UpdateStatus = config.UpdatedFailed
+			return
+		}
+		if domainID == 0 {
+			util.LogError("在DNS服务商中未找到根域名: %s", domain.DomainName)
+			domain.UpdateStatus = config.UpdatedFailed
+			return
+		}
+
+		record, err := linode.getRecord(ctx, domainID, domain, recordType)
+		if err != nil {
+			util.LogError("查询域名信息发生异常! %s", err)
+			domain.UpdateStatus = config.UpdatedFailed
+			return
+		}
+
+		if record != nil {
+			linode.modify(ctx, domainID, *record, domain, ipAddr)
+		} else {
+			linode.create(ctx, domainID, domain, recordType, ipAddr)
+		}
+	})
+}
+
+// getDomainID 获得根域名的ID, 未找到时返回0
+func (linode *Linode) getDomainID(ctx context.Context, domain *config.Domain) (int, error) {
+	req, err := util.NewJSONRequest(ctx, http.MethodGet, linodeEndpoint, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("X-Filter", fmt.Sprintf(`{"domain":%q}`, domain.DomainName))
+
+	var result LinodeDomainsResp
+	err = linode.do(ctx, req, &result)
+	if err != nil || len(result.Data) == 0 {
+		return 0, err
+	}
+	return result.Data[0].ID, nil
+}
+
+// getRecord 获得名称和类型相同的记录, 不存在时返回nil
+func (linode *Linode) getRecord(ctx context.Context, domainID int, domain *config.Domain, recordType string) (*LinodeRecord, error) {
+	for page := 1; ; page++ {
+		var result LinodeRecordsResp
+		err := linode.request(
+			ctx,
+			http.MethodGet,
+			fmt.Sprintf(linodeEndpoint+"/%d/records?page=%d&page_size=500", domainID, page),
+			nil,
+			&result,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, record := range result.Data {
+			// 根域名的记录名称为空
+			if record.Type == recordType && record.Name == domain.SubDomain {
+				return &record, nil
+			}
+		}
+		if page >= result.Pages {
+			return nil, nil
+		}
+	}
+}
+
+func (linode *Linode) create(ctx context.Context, domainID int, domain *config.Domain, recordType string, ipAddr string) {
+	if skipCreate(domain) {
+		return
+	}
+	recordsURL := fmt.Sprintf(linodeEndpoint+"/%d/records", domainID)
+	record := &LinodeRecord{
+		Type:   recordType,
+		Name:   domain.SubDomain,
+		Target: ipAddr,
+		TTLSec: linode.TTL,
+	}
+	if dryRun(domain, http.MethodPost, recordsURL, record) {
+		return
+	}
+
+	var result LinodeRecord
+	err := linode.request(ctx, http.MethodPost, recordsURL, record, &result)
+	if err != nil {
+		util.LogError("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+
+	util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
+	domain.UpdateStatus = config.UpdatedSuccess
+}
+
+func (linode *Linode) modify(ctx context.Context, domainID int, record LinodeRecord, domain *config.Domain, ipAddr string) {
+	// 相同不修改, Linode 会将TTL调整为支持的值, 不参与比较
+	if record.Target == ipAddr {
+		util.LogDebug("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		domain.UpdateStatus = config.UpdatedNothing
+		return
+	}
+
+	recordURL := fmt.Sprintf(linodeEndpoint+"/%d/records/%d", domainID, record.ID)
+	record.Target = ipAddr
+	record.TTLSec = linode.TTL
+	if dryRun(domain, http.MethodPut, recordURL, record) {
+		return
+	}
+
+	var result LinodeRecord
+	err := linode.request(ctx, http.MethodPut, recordURL, record, &result)
+	if err != nil {
+		util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+
+	util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+	domain.UpdateStatus = config.UpdatedSuccess
+}
+
+// request 统一请求接口
+func (linode *Linode) request(ctx context.Context, method, url string, body interface{}, result interface{}) error {
+	req, err := util.NewJSONRequest(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	return linode.do(ctx, req, result)
+}
+
+func (linode *Linode) do(ctx context.Context, req *http.Request, result interface{}) error {
+	req.Header.Set("Authorization", "Bearer "+linode.DNS.Secret)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := linode.client.Do(req)
+	return util.GetHTTPResponse(resp, err, result)
+}
@@ -1,6 +1,9 @@
 package dns
 
 import (
+	"bytes"
+	"context"
+	"encoding/xml"
 	"io"
 	"net/http"
 	"strings"
@@ -19,6 +22,7 @@ type NameCheap struct {
 	Domains  config.Domains
 	lastIpv4 string
 	lastIpv6 string
+	client   *http.Client
 }
 
 // NameCheap 修改域名解析结果
@@ -27,6 +31,10 @@ type NameCheapResp struct {
 	Errors []string
 }
 
+func init() {
+	RegisterDNS("namecheap", func() DNS { return &NameCheap{} })
+}
+
 // Init 初始化
 func (nc *NameCheap) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
 	nc.Domains.Ipv4Cache = ipv4cache
@@ -36,16 +44,17 @@ func (nc *NameCheap) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ip
 
 	nc.DNS = dnsConf.DNS
 	nc.Domains.GetNewIp(dnsConf)
+	nc.client = util.CreateHTTPClientTimeout(dnsConf.GetHTTPTimeout())
 }
 
 // AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
-func (nc *NameCheap) AddUpdateDomainRecords() config.Domains {
-	nc.addUpdateDomainRecords("A")
-	nc.addUpdateDomainRecords("AAAA")
+func (nc *NameCheap) AddUpdateDomainRecords(ctx context.Context) config.Domains {
+	nc.addUpdateDomainRecords(ctx, "A")
+	nc.addUpdateDomainRecords(ctx, "AAAA")
 	return nc.Domains
 }
 
-func (nc *NameCheap) addUpdateDomainRecords(recordType string) {
+func (nc *NameCheap) addUpdateDomainRecords(ctx context.Context, recordType string) {
 	ipAddr, domains := nc.Domains.GetNewIpResult(recordType)
 
 	if ipAddr == "" {
@@ -55,27 +64,35 @@ func (nc *NameCheap) addUpdateDomainRecords(recordType string) {
 	// 防止多次发送Webhook通知
 	if recordType == "A" {
 		if nc.lastIpv4 == ipAddr {
-			util.Log("你的IPv4未变化, 未触发 %s 请求", "NameCheap")
+			util.LogDebug("你的IPv4未变化, 未触发 %s 请求", "NameCheap")
 			return
 		}
 	} else {
 		// https://www.namecheap.com/support/knowledgebase/article.aspx/29/11/how-to-dynamically-update-the-hosts-ip-with-an-http-request/
-		util.Log("Namecheap 不支持更新 IPv6")
+		util.LogWarn("Namecheap 不支持更新 IPv6")
 		return
 	}
 
 	for _, domain := range domains {
-		nc.modify(domain, ipAddr)
+		nc.modify(ctx, domain, ipAddr)
 	}
 }
 
 // 修改
-func (nc *NameCheap) modify(domain *config.Domain, ipAddr string) {
+func (nc *NameCheap) modify(ctx context.Context, domain *config.Domain, ipAddr string) {
+	if dryRun(domain, http.MethodGet, strings.NewReplacer(
+		"#{host}", domain.GetSubDomain(),
+		"#{domain}", domain.DomainName,
+		"#{ip}", ipAddr,
+	).Replace(nameCheapEndpoint), nil) {
+		return
+	}
+
 	var result NameCheapResp
-	err := nc.request(&result, ipAddr, domain)
+	err := nc.request(ctx, &result, ipAddr, domain)
 
 	if err != nil {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
@@ -85,13 +102,13 @@ func (nc *NameCheap) modify(domain *config.Domain, ipAddr string) {
 		util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	default:
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, result.Status)
+		util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, result.Status)
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
 
 // request 统一请求接口
-func (nc *NameCheap) request(result *NameCheapResp, ipAddr string, domain *config.Domain) (err error) {
+func (nc *NameCheap) request(ctx context.Context, result *NameCheapResp, ipAddr string, domain *config.Domain) (err error) {
 	url := strings.NewReplacer(
 		"#{host}", domain.GetSubDomain(),
 		"#{domain}", domain.DomainName,
@@ -99,7 +116,8 @@ func (nc *NameCheap) request(result *NameCheapResp, ipAddr string, domain *confi
 		"#{ip}", ipAddr,
 	).Replace(nameCheapEndpoint)
 
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodGet,
 		url,
 		http.NoBody,
@@ -109,8 +127,7 @@ func (nc *NameCheap) request(result *NameCheapResp, ipAddr string, domain *confi
 		return
 	}
 
-	client := util.CreateHTTPClient()
-	resp, err := client.Do(req)
+	resp, err := nc.client.Do(req)
 	if err != nil {
 		return
 	}
@@ -121,9 +138,9 @@ func (nc *NameCheap) request(result *NameCheapResp, ipAddr string, domain *confi
 		return err
 	}
 
-	status := string(data)
-
-	if strings.Contains(status, "<ErrCount>0</ErrCount>") {
+	status, errs := parseNameCheapResp(data)
+	result.Errors = errs
+	if status == "" {
 		result.Status = "Success"
 	} else {
 		result.Status = status
@@ -131,3 +148,37 @@ func (nc *NameCheap) request(result *NameCheapResp, ipAddr string, domain *confi
 
 	return
 }
+
+// nameCheapXMLResp 返回的XML, 错误信息的节点名为 Err1、Err2...
+type nameCheapXMLResp struct {
+	ErrCount int `xml:"ErrCount"`
+	Errors   struct {
+		Err []struct {
+			Text string `xml:",chardata"`
+		} `xml:",any"`
+	} `xml:"errors"`
+}
+
+// parseNameCheapResp 解析返回结果, 成功时status为空
+func parseNameCheapResp(data []byte) (status string, errs []string) {
+	var resp nameCheapXMLResp
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	// 声明为utf-16, 实际内容为utf-8
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	if err := decoder.Decode(&resp); err != nil {
+		return string(data), nil
+	}
+	if resp.ErrCount == 0 {
+		return "", nil
+	}
+
+	for _, e := range resp.Errors.Err {
+		errs = append(errs, strings.TrimSpace(e.Text))
+	}
+	if len(errs) == 0 {
+		return string(data), nil
+	}
+	return strings.Join(errs, ", "), errs
+}
@@ -1,6 +1,7 @@
 package dns
 
 import (
+	"context"
 	"encoding/xml"
 	"io"
 	"net/http"
@@ -22,6 +23,7 @@ type NameSilo struct {
 	Domains  config.Domains
 	lastIpv4 string
 	lastIpv6 string
+	client   *http.Client
 }
 
 // NameSiloResp 修改域名解析结果
@@ -63,6 +65,10 @@ type ResourceRecord struct {
 	Distance int    `xml:"distance"`
 }
 
+func init() {
+	RegisterDNS("namesilo", func() DNS { return &NameSilo{} })
+}
+
 // Init 初始化
 func (ns *NameSilo) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
 	ns.Domains.Ipv4Cache = ipv4cache
@@ -72,16 +78,17 @@ func (ns *NameSilo) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv
 
 	ns.DNS = dnsConf.DNS
 	ns.Domains.GetNewIp(dnsConf)
+	ns.client = util.CreateHTTPClientTimeout(dnsConf.GetHTTPTimeout())
 }
 
 // AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
-func (ns *NameSilo) AddUpdateDomainRecords() config.Domains {
-	ns.addUpdateDomainRecords("A")
-	ns.addUpdateDomainRecords("AAAA")
+func (ns *NameSilo) AddUpdateDomainRecords(ctx context.Context) config.Domains {
+	ns.addUpdateDomainRecords(ctx, "A")
+	ns.addUpdateDomainRecords(ctx, "AAAA")
 	return ns.Domains
 }
 
-func (ns *NameSilo) addUpdateDomainRecords(recordType string) {
+func (ns *NameSilo) addUpdateDomainRecords(ctx context.Context, recordType string) {
 	ipAddr, domains := ns.Domains.GetNewIpResult(recordType)
 
 	if ipAddr == "" {
@@ -94,9 +101,9 @@ func (ns *NameSilo) addUpdateDomainRecords(recordType string) {
 			domain.SubDomain = ""
 		}
 		// 拿到DNS记录列表，从列表中去取对应域名的id，有id进行修改，没ID进行新增
-		records, err := ns.listRecords(domain)
+		records, err := ns.listRecords(ctx, domain)
 		if err != nil {
-			util.Log("查询域名信息发生异常! %s", err)
+			util.LogError("查询域名信息发生异常! %s", err)
 			domain.UpdateStatus = config.UpdatedFailed
 			return
 		}
@@ -109,44 +116,61 @@ func (ns *NameSilo) addUpdateDomainRecords(recordType string) {
 		} else {
 			recordID = record.RecordID
 			if record.Value == ipAddr {
-				util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+				util.LogDebug("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+				domain.UpdateStatus = config.UpdatedNothing
 				return
 			}
 		}
-		ns.modify(domain, recordID, recordType, ipAddr, isAdd)
+		ns.modify(ctx, domain, recordID, recordType, ipAddr, isAdd)
 	}
 }
 
 // 修改
-func (ns *NameSilo) modify(domain *config.Domain, recordID, recordType, ipAddr string, isAdd bool) {
+func (ns *NameSilo) modify(ctx context.Context, domain *config.Domain, recordID, recordType, ipAddr string, isAdd bool) {
+	if isAdd && skipCreate(domain) {
+		return
+	}
 	var err error
 	var result string
 	var requestType string
+	endpoint := nameSiloUpdateRecordEndpoint
+	if isAdd {
+		endpoint = nameSiloAddRecordEndpoint
+	}
+	if dryRun(domain, http.MethodGet, strings.NewReplacer(
+		"#{host}", domain.SubDomain,
+		"#{domain}", domain.DomainName,
+		"#{recordID}", recordID,
+		"#{recordType}", recordType,
+		"#{ip}", ipAddr,
+	).Replace(endpoint), nil) {
+		return
+	}
 	if isAdd {
 		requestType = "新增"
-		result, err = ns.request(ipAddr, domain, "", recordType, nameSiloAddRecordEndpoint)
+		result, err = ns.request(ctx, ipAddr, domain, "", recordType, nameSiloAddRecordEndpoint)
 	} else {
 		requestType = "更新"
-		result, err = ns.request(ipAddr, domain, recordID, "", nameSiloUpdateRecordEndpoint)
+		result, err = ns.request(ctx, ipAddr, domain, recordID, "", nameSiloUpdateRecordEndpoint)
 	}
 	if err != nil {
-		util.Log("异常信息: %s", err)
+		util.LogError("异常信息: %s", err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
 	var resp NameSiloResp
 	xml.Unmarshal([]byte(result), &resp)
 	if resp.Reply.Code == 300 {
-		util.Log(requestType+"域名解析 %s 成功! IP: %s\n", domain, ipAddr)
+		util.Log(requestType+"域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log(requestType+"域名解析 %s 失败! 异常信息: %s", domain, resp.Reply.Detail)
+		util.LogError(requestType+"域名解析 %s 失败! 异常信息: %s", domain, resp.Reply.Detail)
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
 
-func (ns *NameSilo) listRecords(domain *config.Domain) (*NameSiloDNSListRecordResp, error) {
-	result, err := ns.request("", domain, "", "", nameSiloListRecordEndpoint)
+func (ns *NameSilo) listRecords(ctx context.Context, domain *config.Domain) (*NameSiloDNSListRecordResp, error) {
+	result, err := ns.request(ctx, "", domain, "", "", nameSiloListRecordEndpoint)
 	if err != nil {
 		return nil, err
 	}
@@ -160,7 +184,7 @@ func (ns *NameSilo) listRecords(domain *config.Domain) (*NameSiloDNSListRecordRe
 }
 
 // request 统一请求接口
-func (ns *NameSilo) request(ipAddr string, domain *config.Domain, recordID, recordType, url string) (result string, err error) {
+func (ns *NameSilo) request(ctx context.Context, ipAddr string, domain *config.Domain, recordID, recordType, url string) (result string, err error) {
 	url = strings.NewReplacer(
 		"#{host}", domain.SubDomain,
 		"#{domain}", domain.DomainName,
@@ -169,7 +193,8 @@ func (ns *NameSilo) request(ipAddr string, domain *config.Domain, recordID, reco
 		"#{recordType}", recordType,
 		"#{ip}", ipAddr,
 	).Replace(url)
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodGet,
 		url,
 		http.NoBody,
@@ -179,8 +204,7 @@ func (ns *NameSilo) request(ipAddr string, domain *config.Domain, recordID, reco
 		return
 	}
 
-	client := util.CreateHTTPClient()
-	resp, err := client.Do(req)
+	resp, err := ns.client.Do(req)
 	if err != nil {
 		return
 	}
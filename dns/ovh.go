@@ -0,0 +1,243 @@
+package dns
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+const (
+	ovhEndpoint = "https://eu.api.ovh.com/1.0"
+)
+
+// ovhEndpoints 可直接填写的区域别名
+var ovhEndpoints = map[string]string{
+	"ovh-eu": ovhEndpoint,
+	"ovh-ca": "https://ca.api.ovh.com/1.0",
+	"ovh-us": "https://api.us.ovhcloud.com/1.0",
+}
+
+// OVH OVH实现
+// https://eu.api.ovh.com/console/#/domain/zone
+type OVH struct {
+	DNS      config.DNS
+	Domains  config.Domains
+	TTL      int
+	endpoint string
+	// 服务器时间与本地时间的差值
+	timeDelta *int64
+	client    *http.Client
+}
+
+// OVHRecord 记录实体
+type OVHRecord struct {
+	ID        int64  `json:"id,omitempty"`
+	FieldType string `json:"fieldType,omitempty"`
+	SubDomain string `json:"subDomain"`
+	Target    string `json:"target"`
+	TTL       int    `json:"ttl"`
+}
+
+func init() {
+	RegisterDNS("ovh", func() DNS { return &OVH{} })
+}
+
+// Init 初始化
+func (ovh *OVH) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
+	ovh.Domains.Ipv4Cache = ipv4cache
+	ovh.Domains.Ipv6Cache = ipv6cache
+	ovh.DNS = dnsConf.DNS
+	ovh.Domains.GetNewIp(dnsConf)
+	ovh.client = util.CreateHTTPClientTimeout(dnsConf.GetHTTPTimeout())
+
+	ovh.endpoint = strings.TrimSuffix(dnsConf.DNS.Endpoint, "/")
+	if alias, ok := ovhEndpoints[ovh.endpoint]; ok {
+		ovh.endpoint = alias
+	} else if ovh.endpoint == "" {
+		ovh.endpoint = ovhEndpoint
+	}
+
+	if dnsConf.TTL == "" {
+		// 默认600s
+		ovh.TTL = 600
+	} else {
+		ttl, err := strconv.Atoi(dnsConf.TTL)
+		if err != nil {
+			ovh.TTL = 600
+		} else {
+			ovh.TTL = ttl
+		}
+	}
+}
+
+// AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
+func (ovh *OVH) AddUpdateDomainRecords(ctx context.Context) config.Domains {
+	ovh.addUpdateDomainRecords(ctx, "A")
+	ovh.addUpdateDomainRecords(ctx, "AAAA")
+	return ovh.Domains
+}
+
+func (ovh *OVH) addUpdateDomainRecords(ctx context.Context, recordType string) {
+	ipAddr, domains := ovh.Domains.GetNewIpResult(recordType)
+
+	if ipAddr == "" {
+		return
+	}
+
+	for _, domain := range domains {
+		zoneURL := ovh.endpoint + "/domain/zone/" + url.PathEscape(domain.DomainName)
+
+		params := url.Values{}
+		params.Set("fieldType", recordType)
+		params.Set("subDomain", domain.SubDomain)
+		var ids []int64
+		err := ovh.request(ctx, http.MethodGet, zoneURL+"/record?"+params.Encode(), nil, &ids)
+		if err != nil {
+			util.LogError("查询域名信息发生异常! %s", err)
+			domain.UpdateStatus = config.UpdatedFailed
+			continue
+		}
+
+		if len(ids) > 0 {
+			var record OVHRecord
+			err = ovh.request(ctx, http.MethodGet, fmt.Sprintf("%s/record/%d", zoneURL, ids[0]), nil, &record)
+			if err != nil {
+				util.LogError("查询域名信息发生异常! %s", err)
+				domain.UpdateStatus = config.UpdatedFailed
+				continue
+			}
+			ovh.modify(ctx, zoneURL, record, domain, ipAddr)
+		} else {
+			ovh.create(ctx, zoneURL, domain, recordType, ipAddr)
+		}
+	}
+}
+
+// create 创建
+func (ovh *OVH) create(ctx context.Context, zoneURL string, domain *config.Domain, recordType string, ipAddr string) {
+	if skipCreate(domain) {
+		return
+	}
+	record := OVHRecord{
+		FieldType: recordType,
+		SubDomain: domain.SubDomain,
+		Target:    ipAddr,
+		TTL:       ovh.TTL,
+	}
+	if dryRun(domain, http.MethodPost, zoneURL+"/record", record) {
+		return
+	}
+
+	var result OVHRecord
+	err := ovh.request(ctx, http.MethodPost, zoneURL+"/record", record, &result)
+	if err == nil {
+		err = ovh.refresh(ctx, zoneURL)
+	}
+	if err != nil {
+		util.LogError("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+
+	util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
+	domain.UpdateStatus = config.UpdatedSuccess
+}
+
+// modify 修改
+func (ovh *OVH) modify(ctx context.Context, zoneURL string, record OVHRecord, domain *config.Domain, ipAddr string) {
+	// 相同不修改
+	if record.Target == ipAddr && record.TTL == ovh.TTL {
+		util.LogDebug("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		domain.UpdateStatus = config.UpdatedNothing
+		return
+	}
+
+	recordURL := fmt.Sprintf("%s/record/%d", zoneURL, record.ID)
+	// 修改时不能提交 id 和 fieldType
+	body := OVHRecord{
+		SubDomain: record.SubDomain,
+		Target:    ipAddr,
+		TTL:       ovh.TTL,
+	}
+	if dryRun(domain, http.MethodPut, recordURL, body) {
+		return
+	}
+
+	err := ovh.request(ctx, http.MethodPut, recordURL, body, nil)
+	if err == nil {
+		err = ovh.refresh(ctx, zoneURL)
+	}
+	if err != nil {
+		util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+
+	util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+	domain.UpdateStatus = config.UpdatedSuccess
+}
+
+// refresh 修改记录后需刷新区域才会生效
+func (ovh *OVH) refresh(ctx context.Context, zoneURL string) error {
+	return ovh.request(ctx, http.MethodPost, zoneURL+"/refresh", nil, nil)
+}
+
+// getTimestamp 获得服务器时间, 每次运行只请求一次 /auth/time
+func (ovh *OVH) getTimestamp(ctx context.Context) (int64, error) {
+	if ovh.timeDelta == nil {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, ovh.endpoint+"/auth/time", http.NoBody)
+		if err != nil {
+			return 0, err
+		}
+
+		var serverTime int64
+		resp, err := ovh.client.Do(req)
+		err = util.GetHTTPResponse(resp, err, &serverTime)
+		if err != nil {
+			return 0, err
+		}
+		delta := serverTime - time.Now().Unix()
+		ovh.timeDelta = &delta
+	}
+	return time.Now().Unix() + *ovh.timeDelta, nil
+}
+
+// request 统一请求接口, result 为nil时忽略返回内容
+func (ovh *OVH) request(ctx context.Context, method, url string, data interface{}, result interface{}) error {
+	timestamp, err := ovh.getTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	// 签名需要和发送的请求体完全一致
+	var body []byte
+	if data != nil {
+		body, err = json.Marshal(data)
+		if err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	util.OvhSigner(ovh.DNS.ID, ovh.DNS.Secret, ovh.DNS.ConsumerKey, req, string(body), timestamp)
+
+	resp, err := ovh.client.Do(req)
+	respBody, err := util.GetHTTPResponseOrg(resp, err)
+	if err != nil || result == nil {
+		return err
+	}
+	return json.Unmarshal(respBody, result)
+}
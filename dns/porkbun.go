@@ -2,9 +2,13 @@ package dns
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/jeessy2/ddns-go/v6/config"
 	"github.com/jeessy2/ddns-go/v6/util"
@@ -12,14 +16,19 @@ import (
 
 const (
 	porkbunEndpoint string = "https://porkbun.com/api/json/v3/dns"
+	// Porkbun 允许的最小TTL
+	porkbunMinTTL = 600
 )
 
 type Porkbun struct {
-	DNSConfig config.DNS
-	Domains   config.Domains
-	TTL       string
+	DNSConfig       config.DNS
+	Domains         config.Domains
+	TTL             string
+	CleanDuplicates bool
+	client          *http.Client
 }
 type PorkbunDomainRecord struct {
+	ID      *string `json:"id,omitempty"`
 	Name    *string `json:"name"`    // subdomain
 	Type    *string `json:"type"`    // record type, e.g. A AAAA CNAME
 	Content *string `json:"content"` // value
@@ -45,38 +54,48 @@ type PorkbunDomainCreateOrUpdateVO struct {
 	*PorkbunDomainRecord
 }
 
+func init() {
+	RegisterDNS("porkbun", func() DNS { return &Porkbun{} })
+}
+
 // Init 初始化
 func (pb *Porkbun) Init(conf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
 	pb.Domains.Ipv4Cache = ipv4cache
 	pb.Domains.Ipv6Cache = ipv6cache
 	pb.DNSConfig = conf.DNS
+	pb.CleanDuplicates = conf.CleanDuplicates
 	pb.Domains.GetNewIp(conf)
+	pb.client = util.CreateHTTPClientTimeout(conf.GetHTTPTimeout())
 	if conf.TTL == "" {
 		// 默认600s
 		pb.TTL = "600"
 	} else {
 		pb.TTL = conf.TTL
 	}
+	if ttl, err := strconv.Atoi(pb.TTL); err == nil {
+		pb.TTL = strconv.Itoa(checkMinTTL("Porkbun", ttl, porkbunMinTTL))
+	}
 }
 
 // AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
-func (pb *Porkbun) AddUpdateDomainRecords() config.Domains {
-	pb.addUpdateDomainRecords("A")
-	pb.addUpdateDomainRecords("AAAA")
+func (pb *Porkbun) AddUpdateDomainRecords(ctx context.Context) config.Domains {
+	pb.addUpdateDomainRecords(ctx, "A")
+	pb.addUpdateDomainRecords(ctx, "AAAA")
 	return pb.Domains
 }
 
-func (pb *Porkbun) addUpdateDomainRecords(recordType string) {
+func (pb *Porkbun) addUpdateDomainRecords(ctx context.Context, recordType string) {
 	ipAddr, domains := pb.Domains.GetNewIpResult(recordType)
 
 	if ipAddr == "" {
 		return
 	}
 
-	for _, domain := range domains {
+	forEachDomain(domains, func(domain *config.Domain) {
 		var record PorkbunDomainQueryResponse
 		// 获取当前域名信息
 		err := pb.request(
+			ctx,
 			porkbunEndpoint+fmt.Sprintf("/retrieveByNameType/%s/%s/%s", domain.DomainName, recordType, domain.SubDomain),
 			&PorkbunApiKey{
 				AccessKey: pb.DNSConfig.ID,
@@ -86,30 +105,47 @@ func (pb *Porkbun) addUpdateDomainRecords(recordType string) {
 		)
 
 		if err != nil {
-			util.Log("查询域名信息发生异常! %s", err)
+			util.LogError("查询域名信息发生异常! %s", err)
 			domain.UpdateStatus = config.UpdatedFailed
 			return
 		}
 		if record.Status == "SUCCESS" {
 			if len(record.Records) > 0 {
 				// 存在，更新
-				pb.modify(&record, domain, recordType, ipAddr)
+				pb.modify(ctx, &record, domain, recordType, ipAddr)
+				// 开启后清理多余的相同解析记录
+				if pb.CleanDuplicates {
+					pb.cleanDuplicateRecords(ctx, record.Records[1:], domain)
+				}
 			} else {
 				// 不存在，创建
-				pb.create(domain, recordType, ipAddr)
+				pb.create(ctx, domain, recordType, ipAddr)
 			}
 		} else {
-			util.Log("在DNS服务商中未找到根域名: %s", domain.DomainName)
+			util.LogError("在DNS服务商中未找到根域名: %s", domain.DomainName)
 			domain.UpdateStatus = config.UpdatedFailed
 		}
-	}
+	})
 }
 
 // 创建
-func (pb *Porkbun) create(domain *config.Domain, recordType string, ipAddr string) {
+func (pb *Porkbun) create(ctx context.Context, domain *config.Domain, recordType string, ipAddr string) {
+	if skipCreate(domain) {
+		return
+	}
+	if dryRun(domain, "POST", porkbunEndpoint+fmt.Sprintf("/create/%s", domain.DomainName), &PorkbunDomainRecord{
+		Name:    &domain.SubDomain,
+		Type:    &recordType,
+		Content: &ipAddr,
+		Ttl:     &pb.TTL,
+	}) {
+		return
+	}
+
 	var response PorkbunResponse
 
 	err := pb.request(
+		ctx,
 		porkbunEndpoint+fmt.Sprintf("/create/%s", domain.DomainName),
 		&PorkbunDomainCreateOrUpdateVO{
 			PorkbunApiKey: &PorkbunApiKey{
@@ -127,7 +163,7 @@ func (pb *Porkbun) create(domain *config.Domain, recordType string, ipAddr strin
 	)
 
 	if err != nil {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogError("新增域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
@@ -136,39 +172,53 @@ func (pb *Porkbun) create(domain *config.Domain, recordType string, ipAddr strin
 		util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, response.Status)
+		util.LogError("新增域名解析 %s 失败! 异常信息: %s", domain, response.Status)
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
 
 // 修改
-func (pb *Porkbun) modify(record *PorkbunDomainQueryResponse, domain *config.Domain, recordType string, ipAddr string) {
+func (pb *Porkbun) modify(ctx context.Context, record *PorkbunDomainQueryResponse, domain *config.Domain, recordType string, ipAddr string) {
 
 	// 相同不修改
 	if len(record.Records) > 0 && *record.Records[0].Content == ipAddr {
-		util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		util.LogDebug("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		domain.UpdateStatus = config.UpdatedNothing
+		return
+	}
+
+	// 按记录ID修改, 不影响同名的其它记录
+	editURL := porkbunEndpoint + fmt.Sprintf("/editByNameType/%s/%s/%s", domain.DomainName, recordType, domain.SubDomain)
+	if record.Records[0].ID != nil {
+		editURL = porkbunEndpoint + fmt.Sprintf("/edit/%s/%s", domain.DomainName, *record.Records[0].ID)
+	}
+	editRecord := &PorkbunDomainRecord{
+		Name:    &domain.SubDomain,
+		Type:    &recordType,
+		Content: &ipAddr,
+		Ttl:     &pb.TTL,
+	}
+	if dryRun(domain, "POST", editURL, editRecord) {
 		return
 	}
 
 	var response PorkbunResponse
 
 	err := pb.request(
-		porkbunEndpoint+fmt.Sprintf("/editByNameType/%s/%s/%s", domain.DomainName, recordType, domain.SubDomain),
+		ctx,
+		editURL,
 		&PorkbunDomainCreateOrUpdateVO{
 			PorkbunApiKey: &PorkbunApiKey{
 				AccessKey: pb.DNSConfig.ID,
 				SecretKey: pb.DNSConfig.Secret,
 			},
-			PorkbunDomainRecord: &PorkbunDomainRecord{
-				Content: &ipAddr,
-				Ttl:     &pb.TTL,
-			},
+			PorkbunDomainRecord: editRecord,
 		},
 		&response,
 	)
 
 	if err != nil {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
@@ -177,18 +227,65 @@ func (pb *Porkbun) modify(record *PorkbunDomainQueryResponse, domain *config.Dom
 		util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, response.Status)
+		util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, response.Status)
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
 
+// cleanDuplicateRecords 删除多余的相同解析记录
+func (pb *Porkbun) cleanDuplicateRecords(ctx context.Context, records []PorkbunDomainRecord, domain *config.Domain) {
+	for _, record := range records {
+		if record.ID == nil {
+			continue
+		}
+		deleteURL := porkbunEndpoint + fmt.Sprintf("/delete/%s/%s", domain.DomainName, *record.ID)
+		if dryRun(domain, "POST", deleteURL, nil) {
+			continue
+		}
+
+		var response PorkbunResponse
+		err := pb.request(
+			ctx,
+			deleteURL,
+			&PorkbunApiKey{
+				AccessKey: pb.DNSConfig.ID,
+				SecretKey: pb.DNSConfig.Secret,
+			},
+			&response,
+		)
+		if err != nil {
+			util.LogError("删除多余域名解析 %s 失败! 异常信息: %s", domain, err)
+		} else if response.Status != "SUCCESS" {
+			util.LogError("删除多余域名解析 %s 失败! 异常信息: %s", domain, response.Status)
+		} else {
+			util.Log("删除多余域名解析 %s 成功!", domain)
+		}
+	}
+}
+
+// Check 调用ping接口, 检查API Key是否正确
+func (pb *Porkbun) Check(ctx context.Context) error {
+	var result PorkbunResponse
+	err := pb.request(
+		ctx,
+		strings.TrimSuffix(porkbunEndpoint, "/dns")+"/ping",
+		&PorkbunApiKey{AccessKey: pb.DNSConfig.ID, SecretKey: pb.DNSConfig.Secret},
+		&result,
+	)
+	if err == nil && result.Status != "SUCCESS" {
+		err = errors.New(result.Status)
+	}
+	return err
+}
+
 // request 统一请求接口
-func (pb *Porkbun) request(url string, data interface{}, result interface{}) (err error) {
+func (pb *Porkbun) request(ctx context.Context, url string, data interface{}, result interface{}) (err error) {
 	jsonStr := make([]byte, 0)
 	if data != nil {
 		jsonStr, _ = json.Marshal(data)
 	}
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"POST",
 		url,
 		bytes.NewBuffer(jsonStr),
@@ -198,8 +295,7 @@ func (pb *Porkbun) request(url string, data interface{}, result interface{}) (er
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	client := util.CreateHTTPClient()
-	resp, err := client.Do(req)
+	resp, err := pb.client.Do(req)
 	err = util.GetHTTPResponse(resp, err, result)
 
 	return
@@ -0,0 +1,239 @@
+package dns
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+const (
+	route53Endpoint = "https://route53.amazonaws.com/2013-04-01"
+	// Route53 为全局服务, 固定使用 us-east-1 签名
+	route53Region = "us-east-1"
+)
+
+// Route53 AWS Route53实现
+// https://docs.aws.amazon.com/Route53/latest/APIReference/Welcome.html
+type Route53 struct {
+	DNS     config.DNS
+	Domains config.Domains
+	TTL     int
+	client  *http.Client
+}
+
+// Route53HostedZonesResp ListHostedZonesByName返回结果
+type Route53HostedZonesResp struct {
+	HostedZones []struct {
+		ID   string `xml:"Id"`
+		Name string `xml:"Name"`
+	} `xml:"HostedZones>HostedZone"`
+}
+
+// Route53RecordSet 记录集
+type Route53RecordSet struct {
+	Name   string   `xml:"Name"`
+	Type   string   `xml:"Type"`
+	TTL    int      `xml:"TTL"`
+	Values []string `xml:"ResourceRecords>ResourceRecord>Value"`
+}
+
+// Route53RecordSetsResp ListResourceRecordSets返回结果
+type Route53RecordSetsResp struct {
+	ResourceRecordSets []Route53RecordSet `xml:"ResourceRecordSets>ResourceRecordSet"`
+}
+
+// Route53Change 记录集变更
+type Route53Change struct {
+	Action            string           `xml:"Action"`
+	ResourceRecordSet Route53RecordSet `xml:"ResourceRecordSet"`
+}
+
+// Route53ChangeRequest ChangeResourceRecordSets请求
+type Route53ChangeRequest struct {
+	XMLName xml.Name        `xml:"https://route53.amazonaws.com/doc/2013-04-01/ ChangeResourceRecordSetsRequest"`
+	Changes []Route53Change `xml:"ChangeBatch>Changes>Change"`
+}
+
+func init() {
+	RegisterDNS("route53", func() DNS { return &Route53{} })
+}
+
+// Init 初始化
+func (r53 *Route53) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
+	r53.Domains.Ipv4Cache = ipv4cache
+	r53.Domains.Ipv6Cache = ipv6cache
+	r53.DNS = dnsConf.DNS
+	r53.Domains.GetNewIp(dnsConf)
+	r53.client = util.CreateHTTPClientTimeout(dnsConf.GetHTTPTimeout())
+	if dnsConf.TTL == "" {
+		// 默认300s
+		r53.TTL = 300
+	} else {
+		ttl, err := strconv.Atoi(dnsConf.TTL)
+		if err != nil {
+			r53.TTL = 300
+		} else {
+			r53.TTL = ttl
+		}
+	}
+}
+
+// AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
+func (r53 *Route53) AddUpdateDomainRecords(ctx context.Context) config.Domains {
+	r53.addUpdateDomainRecords(ctx, "A")
+	r53.addUpdateDomainRecords(ctx, "AAAA")
+	return r53.Domains
+}
+
+func (r53 *Route53) addUpdateDomainRecords(ctx context.Context, recordType string) {
+	ipAddr, domains := r53.Domains.GetNewIpResult(recordType)
+
+	if ipAddr == "" {
+		return
+	}
+
+	forEachDomain(domains, func(domain *config.Domain) {
+		zoneID, err := r53.getZoneID(ctx, domain)
+		if err != nil {
+			util.LogError("查询域名信息发生异常! %s", err)
+			domain.UpdateStatus = config.UpdatedFailed
+			return
+		}
+		if zoneID == "" {
+			util.LogError("在DNS服务商中未找到根域名: %s", domain.DomainName)
+			domain.UpdateStatus = config.UpdatedFailed
+			return
+		}
+
+		// 相同不修改, 查询失败时直接UPSERT
+		recordSet, err := r53.getRecordSet(ctx, zoneID, domain, recordType)
+		if err == nil && recordSet != nil && len(recordSet.Values) == 1 &&
+			recordSet.Values[0] == ipAddr && recordSet.TTL == r53.TTL {
+			util.LogDebug("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+			domain.UpdateStatus = config.UpdatedNothing
+			return
+		}
+		// 只更新不新增时需确定记录已存在
+		if recordSet == nil && domain.UpdateOnly {
+			if err != nil {
+				util.LogError("查询域名信息发生异常! %s", err)
+				domain.UpdateStatus = config.UpdatedFailed
+			} else {
+				skipCreate(domain)
+			}
+			return
+		}
+
+		r53.upsert(ctx, zoneID, domain, recordType, ipAddr)
+	})
+}
+
+// getZoneID 获得托管区域ID, 已配置 Zone ID 时直接使用
+func (r53 *Route53) getZoneID(ctx context.Context, domain *config.Domain) (string, error) {
+	if r53.DNS.ZoneID != "" {
+		return strings.TrimPrefix(r53.DNS.ZoneID, "/hostedzone/"), nil
+	}
+
+	params := url.Values{}
+	params.Set("dnsname", domain.DomainName)
+	params.Set("maxitems", "1")
+
+	var result Route53HostedZonesResp
+	err := r53.request(ctx, http.MethodGet, route53Endpoint+"/hostedzonesbyname?"+params.Encode(), nil, &result)
+	if err != nil {
+		return "", err
+	}
+
+	// 返回按名称排序的第一个区域, 可能不是当前域名
+	for _, zone := range result.HostedZones {
+		if strings.EqualFold(strings.TrimSuffix(zone.Name, "."), domain.DomainName) {
+			return strings.TrimPrefix(zone.ID, "/hostedzone/"), nil
+		}
+	}
+	return "", nil
+}
+
+// getRecordSet 获得名称和类型相同的记录集, 不存在时返回nil
+func (r53 *Route53) getRecordSet(ctx context.Context, zoneID string, domain *config.Domain, recordType string) (*Route53RecordSet, error) {
+	params := url.Values{}
+	params.Set("name", domain.String()+".")
+	params.Set("type", recordType)
+	params.Set("maxitems", "1")
+
+	var result Route53RecordSetsResp
+	err := r53.request(ctx, http.MethodGet, fmt.Sprintf(route53Endpoint+"/hostedzone/%s/rrset?%s", zoneID, params.Encode()), nil, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, recordSet := range result.ResourceRecordSets {
+		if recordSet.Type == recordType && strings.EqualFold(strings.TrimSuffix(recordSet.Name, "."), domain.String()) {
+			return &recordSet, nil
+		}
+	}
+	return nil, nil
+}
+
+// upsert 不存在时新增, 存在时替换
+func (r53 *Route53) upsert(ctx context.Context, zoneID string, domain *config.Domain, recordType string, ipAddr string) {
+	changeURL := fmt.Sprintf(route53Endpoint+"/hostedzone/%s/rrset/", zoneID)
+
+	change := Route53ChangeRequest{
+		Changes: []Route53Change{{
+			Action: "UPSERT",
+			ResourceRecordSet: Route53RecordSet{
+				Name:   domain.String() + ".",
+				Type:   recordType,
+				TTL:    r53.TTL,
+				Values: []string{ipAddr},
+			},
+		}},
+	}
+
+	body, err := xml.Marshal(change)
+	if err != nil {
+		util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+	if dryRun(domain, http.MethodPost, changeURL, string(body)) {
+		return
+	}
+
+	err = r53.request(ctx, http.MethodPost, changeURL, body, nil)
+	if err != nil {
+		util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+
+	util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+	domain.UpdateStatus = config.UpdatedSuccess
+}
+
+// request 统一请求接口, 返回XML
+func (r53 *Route53) request(ctx context.Context, method, url string, body []byte, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/xml")
+	}
+	util.AwsSigV4Signer(r53.DNS.ID, r53.DNS.Secret, route53Region, "route53", req, body)
+
+	resp, err := r53.client.Do(req)
+	data, err := util.GetHTTPResponseOrg(resp, err)
+	if err != nil || result == nil {
+		return err
+	}
+	return xml.Unmarshal(data, result)
+}
@@ -0,0 +1,40 @@
+package dns
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// 取消请求后等待更新结束的时间
+const cancelWait = 2 * time.Second
+
+// 正在退出, 不再开始新的更新
+var stopping atomic.Bool
+
+// Shutdown 等待正在进行的更新完成后返回, 之后不再开始新的更新
+// 超过 timeout 仍未完成时取消进行中的请求, 避免在查询后、修改前被结束
+func Shutdown(timeout time.Duration) {
+	stopping.Store(true)
+
+	done := make(chan struct{})
+	go func() {
+		runLock.Lock()
+		defer runLock.Unlock()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return
+	case <-time.After(timeout):
+	}
+
+	util.LogWarn("更新超过 %s 未完成, 已取消进行中的请求", timeout)
+	util.CancelRequests()
+	select {
+	case <-done:
+	case <-time.After(cancelWait):
+	}
+}
@@ -0,0 +1,49 @@
+package dns
+
+import (
+	"testing"
+	"time"
+)
+
+// TestShutdownWaitsForRun 测试退出时等待正在进行的更新完成, 之后不再运行
+func TestShutdownWaitsForRun(t *testing.T) {
+	defer stopping.Store(false)
+
+	runLock.Lock()
+	released := make(chan struct{})
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(released)
+		runLock.Unlock()
+	}()
+
+	Shutdown(5 * time.Second)
+	select {
+	case <-released:
+	default:
+		t.Fatal("Shutdown 未等待正在进行的更新")
+	}
+
+	if !stopping.Load() {
+		t.Error("退出后不应再开始新的更新")
+	}
+	if _, wait := run(time.Minute); wait != time.Minute {
+		t.Errorf("期待直接返回等待时间, 得到 %s", wait)
+	}
+}
+
+// TestRunNowSkipsWhenRunning 测试已有正在运行的更新时立即更新不会同时运行
+func TestRunNowSkipsWhenRunning(t *testing.T) {
+	runLock.Lock()
+	_, ok := RunNow()
+	runLock.Unlock()
+	if ok {
+		t.Error("已有正在运行的更新时不应再运行")
+	}
+
+	defer stopping.Store(false)
+	stopping.Store(true)
+	if _, ok := RunNow(); !ok {
+		t.Error("没有正在运行的更新时应运行")
+	}
+}
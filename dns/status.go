@@ -0,0 +1,131 @@
+package dns
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+)
+
+// DomainStatus 域名的当前状态
+type DomainStatus struct {
+	config.DomainResult
+	// Provider DNS服务商
+	Provider string `json:"provider"`
+	// LastUpdateTime 最后一次更新成功的时间, 未更新过为null
+	LastUpdateTime *time.Time `json:"lastUpdateTime"`
+	// LastCheckTime 最后一次运行的时间
+	LastCheckTime time.Time `json:"lastCheckTime"`
+}
+
+var (
+	// 每个配置的域名状态, 与 Ipcache 一一对应
+	statuses     = [][]DomainStatus{}
+	statusesLock sync.Mutex
+
+	// 每个配置开始连续全部更新失败的时间, 未失败为零值, 与 statuses 一一对应
+	failedSinces = []time.Time{}
+
+	// 每个配置是否已禁用, 健康检查时忽略, 与 statuses 一一对应
+	disabledStatuses = []bool{}
+
+	// HealthThreshold 所有配置连续全部更新失败超过此时间后健康检查不通过, 为0时不检查
+	HealthThreshold = time.Hour
+)
+
+// GetStatus 获得所有域名的当前状态
+func GetStatus() []DomainStatus {
+	statusesLock.Lock()
+	defer statusesLock.Unlock()
+	all := []DomainStatus{}
+	for _, list := range statuses {
+		all = append(all, list...)
+	}
+	return all
+}
+
+// resetStatus 配置改变时重置所有状态
+func resetStatus(num int) {
+	statusesLock.Lock()
+	defer statusesLock.Unlock()
+	statuses = make([][]DomainStatus, num)
+	failedSinces = make([]time.Time, num)
+	disabledStatuses = make([]bool, num)
+}
+
+// disableStatus 第 i 个配置已禁用, 清除其域名状态
+func disableStatus(i int) {
+	statusesLock.Lock()
+	defer statusesLock.Unlock()
+	if i >= len(statuses) {
+		return
+	}
+	statuses[i] = nil
+	failedSinces[i] = time.Time{}
+	disabledStatuses[i] = true
+}
+
+// Healthy 健康检查, 所有启用的配置都已连续全部更新失败超过 HealthThreshold 时返回false
+func Healthy() bool {
+	statusesLock.Lock()
+	defer statusesLock.Unlock()
+	if HealthThreshold <= 0 || len(failedSinces) == 0 {
+		return true
+	}
+
+	// 最后一个开始失败的配置失败的时间即为全部失败的时间
+	var allFailedSince time.Time
+	for i, failedSince := range failedSinces {
+		if disabledStatuses[i] {
+			continue
+		}
+		if failedSince.IsZero() {
+			return true
+		}
+		if failedSince.After(allFailedSince) {
+			allFailedSince = failedSince
+		}
+	}
+	return allFailedSince.IsZero() || time.Since(allFailedSince) <= HealthThreshold
+}
+
+// updateStatus 更新第 i 个配置的域名状态, 保留未成功更新的域名的最后更新时间
+func updateStatus(i int, provider string, domains *config.Domains) {
+	statusesLock.Lock()
+	defer statusesLock.Unlock()
+	if i >= len(statuses) {
+		return
+	}
+
+	lastUpdateTimes := map[[2]string]*time.Time{}
+	for _, status := range statuses[i] {
+		lastUpdateTimes[[2]string{status.Domain, status.RecordType}] = status.LastUpdateTime
+	}
+
+	now := time.Now()
+	list := []DomainStatus{}
+	results := config.GetDomainResults(domains)
+	// 有域名且全部更新失败
+	allFailed := len(results) > 0
+	for _, result := range results {
+		allFailed = allFailed && result.Status == "failed"
+		status := DomainStatus{
+			DomainResult:   result,
+			Provider:       provider,
+			LastUpdateTime: lastUpdateTimes[[2]string{result.Domain, result.RecordType}],
+			LastCheckTime:  now,
+		}
+		if result.Status == "success" {
+			status.LastUpdateTime = &now
+		}
+		list = append(list, status)
+	}
+	statuses[i] = list
+	disabledStatuses[i] = false
+
+	if !allFailed {
+		failedSinces[i] = time.Time{}
+	} else if failedSinces[i].IsZero() {
+		failedSinces[i] = now
+	}
+}
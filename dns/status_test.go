@@ -0,0 +1,83 @@
+package dns
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+)
+
+// TestUpdateStatus 测试未成功更新时保留最后更新时间
+func TestUpdateStatus(t *testing.T) {
+	resetStatus(1)
+	defer resetStatus(0)
+
+	domain := &config.Domain{DomainName: "example.com", SubDomain: "www", UpdateStatus: config.UpdatedSuccess}
+	domains := &config.Domains{Ipv4Addr: "1.2.3.4", Ipv4Domains: []*config.Domain{domain}}
+	updateStatus(0, "cloudflare", domains)
+
+	status := GetStatus()
+	if len(status) != 1 || status[0].Domain != "www.example.com" || status[0].NewIP != "1.2.3.4" ||
+		status[0].Provider != "cloudflare" || status[0].LastUpdateTime == nil {
+		t.Fatalf("状态不正确: %+v", status)
+	}
+	lastUpdateTime := *status[0].LastUpdateTime
+
+	domain.UpdateStatus = config.UpdatedNothing
+	updateStatus(0, "cloudflare", domains)
+	status = GetStatus()
+	if status[0].Status != "nothing" || status[0].LastUpdateTime == nil || !status[0].LastUpdateTime.Equal(lastUpdateTime) {
+		t.Errorf("最后更新时间应保留: %+v", status[0])
+	}
+
+	// 超出配置数量时忽略
+	updateStatus(1, "cloudflare", domains)
+	if len(GetStatus()) != 1 {
+		t.Error("不应添加状态")
+	}
+}
+
+// TestHealthy 测试所有配置连续全部更新失败超过阈值时健康检查不通过
+func TestHealthy(t *testing.T) {
+	resetStatus(2)
+	defer resetStatus(0)
+
+	failed := &config.Domains{Ipv4Domains: []*config.Domain{{DomainName: "example.com", UpdateStatus: config.UpdatedFailed}}}
+	nothing := &config.Domains{Ipv4Domains: []*config.Domain{{DomainName: "example.org", UpdateStatus: config.UpdatedNothing}}}
+
+	updateStatus(0, "cloudflare", failed)
+	updateStatus(1, "alidns", nothing)
+	failedSinces[0] = time.Now().Add(-2 * HealthThreshold)
+	if !Healthy() {
+		t.Error("还有配置未失败, 应健康")
+	}
+
+	updateStatus(1, "alidns", failed)
+	if !Healthy() {
+		t.Error("全部失败未超过阈值, 应健康")
+	}
+	failedSinces[1] = time.Now().Add(-2 * HealthThreshold)
+	if Healthy() {
+		t.Error("全部失败超过阈值, 应不健康")
+	}
+
+	// 再次失败不重置开始失败的时间
+	updateStatus(1, "alidns", failed)
+	if Healthy() {
+		t.Error("开始失败的时间不应重置")
+	}
+	updateStatus(1, "alidns", nothing)
+	if !Healthy() {
+		t.Error("恢复后应健康")
+	}
+
+	// 禁用的配置不参与健康检查
+	disableStatus(1)
+	if Healthy() {
+		t.Error("启用的配置全部失败超过阈值, 应不健康")
+	}
+	disableStatus(0)
+	if !Healthy() {
+		t.Error("全部禁用时应健康")
+	}
+}
@@ -2,6 +2,7 @@ package dns
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"strconv"
@@ -21,6 +22,7 @@ type TencentCloud struct {
 	DNS     config.DNS
 	Domains config.Domains
 	TTL     int
+	client  *http.Client
 }
 
 // TencentCloudRecord 腾讯云记录
@@ -63,11 +65,16 @@ type TencentCloudStatus struct {
 	}
 }
 
+func init() {
+	RegisterDNS("tencentcloud", func() DNS { return &TencentCloud{} })
+}
+
 func (tc *TencentCloud) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
 	tc.Domains.Ipv4Cache = ipv4cache
 	tc.Domains.Ipv6Cache = ipv6cache
 	tc.DNS = dnsConf.DNS
 	tc.Domains.GetNewIp(dnsConf)
+	tc.client = util.CreateHTTPClientTimeout(dnsConf.GetHTTPTimeout())
 	if dnsConf.TTL == "" {
 		// 默认 600s
 		tc.TTL = 600
@@ -82,23 +89,23 @@ func (tc *TencentCloud) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache,
 }
 
 // AddUpdateDomainRecords 添加或更新 IPv4/IPv6 记录
-func (tc *TencentCloud) AddUpdateDomainRecords() config.Domains {
-	tc.addUpdateDomainRecords("A")
-	tc.addUpdateDomainRecords("AAAA")
+func (tc *TencentCloud) AddUpdateDomainRecords(ctx context.Context) config.Domains {
+	tc.addUpdateDomainRecords(ctx, "A")
+	tc.addUpdateDomainRecords(ctx, "AAAA")
 	return tc.Domains
 }
 
-func (tc *TencentCloud) addUpdateDomainRecords(recordType string) {
+func (tc *TencentCloud) addUpdateDomainRecords(ctx context.Context, recordType string) {
 	ipAddr, domains := tc.Domains.GetNewIpResult(recordType)
 
 	if ipAddr == "" {
 		return
 	}
 
-	for _, domain := range domains {
-		result, err := tc.getRecordList(domain, recordType)
+	forEachDomain(domains, func(domain *config.Domain) {
+		result, err := tc.getRecordList(ctx, domain, recordType)
 		if err != nil {
-			util.Log("查询域名信息发生异常! %s", err)
+			util.LogError("查询域名信息发生异常! %s", err)
 			domain.UpdateStatus = config.UpdatedFailed
 			return
 		}
@@ -116,17 +123,20 @@ func (tc *TencentCloud) addUpdateDomainRecords(recordType string) {
 			}
 
 			// 修改记录
-			tc.modify(recordSelected, domain, recordType, ipAddr)
+			tc.modify(ctx, recordSelected, domain, recordType, ipAddr)
 		} else {
 			// 添加记录
-			tc.create(domain, recordType, ipAddr)
+			tc.create(ctx, domain, recordType, ipAddr)
 		}
-	}
+	})
 }
 
 // create 添加记录
 // CreateRecord https://cloud.tencent.com/document/api/1427/56180
-func (tc *TencentCloud) create(domain *config.Domain, recordType string, ipAddr string) {
+func (tc *TencentCloud) create(ctx context.Context, domain *config.Domain, recordType string, ipAddr string) {
+	if skipCreate(domain) {
+		return
+	}
 	record := &TencentCloudRecord{
 		Domain:     domain.DomainName,
 		SubDomain:  domain.GetSubDomain(),
@@ -136,15 +146,20 @@ func (tc *TencentCloud) create(domain *config.Domain, recordType string, ipAddr
 		TTL:        tc.TTL,
 	}
 
+	if dryRun(domain, "CreateRecord", tencentCloudEndPoint, record) {
+		return
+	}
+
 	var status TencentCloudStatus
 	err := tc.request(
+		ctx,
 		"CreateRecord",
 		record,
 		&status,
 	)
 
 	if err != nil {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogError("新增域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
@@ -153,17 +168,18 @@ func (tc *TencentCloud) create(domain *config.Domain, recordType string, ipAddr
 		util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, status.Response.Error.Message)
+		util.LogError("新增域名解析 %s 失败! 异常信息: %s", domain, status.Response.Error.Message)
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
 
 // modify 修改记录
 // ModifyRecord https://cloud.tencent.com/document/api/1427/56157
-func (tc *TencentCloud) modify(record TencentCloudRecord, domain *config.Domain, recordType string, ipAddr string) {
+func (tc *TencentCloud) modify(ctx context.Context, record TencentCloudRecord, domain *config.Domain, recordType string, ipAddr string) {
 	// 相同不修改
 	if record.Value == ipAddr {
-		util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		util.LogDebug("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		domain.UpdateStatus = config.UpdatedNothing
 		return
 	}
 	var status TencentCloudStatus
@@ -173,14 +189,18 @@ func (tc *TencentCloud) modify(record TencentCloudRecord, domain *config.Domain,
 	record.RecordLine = tc.getRecordLine(domain)
 	record.Value = ipAddr
 	record.TTL = tc.TTL
+	if dryRun(domain, "ModifyRecord", tencentCloudEndPoint, record) {
+		return
+	}
 	err := tc.request(
+		ctx,
 		"ModifyRecord",
 		record,
 		&status,
 	)
 
 	if err != nil {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, err)
 		domain.UpdateStatus = config.UpdatedFailed
 		return
 	}
@@ -189,14 +209,14 @@ func (tc *TencentCloud) modify(record TencentCloudRecord, domain *config.Domain,
 		util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
 	} else {
-		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, status.Response.Error.Message)
+		util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, status.Response.Error.Message)
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
 
 // getRecordList 获取域名的解析记录列表
 // DescribeRecordList https://cloud.tencent.com/document/api/1427/56166
-func (tc *TencentCloud) getRecordList(domain *config.Domain, recordType string) (result TencentCloudRecordListsResp, err error) {
+func (tc *TencentCloud) getRecordList(ctx context.Context, domain *config.Domain, recordType string) (result TencentCloudRecordListsResp, err error) {
 	record := TencentCloudRecord{
 		Domain:     domain.DomainName,
 		Subdomain:  domain.GetSubDomain(),
@@ -204,6 +224,7 @@ func (tc *TencentCloud) getRecordList(domain *config.Domain, recordType string)
 		RecordLine: tc.getRecordLine(domain),
 	}
 	err = tc.request(
+		ctx,
 		"DescribeRecordList",
 		record,
 		&result,
@@ -221,12 +242,13 @@ func (tc *TencentCloud) getRecordLine(domain *config.Domain) string {
 }
 
 // request 统一请求接口
-func (tc *TencentCloud) request(action string, data interface{}, result interface{}) (err error) {
+func (tc *TencentCloud) request(ctx context.Context, action string, data interface{}, result interface{}) (err error) {
 	jsonStr := make([]byte, 0)
 	if data != nil {
 		jsonStr, _ = json.Marshal(data)
 	}
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"POST",
 		tencentCloudEndPoint,
 		bytes.NewBuffer(jsonStr),
@@ -240,8 +262,7 @@ func (tc *TencentCloud) request(action string, data interface{}, result interfac
 
 	util.TencentCloudSigner(tc.DNS.ID, tc.DNS.Secret, req, action, string(jsonStr))
 
-	client := util.CreateHTTPClient()
-	resp, err := client.Do(req)
+	resp, err := tc.client.Do(req)
 	err = util.GetHTTPResponse(resp, err, result)
 
 	return
@@ -0,0 +1,74 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// TXTSetter 支持直接设置TXT记录的DNS服务商, 如 ACME DNS-01 验证
+type TXTSetter interface {
+	DNS
+	// 设置或替换TXT记录, 结果写入 domain.UpdateStatus
+	SetTXTRecord(ctx context.Context, domain *config.Domain, value string)
+}
+
+// SetTXTRecord 使用配置中的DNS服务商设置TXT记录
+// 优先使用域名列表中包含该根域名的配置, 否则使用第一个支持TXT记录的配置
+func SetTXTRecord(ctx context.Context, domainName, subDomain, value string) error {
+	conf, err := config.GetConfigCached()
+	if err != nil {
+		return err
+	}
+	dryRunEnabled = DryRun || conf.DryRun
+
+	var setter TXTSetter
+	var setterConf config.DnsConfig
+	for _, dc := range conf.DnsConf {
+		s, ok := newDNS(dc.DNS.Name).(TXTSetter)
+		if !ok || !dc.Enabled {
+			continue
+		}
+		if setter == nil || dnsConfHasDomain(dc, domainName) {
+			setter, setterConf = s, dc
+		}
+		if dnsConfHasDomain(dc, domainName) {
+			break
+		}
+	}
+	if setter == nil {
+		return errors.New(util.LogStr("没有支持TXT记录的DNS服务商配置"))
+	}
+
+	// 不需要获取IP
+	setterConf.Ipv4.Enable = false
+	setterConf.Ipv6.Enable = false
+	setter.Init(&setterConf, &util.IpCache{}, &util.IpCache{})
+
+	domain := &config.Domain{DomainName: domainName, SubDomain: subDomain}
+	setter.SetTXTRecord(ctx, domain, value)
+	if domain.UpdateStatus == config.UpdatedFailed {
+		return errors.New(util.LogStr("设置TXT记录 %s 失败", domain))
+	}
+	return nil
+}
+
+// dnsConfHasDomain 配置的域名列表中是否包含该根域名
+func dnsConfHasDomain(dc config.DnsConfig, domainName string) bool {
+	lines := append(append(append([]string{}, dc.Ipv4.Domains...), dc.Ipv6.Domains...), dc.Cname.Domains...)
+	lines = append(lines, dc.Mx.Domains...)
+	for _, line := range lines {
+		host := strings.Fields(strings.Split(line, "?")[0])
+		if len(host) == 0 {
+			continue
+		}
+		name := strings.ToLower(strings.ReplaceAll(host[0], ":", "."))
+		if name == domainName || strings.HasSuffix(name, "."+domainName) {
+			return true
+		}
+	}
+	return false
+}
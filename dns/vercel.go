@@ -2,6 +2,7 @@ package dns
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -16,6 +17,7 @@ type Vercel struct {
 	DNS     config.DNS
 	Domains config.Domains
 	TTL     int
+	client  *http.Client
 }
 
 type ListExistingRecordsResponse struct {
@@ -37,11 +39,16 @@ type Record struct {
 	Comment   *string `json:"comment,omitempty"`
 }
 
+func init() {
+	RegisterDNS("vercel", func() DNS { return &Vercel{} })
+}
+
 func (v *Vercel) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
 	v.Domains.Ipv4Cache = ipv4cache
 	v.Domains.Ipv6Cache = ipv6cache
 	v.DNS = dnsConf.DNS
 	v.Domains.GetNewIp(dnsConf)
+	v.client = util.CreateHTTPClientTimeout(dnsConf.GetHTTPTimeout())
 
 	// Must be greater than 60
 	ttl, err := strconv.Atoi(dnsConf.TTL)
@@ -54,13 +61,13 @@ func (v *Vercel) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6ca
 	v.TTL = ttl
 }
 
-func (v *Vercel) AddUpdateDomainRecords() (domains config.Domains) {
-	v.addUpdateDomainRecords("A")
-	v.addUpdateDomainRecords("AAAA")
+func (v *Vercel) AddUpdateDomainRecords(ctx context.Context) (domains config.Domains) {
+	v.addUpdateDomainRecords(ctx, "A")
+	v.addUpdateDomainRecords(ctx, "AAAA")
 	return v.Domains
 }
 
-func (v *Vercel) addUpdateDomainRecords(recordType string) {
+func (v *Vercel) addUpdateDomainRecords(ctx context.Context, recordType string) {
 	ipAddr, domains := v.Domains.GetNewIpResult(recordType)
 
 	if ipAddr == "" {
@@ -74,9 +81,9 @@ func (v *Vercel) addUpdateDomainRecords(recordType string) {
 		err     error
 	)
 	for _, domain := range domains {
-		records, err = v.listExistingRecords(domain)
+		records, err = v.listExistingRecords(ctx, domain)
 		if err != nil {
-			util.Log("查询域名信息发生异常! %s", err)
+			util.LogError("查询域名信息发生异常! %s", err)
 			continue
 		}
 
@@ -89,14 +96,32 @@ func (v *Vercel) addUpdateDomainRecords(recordType string) {
 		}
 
 		if targetRecord == nil {
-			err = v.createRecord(domain, recordType, ipAddr)
+			if skipCreate(domain) {
+				continue
+			}
+			if dryRun(domain, http.MethodPost, "https://api.vercel.com/v2/domains/"+domain.DomainName+"/records", map[string]interface{}{
+				"name":  domain.SubDomain,
+				"type":  recordType,
+				"value": ipAddr,
+				"ttl":   v.TTL,
+			}) {
+				continue
+			}
+			err = v.createRecord(ctx, domain, recordType, ipAddr)
 		} else {
 			if strings.ToLower(targetRecord.Value) == ipAddr {
-				util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+				util.LogDebug("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
 				domain.UpdateStatus = config.UpdatedNothing
 				continue
 			} else {
-				err = v.updateRecord(targetRecord, recordType, ipAddr)
+				if dryRun(domain, http.MethodPatch, "https://api.vercel.com/v1/domains/records/"+targetRecord.ID, map[string]interface{}{
+					"type":  recordType,
+					"value": ipAddr,
+					"ttl":   v.TTL,
+				}) {
+					continue
+				}
+				err = v.updateRecord(ctx, targetRecord, recordType, ipAddr)
 			}
 		}
 
@@ -108,15 +133,15 @@ func (v *Vercel) addUpdateDomainRecords(recordType string) {
 			util.Log(operation+"域名解析 %s 成功! IP: %s", domain, ipAddr)
 			domain.UpdateStatus = config.UpdatedSuccess
 		} else {
-			util.Log(operation+"域名解析 %s 失败! 异常信息: %s", domain, err)
+			util.LogError(operation+"域名解析 %s 失败! 异常信息: %s", domain, err)
 			domain.UpdateStatus = config.UpdatedFailed
 		}
 	}
 }
 
-func (v *Vercel) listExistingRecords(domain *config.Domain) (records []Record, err error) {
+func (v *Vercel) listExistingRecords(ctx context.Context, domain *config.Domain) (records []Record, err error) {
 	var result ListExistingRecordsResponse
-	err = v.request(http.MethodGet, "https://api.vercel.com/v4/domains/"+domain.DomainName+"/records", nil, &result)
+	err = v.request(ctx, http.MethodGet, "https://api.vercel.com/v4/domains/"+domain.DomainName+"/records", nil, &result)
 	if err != nil {
 		return
 	}
@@ -124,8 +149,8 @@ func (v *Vercel) listExistingRecords(domain *config.Domain) (records []Record, e
 	return
 }
 
-func (v *Vercel) createRecord(domain *config.Domain, recordType string, recordValue string) (err error) {
-	err = v.request(http.MethodPost, "https://api.vercel.com/v2/domains/"+domain.DomainName+"/records", map[string]interface{}{
+func (v *Vercel) createRecord(ctx context.Context, domain *config.Domain, recordType string, recordValue string) (err error) {
+	err = v.request(ctx, http.MethodPost, "https://api.vercel.com/v2/domains/"+domain.DomainName+"/records", map[string]interface{}{
 		"name":    domain.SubDomain,
 		"type":    recordType,
 		"value":   recordValue,
@@ -135,8 +160,8 @@ func (v *Vercel) createRecord(domain *config.Domain, recordType string, recordVa
 	return
 }
 
-func (v *Vercel) updateRecord(record *Record, recordType string, recordValue string) (err error) {
-	err = v.request(http.MethodPatch, "https://api.vercel.com/v1/domains/records/"+record.ID, map[string]interface{}{
+func (v *Vercel) updateRecord(ctx context.Context, record *Record, recordType string, recordValue string) (err error) {
+	err = v.request(ctx, http.MethodPatch, "https://api.vercel.com/v1/domains/records/"+record.ID, map[string]interface{}{
 		"type":  recordType,
 		"value": recordValue,
 		"ttl":   v.TTL,
@@ -144,13 +169,14 @@ func (v *Vercel) updateRecord(record *Record, recordType string, recordValue str
 	return
 }
 
-func (v *Vercel) request(method, api string, data, result interface{}) (err error) {
+func (v *Vercel) request(ctx context.Context, method, api string, data, result interface{}) (err error) {
 	var payload []byte
 	if data != nil {
 		payload, _ = json.Marshal(data)
 	}
 
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		method,
 		api,
 		bytes.NewBuffer(payload),
@@ -161,8 +187,7 @@ func (v *Vercel) request(method, api string, data, result interface{}) (err erro
 	req.Header.Set("Authorization", "Bearer "+v.DNS.Secret)
 	req.Header.Set("Content-Type", "application/json")
 
-	client := util.CreateHTTPClient()
-	resp, err := client.Do(req)
+	resp, err := v.client.Do(req)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,205 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+const (
+	vultrEndpoint = "https://api.vultr.com/v2/domains"
+	// Vultr 允许的最小TTL
+	vultrMinTTL = 120
+)
+
+// Vultr Vultr实现
+// https://www.vultr.com/api/#tag/dns
+type Vultr struct {
+	DNS     config.DNS
+	Domains config.Domains
+	TTL     int
+	client  *http.Client
+}
+
+// VultrRecord 记录实体
+type VultrRecord struct {
+	ID   string `json:"id,omitempty"`
+	Type string `json:"type"`
+	Name string `json:"name"`
+	Data string `json:"data"`
+	TTL  int    `json:"ttl"`
+}
+
+// VultrRecordsResp records返回结果
+type VultrRecordsResp struct {
+	Records []VultrRecord `json:"records"`
+	Meta    struct {
+		Links struct {
+			Next string `json:"next"`
+		} `json:"links"`
+	} `json:"meta"`
+}
+
+// VultrRecordResp 新增记录返回结果
+type VultrRecordResp struct {
+	Record VultrRecord `json:"record"`
+}
+
+func init() {
+	RegisterDNS("vultr", func() DNS { return &Vultr{} })
+}
+
+// Init 初始化
+func (vultr *Vultr) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
+	vultr.Domains.Ipv4Cache = ipv4cache
+	vultr.Domains.Ipv6Cache = ipv6cache
+	vultr.DNS = dnsConf.DNS
+	vultr.Domains.GetNewIp(dnsConf)
+	vultr.client = util.CreateHTTPClientTimeout(dnsConf.GetHTTPTimeout())
+
+	vultr.TTL = 300
+	if dnsConf.TTL != "" {
+		ttl, err := strconv.Atoi(dnsConf.TTL)
+		if err == nil {
+			vultr.TTL = ttl
+		}
+	}
+	if vultr.TTL < vultrMinTTL {
+		vultr.TTL = vultrMinTTL
+	}
+}
+
+// AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
+func (vultr *Vultr) AddUpdateDomainRecords(ctx context.Context) config.Domains {
+	vultr.addUpdateDomainRecords(ctx, "A")
+	vultr.addUpdateDomainRecords(ctx, "AAAA")
+	return vultr.Domains
+}
+
+func (vultr *Vultr) addUpdateDomainRecords(ctx context.Context, recordType string) {
+	ipAddr, domains := vultr.Domains.GetNewIpResult(recordType)
+
+	if ipAddr == "" {
+		return
+	}
+
+	forEachDomain(domains, func(domain *config.Domain) {
+		record, err := vultr.getRecord(ctx, domain, recordType)
+		if err != nil {
+			util.LogError("查询域名信息发生异常! %s", err)
+			domain.UpdateStatus = config.UpdatedFailed
+			return
+		}
+
+		if record != nil {
+			vultr.modify(ctx, *record, domain, ipAddr)
+		} else {
+			vultr.create(ctx, domain, recordType, ipAddr)
+		}
+	})
+}
+
+// getRecord 获得名称和类型相同的记录, 不存在时返回nil
+func (vultr *Vultr) getRecord(ctx context.Context, domain *config.Domain, recordType string) (*VultrRecord, error) {
+	cursor := ""
+	for {
+		var result VultrRecordsResp
+		err := vultr.request(
+			ctx,
+			http.MethodGet,
+			fmt.Sprintf(vultrEndpoint+"/%s/records?per_page=500&cursor=%s", domain.DomainName, url.QueryEscape(cursor)),
+			nil,
+			&result,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, record := range result.Records {
+			// 根域名的记录名称为空
+			if record.Type == recordType && record.Name == domain.SubDomain {
+				return &record, nil
+			}
+		}
+
+		cursor = result.Meta.Links.Next
+		if cursor == "" {
+			return nil, nil
+		}
+	}
+}
+
+func (vultr *Vultr) create(ctx context.Context, domain *config.Domain, recordType string, ipAddr string) {
+	if skipCreate(domain) {
+		return
+	}
+	recordsURL := fmt.Sprintf(vultrEndpoint+"/%s/records", domain.DomainName)
+	record := &VultrRecord{
+		Type: recordType,
+		Name: domain.SubDomain,
+		Data: ipAddr,
+		TTL:  vultr.TTL,
+	}
+	if dryRun(domain, http.MethodPost, recordsURL, record) {
+		return
+	}
+
+	var result VultrRecordResp
+	err := vultr.request(ctx, http.MethodPost, recordsURL, record, &result)
+	if err != nil {
+		util.LogError("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+
+	util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
+	domain.UpdateStatus = config.UpdatedSuccess
+}
+
+func (vultr *Vultr) modify(ctx context.Context, record VultrRecord, domain *config.Domain, ipAddr string) {
+	// 相同不修改
+	if record.Data == ipAddr && record.TTL == vultr.TTL {
+		util.LogDebug("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		domain.UpdateStatus = config.UpdatedNothing
+		return
+	}
+
+	recordURL := fmt.Sprintf(vultrEndpoint+"/%s/records/%s", domain.DomainName, record.ID)
+	body := map[string]interface{}{
+		"data": ipAddr,
+		"ttl":  vultr.TTL,
+	}
+	if dryRun(domain, http.MethodPatch, recordURL, body) {
+		return
+	}
+
+	// 更新成功时返回204, 没有内容
+	var result VultrRecordResp
+	err := vultr.request(ctx, http.MethodPatch, recordURL, body, &result)
+	if err != nil {
+		util.LogError("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+
+	util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+	domain.UpdateStatus = config.UpdatedSuccess
+}
+
+// request 统一请求接口
+func (vultr *Vultr) request(ctx context.Context, method, url string, body interface{}, result interface{}) error {
+	req, err := util.NewJSONRequest(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+vultr.DNS.Secret)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := vultr.client.Do(req)
+	return util.GetHTTPResponse(resp, err, result)
+}
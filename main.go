@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
 	"embed"
 	"errors"
 	"flag"
@@ -10,8 +12,11 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/jeessy2/ddns-go/v6/config"
@@ -30,13 +35,13 @@ var versionFlag = flag.Bool("v", false, "ddns-go version")
 var updateFlag = flag.Bool("u", false, "Upgrade ddns-go to the latest version")
 
 // 监听地址
-var listen = flag.String("l", ":9876", "Listen address")
+var listen = flag.String("l", ":9876", "Listen address, host:port or unix:/path/to.sock")
 
 // 更新频率(秒)
 var every = flag.Int("f", 300, "Update frequency(seconds)")
 
 // 缓存次数
-var ipCacheTimes = flag.Int("cacheTimes", 5, "Cache times")
+var ipCacheTimes = flag.Int("cacheTimes", 5, "Cache times, -1 means only compare when IP changed")
 
 // 服务管理
 var serviceType = flag.String("s", "", "Service management (install|uninstall|restart)")
@@ -53,6 +58,52 @@ var skipVerify = flag.Bool("skipVerify", false, "Skip certificate verification")
 // 自定义 DNS 服务器
 var customDNS = flag.String("dns", "", "Custom DNS server address, example: 8.8.8.8")
 
+// 代理
+var proxyURL = flag.String("proxy", "", "Proxy for all requests, including getting IP, example: http://127.0.0.1:7890, socks5://127.0.0.1:1080")
+
+// HTTPS
+var tlsFlag = flag.Bool("tls", false, "Serve the web UI over HTTPS, a self-signed certificate is generated when -tlsCert/-tlsKey are not set")
+var tlsCert = flag.String("tlsCert", "", "Certificate file for HTTPS")
+var tlsKey = flag.String("tlsKey", "", "Private key file for HTTPS")
+
+// 可信的反向代理
+var trustedProxies = flag.String("trustedProxies", "", "Trusted reverse proxies (IP or CIDR, comma separated) whose X-Forwarded-For/X-Real-IP is used as the client IP, example: 127.0.0.1,172.17.0.0/16")
+
+// Prometheus 指标
+var metricsFlag = flag.Bool("metrics", false, "Expose Prometheus metrics at /metrics, no login required")
+
+// 最低输出的日志级别
+var logLevel = flag.String("logLevel", "info", "Minimum log level (debug|info|warn|error), use warn or error for a quiet log")
+
+// 日志语言
+var logLang = flag.String("lang", "", "Log language (en|zh), default follows the language of the browser when saving the config")
+
+// 日志格式
+var logFormat = flag.String("logFormat", "text", "Log format (text|json), json outputs one JSON object per line")
+
+// 同时更新的域名数量
+var concurrency = flag.Int("concurrency", 5, "Number of domains updated at the same time")
+
+// 定时运行的随机偏移
+var jitter = flag.Int("jitter", 15, "Random offset (± seconds, at most 1/10 of the interval) of each scheduled update, 0 to disable")
+
+// 健康检查的失败阈值
+var healthThreshold = flag.Int("healthThreshold", 3600, "Seconds all DNS configs keep failing before /healthz returns 503, 0 to disable")
+
+// 请求DNS服务商的最大尝试次数
+var retryAttempts = flag.Int("retry", 3, "Max attempts of a request to the DNS provider on network errors, 5xx or 429")
+
+// 演练模式
+var dryRunFlag = flag.Bool("dryRun", false, "Dry run, only log the changes without updating DNS records")
+
+// 运行一次后退出
+var onceFlag = flag.Bool("once", false, "Update once and exit. Exit code 0: succeeded or nothing changed, 1: some domains failed, 2: config error")
+
+// 设置TXT记录, 如 ACME DNS-01 验证
+var txtDomain = flag.String("txtDomain", "", "Set a TXT record with the configured provider and exit, example: example.com")
+var txtName = flag.String("txtName", "_acme-challenge", "The name of the TXT record, used with -txtDomain")
+var txtValue = flag.String("txtValue", "", "The value of the TXT record, used with -txtDomain")
+
 // 重置密码
 var newPassword = flag.String("resetPassword", "", "Reset password to the one entered")
 
@@ -76,7 +127,7 @@ func main() {
 		return
 	}
 	// 检查监听地址
-	if _, err := net.ResolveTCPAddr("tcp", *listen); err != nil {
+	if err := util.CheckListenAddr(*listen); err != nil {
 		log.Fatalf("Parse listen address failed! Exception: %s", err)
 	}
 	// 设置版本号
@@ -100,7 +151,47 @@ func main() {
 	if *customDNS != "" {
 		util.SetDNS(*customDNS)
 	}
+	// 设置代理
+	if *proxyURL != "" {
+		if err := util.SetProxy(*proxyURL); err != nil {
+			log.Fatal(err)
+		}
+	}
 	os.Setenv(util.IPCacheTimesENV, strconv.Itoa(*ipCacheTimes))
+	// 设置日志语言、级别和格式
+	if *logLang != "" {
+		util.SetLogLang(*logLang)
+	}
+	if err := util.SetLogLevel(*logLevel); err != nil {
+		log.Fatal(err)
+	}
+	if err := util.SetLogFormat(*logFormat); err != nil {
+		log.Fatal(err)
+	}
+	if err := util.SetTrustedProxies(*trustedProxies); err != nil {
+		log.Fatal(err)
+	}
+	// 设置重试次数
+	util.SetMaxRetryAttempts(*retryAttempts)
+	// 演练模式
+	dns.DryRun = *dryRunFlag
+	// 同时更新的域名数量
+	dns.Concurrency = *concurrency
+	// 定时运行的随机偏移
+	dns.Jitter = time.Duration(*jitter) * time.Second
+	// 健康检查的失败阈值
+	dns.HealthThreshold = time.Duration(*healthThreshold) * time.Second
+	// 运行一次后退出, 不启动web服务
+	if *onceFlag {
+		os.Exit(runOnce())
+	}
+	// 设置TXT记录后退出
+	if *txtDomain != "" {
+		if err := dns.SetTXTRecord(context.Background(), strings.ToLower(*txtDomain), *txtName, *txtValue); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 	switch *serviceType {
 	case "install":
 		installService()
@@ -110,6 +201,7 @@ func main() {
 		restartService()
 	default:
 		if util.IsRunInDocker() {
+			go waitSignal()
 			run()
 		} else {
 			s := getService()
@@ -125,6 +217,7 @@ func main() {
 				default:
 					util.Log("可使用 sudo ./ddns-go -s install 安装服务运行")
 				}
+				go waitSignal()
 				run()
 			}
 		}
@@ -132,9 +225,8 @@ func main() {
 }
 
 func run() {
-	// 兼容之前的配置文件
+	// 读取配置时会升级之前的配置文件
 	conf, _ := config.GetConfigCached()
-	conf.CompatibleConfig()
 	// 初始化语言
 	util.InitLogLang(conf.Lang)
 
@@ -153,13 +245,61 @@ func run() {
 	// 初始化备用DNS
 	util.InitBackupDNS(*customDNS, conf.Lang)
 
+	// 配置文件被修改后重新加载并运行一次
+	go config.WatchConfig(10*time.Second, func() {
+		util.ForceCompareGlobal = true
+		dns.RunOnce()
+	})
+
 	// 等待网络连接
 	util.WaitInternet(dns.Addresses)
 
+	// 检查配置的根域名是否存在
+	dns.ValidateDomains(util.ShutdownContext())
+
 	// 定时运行
 	dns.RunTimer(time.Duration(*every) * time.Second)
 }
 
+// 退出时等待正在进行的更新完成的最长时间, docker stop 默认10秒后强制结束
+const shutdownTimeout = 7 * time.Second
+
+// waitSignal 非服务方式运行时, 收到退出信号后等待正在进行的更新完成再退出
+func waitSignal() {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
+	<-ch
+	shutdown()
+	os.Exit(0)
+}
+
+// shutdown 等待正在进行的更新完成, 不再开始新的更新
+func shutdown() {
+	util.Log("收到退出信号, 等待正在进行的更新完成")
+	dns.Shutdown(shutdownTimeout)
+	util.Log("ddns-go 已退出")
+}
+
+// runOnce 更新一次, 返回退出码
+// 0: 全部成功或未改变, 1: 有域名更新失败, 2: 配置文件异常
+func runOnce() int {
+	conf, err := config.GetConfigCached()
+	if err != nil {
+		log.Println(err)
+		return 2
+	}
+	util.InitLogLang(conf.Lang)
+	util.InitBackupDNS(*customDNS, conf.Lang)
+
+	result := dns.RunOnceResult()
+	util.Log("更新完成, 成功: %d, 失败: %d, 未改变: %d", result.Success, result.Failed, result.Nothing)
+	if err := result.Err(); err != nil {
+		util.LogError("%s", err)
+		return 1
+	}
+	return 0
+}
+
 func staticFsFunc(writer http.ResponseWriter, request *http.Request) {
 	http.FileServer(http.FS(staticEmbeddedFiles)).ServeHTTP(writer, request)
 }
@@ -178,22 +318,47 @@ func runWebServer() error {
 	http.HandleFunc("/", web.Auth(web.Writing))
 	http.HandleFunc("/save", web.Auth(web.Save))
 	http.HandleFunc("/logs", web.Auth(web.Logs))
+	http.HandleFunc("/logs/stream", web.Auth(web.LogsStream))
 	http.HandleFunc("/clearLog", web.Auth(web.ClearLog))
 	http.HandleFunc("/webhookTest", web.Auth(web.WebhookTest))
+	http.HandleFunc("/dnsTest", web.Auth(web.DnsTest))
+	http.HandleFunc("/api/update", web.Auth(web.UpdateNow))
+	http.HandleFunc("/api/status", web.Auth(web.Status))
+	// 不需要登录, 便于 Docker/Kubernetes 健康检查
+	http.HandleFunc("/healthz", web.AuthAssert(web.Healthz))
+	if *metricsFlag {
+		// 不需要登录, 便于 Prometheus 抓取
+		http.HandleFunc("/metrics", web.AuthAssert(web.Metrics))
+	}
 
 	util.Log("监听 %s", *listen)
 
-	l, err := net.Listen("tcp", *listen)
+	l, err := util.Listen(*listen)
 	if err != nil {
 		return errors.New(util.LogStr("监听端口发生异常, 请检查端口是否被占用! %s", err))
 	}
 
+	// 使用HTTPS
+	if useTLS() {
+		cert, err := util.LoadTLSCertificate(*tlsCert, *tlsKey)
+		if err != nil {
+			l.Close()
+			return errors.New(util.LogStr("读取证书失败! %s", err))
+		}
+		l = tls.NewListener(l, &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12})
+	}
+
 	// 没有配置, 自动打开浏览器
 	autoOpenExplorer()
 
 	return http.Serve(l, nil)
 }
 
+// useTLS 是否使用HTTPS, 指定了证书时自动开启
+func useTLS() bool {
+	return *tlsFlag || *tlsCert != "" || *tlsKey != ""
+}
+
 type program struct{}
 
 func (p *program) Start(s service.Service) error {
@@ -206,6 +371,7 @@ func (p *program) run() {
 }
 func (p *program) Stop(s service.Service) error {
 	// Stop should not block. Return with a few seconds.
+	shutdown()
 	return nil
 }
 
@@ -247,6 +413,62 @@ func getService() service.Service {
 		svcConfig.Arguments = append(svcConfig.Arguments, "-dns", *customDNS)
 	}
 
+	if *proxyURL != "" {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-proxy", *proxyURL)
+	}
+
+	if *trustedProxies != "" {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-trustedProxies", *trustedProxies)
+	}
+
+	if *metricsFlag {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-metrics")
+	}
+
+	if *tlsFlag {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-tls")
+	}
+
+	if *tlsCert != "" {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-tlsCert", *tlsCert)
+	}
+
+	if *tlsKey != "" {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-tlsKey", *tlsKey)
+	}
+
+	if *retryAttempts != 3 {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-retry", strconv.Itoa(*retryAttempts))
+	}
+
+	if *logLevel != "info" {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-logLevel", *logLevel)
+	}
+
+	if *logLang != "" {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-lang", *logLang)
+	}
+
+	if *logFormat != "text" {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-logFormat", *logFormat)
+	}
+
+	if *concurrency != 5 {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-concurrency", strconv.Itoa(*concurrency))
+	}
+
+	if *jitter != 15 {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-jitter", strconv.Itoa(*jitter))
+	}
+
+	if *healthThreshold != 3600 {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-healthThreshold", strconv.Itoa(*healthThreshold))
+	}
+
+	if *dryRunFlag {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-dryRun")
+	}
+
 	prg := &program{}
 	s, err := service.New(prg, svcConfig)
 	if err != nil {
@@ -267,7 +489,7 @@ func uninstallService() {
 	if err := s.Uninstall(); err == nil {
 		util.Log("ddns-go 服务卸载成功")
 	} else {
-		util.Log("ddns-go 服务卸载失败, 异常信息: %s", err)
+		util.LogError("ddns-go 服务卸载失败, 异常信息: %s", err)
 	}
 }
 
@@ -291,7 +513,7 @@ func installService() {
 			}
 			return
 		}
-		util.Log("安装 ddns-go 服务失败, 异常信息: %s", err)
+		util.LogError("安装 ddns-go 服务失败, 异常信息: %s", err)
 	}
 
 	if status != service.StatusUnknown {
@@ -332,9 +554,13 @@ func autoOpenExplorer() {
 			if err != nil {
 				return
 			}
-			url := fmt.Sprintf("http://127.0.0.1:%d", addr.Port)
+			scheme := "http"
+			if useTLS() {
+				scheme = "https"
+			}
+			url := fmt.Sprintf("%s://127.0.0.1:%d", scheme, addr.Port)
 			if addr.IP.IsGlobalUnicast() {
-				url = fmt.Sprintf("http://%s", addr.String())
+				url = fmt.Sprintf("%s://%s", scheme, addr.String())
 			}
 			go util.OpenExplorer(url)
 		}
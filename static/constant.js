@@ -38,11 +38,12 @@ const DNS_PROVIDERS = {
     name: {
       "en": "Cloudflare",
     },
-    idLabel: "",
+    idLabel: "Email",
     secretLabel: "Token",
+    zoneIdLabel: "Zone ID",
     helpHtml: {
-      "en": "<a target='_blank' href='https://dash.cloudflare.com/profile/api-tokens'>Create Token -> Edit Zone DNS (Use template)</a>",
-      "zh-cn": "<a target='_blank' href='https://dash.cloudflare.com/profile/api-tokens'>创建令牌 -> 编辑区域 DNS (使用模板)</a>",
+      "en": "<a target='_blank' href='https://dash.cloudflare.com/profile/api-tokens'>Create Token -> Edit Zone DNS (Use template)</a><br />To use the Global API Key, select Global API Key as the Auth Mode, enter the account Email and fill the Global API Key into Token",
+      "zh-cn": "<a target='_blank' href='https://dash.cloudflare.com/profile/api-tokens'>创建令牌 -> 编辑区域 DNS (使用模板)</a><br />如需使用 Global API Key, 请将认证方式选择为 Global API Key, 填写账号 Email, 并将 Global API Key 填入 Token",
     }
   },
   huaweicloud: {
@@ -157,6 +158,153 @@ const DNS_PROVIDERS = {
       "zh-cn": "<a target='_blank' href='https://www.dynadot.com/community/help/question/enable-DDNS'>开启Dynadot动态域名解析</a>",
     }
   },
+  desec: {
+    name: {
+      "en": "deSEC",
+    },
+    idLabel: "",
+    secretLabel: "Token",
+    helpHtml: {
+      "en": "<a target='_blank' href='https://desec.io/tokens'>Create Token</a> The minimum TTL is 3600",
+      "zh-cn": "<a target='_blank' href='https://desec.io/tokens'>创建令牌</a> TTL最小为3600",
+    }
+  },
+  hetzner: {
+    name: {
+      "en": "Hetzner",
+    },
+    idLabel: "",
+    secretLabel: "Token",
+    zoneIdLabel: "Zone ID",
+    helpHtml: {
+      "en": "<a target='_blank' href='https://dns.hetzner.com/settings/api-token'>Create API Token</a>",
+      "zh-cn": "<a target='_blank' href='https://dns.hetzner.com/settings/api-token'>创建 API Token</a>",
+    }
+  },
+  gandi: {
+    name: {
+      "en": "Gandi",
+    },
+    idLabel: "API Key",
+    secretLabel: "PAT",
+    helpHtml: {
+      "en": "<a target='_blank' href='https://account.gandi.net/'>Create Personal Access Token</a> Fill in either the API Key or the PAT",
+      "zh-cn": "<a target='_blank' href='https://account.gandi.net/'>创建个人访问令牌</a> API Key 和 PAT 填写其中一个即可",
+    }
+  },
+  linode: {
+    name: {
+      "en": "Linode",
+    },
+    idLabel: "",
+    secretLabel: "Token",
+    helpHtml: {
+      "en": "<a target='_blank' href='https://cloud.linode.com/profile/tokens'>Create Personal Access Token</a> Requires Domains read/write scope",
+      "zh-cn": "<a target='_blank' href='https://cloud.linode.com/profile/tokens'>创建个人访问令牌</a> 需要 Domains 读写权限",
+    }
+  },
+  vultr: {
+    name: {
+      "en": "Vultr",
+    },
+    idLabel: "",
+    secretLabel: "API Key",
+    helpHtml: {
+      "en": "<a target='_blank' href='https://my.vultr.com/settings/#settingsapi'>Create API Key</a> The minimum TTL is 120",
+      "zh-cn": "<a target='_blank' href='https://my.vultr.com/settings/#settingsapi'>创建 API Key</a> TTL最小为120",
+    }
+  },
+  digitalocean: {
+    name: {
+      "en": "DigitalOcean",
+    },
+    idLabel: "",
+    secretLabel: "Token",
+    helpHtml: {
+      "en": "<a target='_blank' href='https://cloud.digitalocean.com/account/api/tokens'>Create Personal Access Token</a>",
+      "zh-cn": "<a target='_blank' href='https://cloud.digitalocean.com/account/api/tokens'>创建个人访问令牌</a>",
+    }
+  },
+  dynu: {
+    name: {
+      "en": "Dynu",
+    },
+    idLabel: "",
+    secretLabel: "API Key",
+    helpHtml: {
+      "en": "<a target='_blank' href='https://www.dynu.com/ControlPanel/APICredentials'>Get API Key</a>",
+      "zh-cn": "<a target='_blank' href='https://www.dynu.com/ControlPanel/APICredentials'>获取 API Key</a>",
+    }
+  },
+  duckdns: {
+    name: {
+      "en": "DuckDNS",
+    },
+    idLabel: "",
+    secretLabel: "Token",
+    helpHtml: {
+      "en": "<a target='_blank' href='https://www.duckdns.org/'>Get Token</a> Domains are filled in as xxx.duckdns.org",
+      "zh-cn": "<a target='_blank' href='https://www.duckdns.org/'>获取 Token</a> 域名填写为 xxx.duckdns.org",
+    }
+  },
+  dyndns2: {
+    name: {
+      "en": "DynDNS2",
+    },
+    idLabel: "Username",
+    secretLabel: "Password",
+    helpHtml: {
+      "en": "Works with No-IP, FreeDNS and other hosts speaking the DynDNS2 protocol",
+      "zh-cn": "适用于 No-IP、FreeDNS 等支持 DynDNS2 协议的服务商",
+    }
+  },
+  route53: {
+    name: {
+      "en": "Route53",
+    },
+    idLabel: "Access Key ID",
+    secretLabel: "Secret Access Key",
+    zoneIdLabel: "Hosted Zone ID",
+    helpHtml: {
+      "en": "<a target='_blank' href='https://console.aws.amazon.com/iam/home#/security_credentials'>Create Access Key</a> Requires route53:ListHostedZonesByName, route53:ListResourceRecordSets and route53:ChangeResourceRecordSets",
+      "zh-cn": "<a target='_blank' href='https://console.aws.amazon.com/iam/home#/security_credentials'>创建访问密钥</a> 需要 route53:ListHostedZonesByName、route53:ListResourceRecordSets 和 route53:ChangeResourceRecordSets 权限",
+    }
+  },
+  azure: {
+    name: {
+      "en": "Azure DNS",
+    },
+    idLabel: "Client ID",
+    secretLabel: "Client Secret",
+    zoneIdLabel: "Resource ID",
+    helpHtml: {
+      "en": "<a target='_blank' href='https://learn.microsoft.com/en-us/entra/identity-platform/howto-create-service-principal-portal'>Create an App Registration</a> and grant it the DNS Zone Contributor role on the zone. Resource ID is on the zone's Properties page, such as /subscriptions/{sub}/resourceGroups/{rg}/providers/Microsoft.Network/dnsZones/example.com",
+      "zh-cn": "<a target='_blank' href='https://learn.microsoft.com/zh-cn/entra/identity-platform/howto-create-service-principal-portal'>创建应用注册</a> 并在 DNS 区域上授予 DNS Zone Contributor 角色。资源 ID 可在区域的属性页面找到, 如 /subscriptions/{sub}/resourceGroups/{rg}/providers/Microsoft.Network/dnsZones/example.com",
+    }
+  },
+  googleclouddns: {
+    name: {
+      "en": "Google Cloud DNS",
+    },
+    idLabel: "Project ID",
+    secretLabel: "Service Account Key",
+    zoneIdLabel: "Managed Zone",
+    helpHtml: {
+      "en": "<a target='_blank' href='https://console.cloud.google.com/iam-admin/serviceaccounts'>Create a Service Account</a> with the DNS Administrator role, then paste the whole JSON key. Project ID can be left empty to use the one in the key",
+      "zh-cn": "<a target='_blank' href='https://console.cloud.google.com/iam-admin/serviceaccounts'>创建服务账号</a> 并授予 DNS Administrator 角色, 然后粘贴完整的 JSON 密钥。项目 ID 为空时使用密钥中的项目",
+    }
+  },
+  ovh: {
+    name: {
+      "en": "OVH",
+    },
+    idLabel: "Application Key",
+    secretLabel: "Application Secret",
+    helpHtml: {
+      "en": "<a target='_blank' href='https://eu.api.ovh.com/createToken/?GET=/domain/zone/*&POST=/domain/zone/*&PUT=/domain/zone/*'>Create Token</a> Requires GET/POST/PUT on /domain/zone/*",
+      "zh-cn": "<a target='_blank' href='https://eu.api.ovh.com/createToken/?GET=/domain/zone/*&POST=/domain/zone/*&PUT=/domain/zone/*'>创建 Token</a> 需要 /domain/zone/* 的 GET/POST/PUT 权限",
+    }
+  },
 };
 
 const SVG_CODE = {
@@ -171,6 +319,7 @@ const I18N_MAP = {
   'en': {
     'Logs': 'Logs',
     'Save': 'Save',
+    'Update Now': 'Update Now',
     'Config:': 'Config:',
     'Add': 'Add',
     'Rename': 'Rename',
@@ -187,17 +336,50 @@ const I18N_MAP = {
     '10m': '10m',
     '30m': '30m',
     '1h': '1h',
-    'ttlHelp': 'You can modify it if the account supports a smaller TTL. The TTL will only be updated when the IP changes',
+    'ttlHelp': 'You can modify it if the account supports a smaller TTL. The TTL will only be updated when the IP changes. For Cloudflare, Auto and 1s both mean Auto. A TTL less than the minimum of the provider, such as 600 for Porkbun/GoDaddy, is adjusted to the minimum',
+    'zoneIdHelp': 'Optional. Required when the token only has permission on a single zone, the zone lookup by root domain will be skipped',
+    'Proxied': 'Proxied',
+    'proxiedHelp': 'Route traffic through the Cloudflare proxy (orange cloud). Only works for HTTP(S) services. Add <code>?nonWeb=true</code> to a domain to always keep it DNS only, such as an SSH or game server',
+    'Auth Mode': 'Auth Mode',
+    'Comment': 'Comment',
+    'Tags': 'Tags',
+    'commentTagsHelp': 'Optional. Multiple tags are separated by commas, such as: owner:ddns-go. Existing comment and tags on a record will be kept when updating',
+    'cnameDomainsHelp': 'One per line, the domain and the CNAME target separated by a space, such as: www.example.com home.example.net. No IP is needed',
+    'mxDomainsHelp': 'One per line, the domain, priority and mail server separated by spaces, such as: example.com 10 mail.example.com. No IP is needed',
+    'Update URL': 'Update URL',
+    'updateUrlHelp': 'DynDNS2: the update URL of your provider, defaults to No-IP: https://dynupdate.no-ip.com/nic/update. OVH: ovh-eu (default), ovh-ca, ovh-us or the API URL',
+    'Clean Duplicates': 'Clean Duplicates',
+    'Update Only': 'Update Only',
+    'Delete On No IP': 'Delete On No IP',
+    'Allow private IP': 'Allow private IP',
+    'Suffix': 'Suffix',
+    'Prefer temporary': 'Prefer temporary',
+    'preferTemporaryHelp': 'Deprecated addresses are skipped and stable addresses are preferred by default. Check it to prefer temporary (privacy) addresses. Only supported on Linux',
+    'ipv6SuffixHelp': 'Optional. A fixed interface ID such as <code>::1234</code>, combined with the /64 prefix of the IPv6 obtained, for the device behind a rotating prefix',
+    'allowPrivateHelp': 'By default, private, loopback, link-local and CGNAT (100.64.0.0/10) addresses are not updated. Check it if you resolve domains to a LAN address',
+    'Notify Cooldown': 'Notify Cooldown',
+    'notifyCooldownHelp': 'Minutes. After a notification, further changes of the same domain are not notified within this time, the latest result is notified when it ends. Failures are always notified. Empty to disable',
+    'enabledHelp': 'A disabled config is skipped when updating, its settings and credentials are kept',
+    'cleanDuplicatesHelp': 'Delete other records with the same name and type, keeping only the latest one. Do not enable it if you use round-robin or manually pinned records',
+    'updateOnlyHelp': 'Only update existing records, a domain whose record is not found is marked as failed instead of being created. Can also be enabled for a single domain with <code>?updateOnly=true</code>',
+    'deleteOnNoIPHelp': 'Delete the A/AAAA records after failing to get the IPv4/IPv6 address 3 times in a row, so clients will not time out on a stale record. The records are created again once the address is available',
+    'HTTP Timeout': 'HTTP Timeout',
+    'httpTimeoutHelp': 'Timeout in seconds for requests to the DNS provider, default 30 seconds if left blank',
+    'Interval': 'Interval',
+    'intervalHelp': 'Sync interval in seconds for this DNS configuration, use the <code>-f</code> startup parameter if left blank',
+    'DoH': 'DoH',
+    'dohHelp': 'Optional. Query the current A/AAAA record through this DNS-over-HTTPS resolver first, and skip the DNS provider if it already matches. Leave it blank to disable',
     'Enabled': 'Enabled',
     'Get IP method': 'Get IP method',
     'By api': 'By api',
     'By network card': 'By network card',
     'By command': 'By command',
     'domainsHelp': `
-      Enter one domain per line.
+      Enter one domain per line, all of them are updated to the IP obtained above.
       If the domain is unregistrable, manually separate it into a subdomain and a root domain by using a colon. e.g. <code>www:domain.example.com</code><br />
 
-      Support for <a target="blank" href="https://github.com/jeessy2/ddns-go/wiki/传递自定义参数">custom parameters</a> (Simplified Chinese)
+      Support for <a target="blank" href="https://github.com/jeessy2/ddns-go/wiki/传递自定义参数">custom parameters</a> (Simplified Chinese).
+      Cloudflare supports <code>?ttl=300</code> and <code>?proxied=false</code> to override the TTL and proxy status of a single record, <code>?nonWeb=true</code> always keeps a non-web service such as SSH DNS only
     `,
     'Regular exp.': 'Regular exp.',
     'regHelp': 'You can use @1 to specify the first IPv6 address, @2 to specify the second IPv6 address... You can also use regular expressions to match the specified IPv6 address, leave it blank to disable it',
@@ -215,15 +397,42 @@ const I18N_MAP = {
       >Click to get more info</a
       ><br />
       Support variables #{ipv4Addr}, #{ipv4Result},
-      #{ipv4Domains}, #{ipv6Addr}, #{ipv6Result}, #{ipv6Domains}
+      #{ipv4Domains}, #{ipv6Addr}, #{ipv6Result}, #{ipv6Domains}, #{domainsJSON}
     `,
-    'WebhookRequestBodyHelp': 'If RequestBody is empty, it is a GET request, otherwise it is a POST request. Supported variables are the same as above',
+    'WebhookRequestBodyHelp': 'When the method is Auto, it is a GET request if RequestBody is empty, otherwise a POST request. If another method is selected and RequestBody is empty, #{domainsJSON} is sent. Supported variables are the same as above',
+    'Method': 'Method',
+    'Trigger': 'Trigger',
+    'On change': 'On change',
+    'On failure': 'On failure',
+    'Always': 'Always',
+    'EmailHelp': 'Send an email when the IP changes or an update fails. From defaults to the username, separate multiple recipients in To with commas. Leave the host blank to disable',
+    'EmailTLSHelp': 'Use TLS (SSL) connection, default port 465. Otherwise STARTTLS is used, default port 587',
+    'SlackHelp': 'Slack incoming webhook URL, the message summarizes the update and each changed domain is an attachment, green for success and red for failure. Leave it blank to disable',
+    'SlackChannelHelp': 'Optional, overrides the default channel of the webhook, such as #ddns',
+    'Channel': 'Channel',
+    'DiscordHelp': 'Discord channel webhook URL, each domain is sent as an embed, green for success and red for failure. Leave it blank to disable',
+    'BarkHelp': 'Bark push URL with your key, such as https://api.day.app/yourkey, self-hosted servers are supported. Sound and Group are optional. Leave it blank to disable',
+    'TelegramHelp': 'Create a bot with @BotFather to get the Bot Token, the Chat ID can be a user, group or channel ID. Leave it blank to disable',
     'WebhookHeadersHelp': 'One header per line, such as: Authorization: Bearer API_KEY',
     'Try it': 'Try it',
     'Clear': 'Clear',
     'OK': 'OK',
-    "Ipv4UrlHelp": "https://api.ipify.org, https://myip.ipip.net, https://ddns.oray.com/checkip, https://ip.3322.net",
-    "Ipv6UrlHelp": "https://speed.neu6.edu.cn/getIP.php, https://v6.ident.me, https://6.ipw.cn",
+    "Ipv4UrlHelp": "https://cloudflare.com/cdn-cgi/trace, https://api.ipify.org, https://myip.ipip.net, https://ddns.oray.com/checkip, https://ip.3322.net<br />The ip= line is used for Cloudflare <code>/cdn-cgi/trace</code><br />Query a DNS server: dns://resolver1.opendns.com/myip.opendns.com, dns+tcp:// uses TCP, append ?type=TXT for TXT records such as dns://ns1.google.com/o-o.myaddr.l.google.com?type=TXT",
+    "Ipv6UrlHelp": "https://[2606:4700:4700::1111]/cdn-cgi/trace, https://speed.neu6.edu.cn/getIP.php, https://v6.ident.me, https://6.ipw.cn<br />Query a DNS server: dns://ns1.google.com/o-o.myaddr.l.google.com?type=TXT",
+    "Command": "Command",
+    "hookCommandHelp": "Optional. Run after each domain is updated successfully, not run when nothing changed or failed. The result is passed by the environment variables <code>DDNS_DOMAIN</code> <code>DDNS_RECORD_TYPE</code> <code>DDNS_OLD_IP</code> <code>DDNS_NEW_IP</code>. Timeout is 30 seconds",
+    "Test connection": "Test connection",
+    "dnsTestHelp": "Only calls a read-only API to check the credentials, no record will be modified",
+    "History": "History",
+    "Time": "Time",
+    "Domain": "Domain",
+    "Type": "Type",
+    "Old IP": "Old IP",
+    "New IP": "New IP",
+    "Status": "Status",
+    "historyHelp": "The latest 20 records of updated or failed domains, at most 200 records are saved in <code>.ddns_go_history.json</code> next to the config file. Also available at <code>/api/status</code>",
+    "urlAuthHelp": "Optional. Request headers for the API, one <code>Key: Value</code> per line, such as <code>Authorization: Bearer xxx</code>. Fill in the username/password to use Basic authentication",
+    "urlRegHelp": "Optional. A regex applied to the response, the first capture group is used, such as: <code>\"ip\":\"([^\"]+)\"</code>. Leave it blank to use the first IP in the response",
     "Ipv4NetInterfaceHelp": "Get IPv4 address through network card",
     "Ipv6NetInterfaceHelp": "If you do not specify a matching regular expression, the first IPv6 address will be used by default",
     "Ipv4CmdHelp": "Get IPv4 through command, only use the first matching IPv4 address of standard output(stdout). Such as: ip -4 addr show eth1",
@@ -234,6 +443,7 @@ const I18N_MAP = {
   'zh-cn': {
     'Logs': '日志',
     'Save': '保存',
+    'Update Now': '立即更新',
     'Config:': '配置切换:',
     'Add': '添加',
     'Rename': '重命名',
@@ -250,17 +460,50 @@ const I18N_MAP = {
     '10m': '10分钟',
     '30m': '30分钟',
     '1h': '1小时',
-    'ttlHelp': '如账号支持更小的 TTL, 可修改。IP 有变化时才会更新TTL',
+    'ttlHelp': '如账号支持更小的 TTL, 可修改。IP 有变化时才会更新TTL。Cloudflare 的自动和1s都表示自动。小于服务商允许的最小值时(如 Porkbun/GoDaddy 为600)会调整为最小值',
+    'zoneIdHelp': '可选。令牌仅有单个区域权限时需填写, 填写后将不再通过根域名查询区域',
+    'Proxied': '开启代理',
+    'proxiedHelp': '通过 Cloudflare 代理流量(橙色云朵), 仅适用于 HTTP(S) 服务。SSH、游戏服务器等域名可在后面加上 <code>?nonWeb=true</code> 总是仅使用DNS',
+    'Auth Mode': '认证方式',
+    'Comment': '备注',
+    'Tags': '标签',
+    'commentTagsHelp': '可选。多个标签用英文逗号分隔, 如: owner:ddns-go。更新时会保留记录上已有的备注和标签',
+    'cnameDomainsHelp': '一行一个, 域名和 CNAME 目标用空格分隔, 如: www.example.com home.example.net。不需要获取IP',
+    'mxDomainsHelp': '一行一个, 域名、优先级和邮件服务器用空格分隔, 如: example.com 10 mail.example.com。不需要获取IP',
+    'Update URL': '更新地址',
+    'updateUrlHelp': 'DynDNS2: 服务商的更新地址, 默认为 No-IP: https://dynupdate.no-ip.com/nic/update。OVH: ovh-eu (默认)、ovh-ca、ovh-us 或接口地址',
+    'Clean Duplicates': '清理重复记录',
+    'Update Only': '只更新不新增',
+    'Delete On No IP': '无IP时删除',
+    'Allow private IP': '允许内网IP',
+    'Suffix': '后缀',
+    'Prefer temporary': '优先临时地址',
+    'preferTemporaryHelp': '默认跳过已弃用的地址并优先使用稳定地址, 勾选后优先使用临时(隐私)地址。仅支持Linux',
+    'ipv6SuffixHelp': '可选。固定的接口ID, 如 <code>::1234</code>, 与获得的IPv6的/64前缀组合后解析, 适用于前缀会变化的局域网设备',
+    'allowPrivateHelp': '默认不会更新内网、回环、链路本地及CGNAT(100.64.0.0/10)地址, 如需解析到局域网地址请勾选',
+    'Notify Cooldown': '通知冷却',
+    'notifyCooldownHelp': '分钟。通知后同一域名在此时间内的变化不再通知, 结束后通知最新的结果。更新失败总是通知。为空不限制',
+    'enabledHelp': '禁用后更新时将跳过该配置, 但保留其设置和认证信息',
+    'cleanDuplicatesHelp': '删除名称和类型相同的其它记录, 只保留最新的一条。使用轮询或手动固定的记录时请勿开启',
+    'updateOnlyHelp': '只更新已存在的记录, 未找到记录的域名不会新增并标记为失败。也可在单个域名后添加 <code>?updateOnly=true</code> 开启',
+    'deleteOnNoIPHelp': '连续3次未能获取IPv4/IPv6地址时删除A/AAAA记录, 避免客户端访问过期的记录超时。再次获取到地址后会重新新增记录',
+    'HTTP Timeout': '请求超时',
+    'httpTimeoutHelp': '请求DNS服务商的超时时间(秒), 留空默认30秒',
+    'Interval': '同步间隔',
+    'intervalHelp': '当前DNS配置的同步间隔时间(秒), 留空使用启动参数 <code>-f</code> 的值',
+    'DoH': 'DoH',
+    'dohHelp': '可选。先通过该 DNS-over-HTTPS 服务查询当前的A/AAAA记录, 与IP一致时不再请求DNS服务商。留空不启用',
     'Enabled': '是否启用',
     'Get IP method': '获取 IP 方式',
     'By api': '通过接口获取',
     'By network card': '通过网卡获取',
     'By command': '通过命令获取',
     'domainsHelp': `
-      每行一个域名。
+      每行一个域名, 都将更新为上面获取到的IP。
       如果域名不可注册，请使用冒号手动将其分为子域名和根域名。如 <code>www:domain.example.com</code><br />
 
-      支持<a target="blank" href="https://github.com/jeessy2/ddns-go/wiki/传递自定义参数">自定义参数</a>
+      支持<a target="blank" href="https://github.com/jeessy2/ddns-go/wiki/传递自定义参数">自定义参数</a>。
+      Cloudflare 支持使用 <code>?ttl=300</code> 和 <code>?proxied=false</code> 单独设置该记录的TTL和代理状态, <code>?nonWeb=true</code> 使SSH等非Web服务总是仅使用DNS
     `,
     'Regular exp.': '匹配正则表达式',
     'regHelp': '可使用 @1 指定第一个IPv6地址, @2 指定第二个IPv6地址... 也可使用正则表达式匹配指定的IPv6地址, 留空则不启用',
@@ -274,15 +517,42 @@ const I18N_MAP = {
     'WebhookURLHelp': `
       <a target="blank" href="https://github.com/jeessy2/ddns-go#webhook">点击参考官方 Webhook 说明</a>
       <br />
-      支持的变量 #{ipv4Addr}, #{ipv4Result}, #{ipv4Domains}, #{ipv6Addr}, #{ipv6Result}, #{ipv6Domains}
+      支持的变量 #{ipv4Addr}, #{ipv4Result}, #{ipv4Domains}, #{ipv6Addr}, #{ipv6Result}, #{ipv6Domains}, #{domainsJSON}
     `,
-    'WebhookRequestBodyHelp': '如果 RequestBody 为空, 则为 GET 请求, 否则为 POST 请求。支持的变量同上',
+    'WebhookRequestBodyHelp': '请求方法为自动时, 如果 RequestBody 为空, 则为 GET 请求, 否则为 POST 请求。选择其它方法且 RequestBody 为空时, 发送 #{domainsJSON}。支持的变量同上',
+    'Method': '请求方法',
+    'Trigger': '触发条件',
+    'On change': '更新成功或失败时',
+    'On failure': '仅更新失败时',
+    'Always': '每次运行',
+    'EmailHelp': 'IP变化或更新失败时发送邮件。发件人为空时使用用户名, 多个收件人用英文逗号分隔。SMTP Host 留空不启用',
+    'EmailTLSHelp': '使用TLS(SSL)连接, 默认端口465。否则使用STARTTLS, 默认端口587',
+    'SlackHelp': 'Slack Incoming Webhook 地址, 消息为本次更新的统计, 每个有变化的域名为一个 attachment, 成功为绿色, 失败为红色。留空不启用',
+    'SlackChannelHelp': '可选, 覆盖 Webhook 默认的频道, 如 #ddns',
+    'Channel': '频道',
+    'DiscordHelp': 'Discord 频道的 Webhook 地址, 每个域名为一个 embed, 成功为绿色, 失败为红色。留空不启用',
+    'BarkHelp': '包含Key的Bark推送地址, 如 https://api.day.app/yourkey, 支持自建服务器。Sound 和 Group 可不填。留空不启用',
+    'TelegramHelp': '通过 @BotFather 创建机器人获得 Bot Token, Chat ID 可以是用户、群组或频道的ID。留空不启用',
     'WebhookHeadersHelp': '一行一个Header, 如: Authorization: Bearer API_KEY',
     'Try it': '模拟测试Webhook',
     'Clear': '清空',
     'OK': '确定',
-    "Ipv4UrlHelp": "https://myip.ipip.net, https://ddns.oray.com/checkip, https://ip.3322.net",
-    "Ipv6UrlHelp": "https://speed.neu6.edu.cn/getIP.php, https://v6.ident.me, https://6.ipw.cn",
+    "Ipv4UrlHelp": "https://myip.ipip.net, https://ddns.oray.com/checkip, https://ip.3322.net, https://cloudflare.com/cdn-cgi/trace<br />Cloudflare <code>/cdn-cgi/trace</code> 使用返回值中 ip= 的值<br />通过DNS查询: dns://resolver1.opendns.com/myip.opendns.com, dns+tcp:// 使用TCP, 查询TXT记录时添加 ?type=TXT, 如 dns://ns1.google.com/o-o.myaddr.l.google.com?type=TXT",
+    "Ipv6UrlHelp": "https://speed.neu6.edu.cn/getIP.php, https://v6.ident.me, https://6.ipw.cn, https://[2606:4700:4700::1111]/cdn-cgi/trace<br />通过DNS查询: dns://ns1.google.com/o-o.myaddr.l.google.com?type=TXT",
+    "Command": "命令",
+    "hookCommandHelp": "可选。每个域名更新成功后运行, 未改变或失败时不运行。通过环境变量 <code>DDNS_DOMAIN</code> <code>DDNS_RECORD_TYPE</code> <code>DDNS_OLD_IP</code> <code>DDNS_NEW_IP</code> 获得更新结果。超时时间为30秒",
+    "Test connection": "测试连接",
+    "dnsTestHelp": "只调用查询接口检查认证信息是否正确, 不会修改任何记录",
+    "History": "历史记录",
+    "Time": "时间",
+    "Domain": "域名",
+    "Type": "类型",
+    "Old IP": "旧IP",
+    "New IP": "新IP",
+    "Status": "状态",
+    "historyHelp": "最近 20 条更新成功或失败的记录, 最多保存 200 条在配置文件同目录的 <code>.ddns_go_history.json</code> 中。也可通过 <code>/api/status</code> 获取",
+    "urlAuthHelp": "可选。请求接口时的请求头, 每行一个 <code>Key: Value</code>, 如 <code>Authorization: Bearer xxx</code>。填写用户名/密码则使用Basic认证",
+    "urlRegHelp": "可选。对返回内容进行匹配的正则表达式, 使用第一个捕获组, 如: <code>\"ip\":\"([^\"]+)\"</code>。留空则使用返回内容中的第一个IP",
     "Ipv4NetInterfaceHelp": "通过网卡获取IPv4",
     "Ipv6NetInterfaceHelp": "如不指定匹配正则表达式，将默认使用第一个 IPv6 地址",
     "Ipv4CmdHelp": `
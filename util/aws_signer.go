@@ -0,0 +1,86 @@
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const awsSigV4Algorithm = "AWS4-HMAC-SHA256"
+
+func awsHmacsha256(key []byte, s string) []byte {
+	hashed := hmac.New(sha256.New, key)
+	hashed.Write([]byte(s))
+	return hashed.Sum(nil)
+}
+
+// AwsSigV4Signer AWS 签名方法 v4, 可用于 Route53 等 AWS 风格的接口
+// https://docs.aws.amazon.com/IAM/latest/UserGuide/create-signed-request.html
+func AwsSigV4Signer(accessKeyID, secretAccessKey, region, service string, r *http.Request, payload []byte) {
+	awsSigV4Sign(accessKeyID, secretAccessKey, region, service, r, payload, time.Now())
+}
+
+func awsSigV4Sign(accessKeyID, secretAccessKey, region, service string, r *http.Request, payload []byte, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := now.UTC().Format("20060102")
+	r.Header.Set("X-Amz-Date", amzDate)
+
+	// step 1: build canonical request string
+	// 只签名 host 和 x-amz-* 请求头
+	headers := map[string]string{"host": r.URL.Host}
+	for k, v := range r.Header {
+		lk := strings.ToLower(k)
+		if strings.HasPrefix(lk, "x-amz-") {
+			headers[lk] = strings.TrimSpace(strings.Join(v, ","))
+		}
+	}
+	headerNames := make([]string, 0, len(headers))
+	for k := range headers {
+		headerNames = append(headerNames, k)
+	}
+	sort.Strings(headerNames)
+
+	var canonicalHeaders strings.Builder
+	for _, k := range headerNames {
+		canonicalHeaders.WriteString(WriteString(k, ":", headers[k], "\n"))
+	}
+	signedHeaders := strings.Join(headerNames, ";")
+
+	canonicalURI := r.URL.EscapedPath()
+	if canonicalURI == "" {
+		canonicalURI = "/"
+	}
+	canonicalRequest := WriteString(
+		r.Method, "\n",
+		canonicalURI, "\n",
+		awsCanonicalQuery(r.URL.Query()), "\n",
+		canonicalHeaders.String(), "\n",
+		signedHeaders, "\n",
+		sha256hex(string(payload)),
+	)
+
+	// step 2: build string to sign
+	credentialScope := WriteString(date, "/", region, "/", service, "/aws4_request")
+	string2sign := WriteString(awsSigV4Algorithm, "\n", amzDate, "\n", credentialScope, "\n", sha256hex(canonicalRequest))
+
+	// step 3: sign string
+	secretDate := awsHmacsha256([]byte("AWS4"+secretAccessKey), date)
+	secretRegion := awsHmacsha256(secretDate, region)
+	secretService := awsHmacsha256(secretRegion, service)
+	secretSigning := awsHmacsha256(secretService, "aws4_request")
+	signature := hex.EncodeToString(awsHmacsha256(secretSigning, string2sign))
+
+	// step 4: build authorization
+	r.Header.Set("Authorization", WriteString(awsSigV4Algorithm, " Credential=", accessKeyID, "/", credentialScope,
+		", SignedHeaders=", signedHeaders, ", Signature=", signature))
+}
+
+// awsCanonicalQuery 按参数名排序, 空格编码为 %20
+func awsCanonicalQuery(query url.Values) string {
+	return strings.ReplaceAll(query.Encode(), "+", "%20")
+}
@@ -0,0 +1,20 @@
+package util
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestAwsSigV4Sign 使用 AWS 官方测试用例 get-vanilla 测试签名
+func TestAwsSigV4Sign(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+	awsSigV4Sign("AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "us-east-1", "service", req, nil, now)
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("期待 %s, 得到 %s", want, got)
+	}
+}
@@ -0,0 +1,77 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// 通过DNS查询IP的超时时间
+const dnsIPTimeout = 10 * time.Second
+
+// IsDNSIPURL 是否为通过DNS查询IP的地址, 如 dns://resolver1.opendns.com/myip.opendns.com
+func IsDNSIPURL(rawURL string) bool {
+	return strings.HasPrefix(rawURL, "dns://") || strings.HasPrefix(rawURL, "dns+tcp://")
+}
+
+// LookupIPFromDNS 向指定的DNS服务器查询域名, 获得DNS服务器看到的本机IP
+// 格式为 dns://服务器[:端口]/域名, dns:// 使用UDP, dns+tcp:// 使用TCP
+// 域名返回TXT记录时添加 ?type=TXT, 如 dns+tcp://ns1.google.com/o-o.myaddr.l.google.com?type=TXT
+// 通过IPv4或IPv6连接DNS服务器, 以获得对应类型的IP
+func LookupIPFromDNS(rawURL string, ipv6 bool) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	name := strings.Trim(u.Path, "/")
+	if u.Hostname() == "" || name == "" {
+		return "", fmt.Errorf("invalid dns address: %s", rawURL)
+	}
+	server := u.Host
+	if u.Port() == "" {
+		server = net.JoinHostPort(u.Hostname(), "53")
+	}
+
+	network := "udp"
+	if u.Scheme == "dns+tcp" {
+		network = "tcp"
+	}
+	ipNetwork := "ip4"
+	if ipv6 {
+		network += "6"
+		ipNetwork = "ip6"
+	} else {
+		network += "4"
+	}
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, server)
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dnsIPTimeout)
+	defer cancel()
+	if strings.EqualFold(u.Query().Get("type"), "TXT") {
+		txts, err := resolver.LookupTXT(ctx, name)
+		if err != nil {
+			return "", err
+		}
+		for _, txt := range txts {
+			if ip := net.ParseIP(strings.TrimSpace(txt)); ip != nil && (ip.To4() == nil) == ipv6 {
+				return ip.String(), nil
+			}
+		}
+		return "", fmt.Errorf("no ip in txt records: %v", txts)
+	}
+
+	ips, err := resolver.LookupIP(ctx, ipNetwork, name)
+	if err != nil {
+		return "", err
+	}
+	return ips[0].String(), nil
+}
@@ -0,0 +1,59 @@
+package util
+
+import (
+	"net"
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// TestLookupIPFromDNS 测试通过DNS服务器查询A和TXT记录获得IP
+func TestLookupIPFromDNS(t *testing.T) {
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer conn.Close()
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var msg dnsmessage.Message
+			if err := msg.Unpack(buf[:n]); err != nil || len(msg.Questions) == 0 {
+				continue
+			}
+			q := msg.Questions[0]
+			msg.Response = true
+			switch {
+			case q.Type == dnsmessage.TypeA && q.Name.String() == "myip.opendns.com.":
+				msg.Answers = []dnsmessage.Resource{{
+					Header: dnsmessage.ResourceHeader{Name: q.Name, Type: q.Type, Class: q.Class},
+					Body:   &dnsmessage.AResource{A: [4]byte{1, 2, 3, 4}},
+				}}
+			case q.Type == dnsmessage.TypeTXT:
+				msg.Answers = []dnsmessage.Resource{{
+					Header: dnsmessage.ResourceHeader{Name: q.Name, Type: q.Type, Class: q.Class},
+					Body:   &dnsmessage.TXTResource{TXT: []string{"5.6.7.8"}},
+				}}
+			default:
+				msg.RCode = dnsmessage.RCodeNameError
+			}
+			resp, _ := msg.Pack()
+			conn.WriteTo(resp, addr)
+		}
+	}()
+	server := conn.LocalAddr().String()
+
+	if ip, err := LookupIPFromDNS("dns://"+server+"/myip.opendns.com", false); err != nil || ip != "1.2.3.4" {
+		t.Errorf("期待 1.2.3.4, 得到 %q %v", ip, err)
+	}
+	if ip, err := LookupIPFromDNS("dns://"+server+"/o-o.myaddr.l.google.com?type=TXT", false); err != nil || ip != "5.6.7.8" {
+		t.Errorf("期待 5.6.7.8, 得到 %q %v", ip, err)
+	}
+	if _, err := LookupIPFromDNS("dns://"+server, false); err == nil {
+		t.Error("缺少域名时应返回错误")
+	}
+}
@@ -0,0 +1,79 @@
+package util
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// LookupDoH 通过DoH(RFC 8484)查询域名的A/AAAA记录
+func LookupDoH(client *http.Client, dohURL, name, recordType string) (addrs []string, err error) {
+	qType := dnsmessage.TypeA
+	if recordType == "AAAA" {
+		qType = dnsmessage.TypeAAAA
+	}
+	qName, err := dnsmessage.NewName(strings.TrimSuffix(name, ".") + ".")
+	if err != nil {
+		return nil, err
+	}
+
+	msg := dnsmessage.Message{
+		Header:    dnsmessage.Header{RecursionDesired: true},
+		Questions: []dnsmessage.Question{{Name: qName, Type: qType, Class: dnsmessage.ClassINET}},
+	}
+	query, err := msg.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	sep := "?"
+	if strings.Contains(dohURL, "?") {
+		sep = "&"
+	}
+	req, err := http.NewRequest(http.MethodGet, dohURL+sep+"dns="+base64.RawURLEncoding.EncodeToString(query), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/dns-message")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status code: %d", resp.StatusCode)
+	}
+
+	var answer dnsmessage.Message
+	if err = answer.Unpack(body); err != nil {
+		return nil, err
+	}
+	if answer.RCode != dnsmessage.RCodeSuccess && answer.RCode != dnsmessage.RCodeNameError {
+		return nil, fmt.Errorf("rcode: %s", answer.RCode)
+	}
+
+	// 只取A/AAAA记录, 忽略CNAME
+	for _, rr := range answer.Answers {
+		switch r := rr.Body.(type) {
+		case *dnsmessage.AResource:
+			if qType == dnsmessage.TypeA {
+				addrs = append(addrs, net.IP(r.A[:]).String())
+			}
+		case *dnsmessage.AAAAResource:
+			if qType == dnsmessage.TypeAAAA {
+				addrs = append(addrs, net.IP(r.AAAA[:]).String())
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,57 @@
+package util
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// TestLookupDoH 测试DoH查询及解析A记录
+func TestLookupDoH(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query, err := base64.RawURLEncoding.DecodeString(r.URL.Query().Get("dns"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var msg dnsmessage.Message
+		if err := msg.Unpack(query); err != nil {
+			t.Fatal(err)
+		}
+		q := msg.Questions[0]
+		if q.Name.String() != "www.example.com." || q.Type != dnsmessage.TypeA {
+			t.Errorf("查询不正确: %s %s", q.Name, q.Type)
+		}
+
+		msg.Response = true
+		target, _ := dnsmessage.NewName("example.com.")
+		msg.Answers = []dnsmessage.Resource{
+			{
+				Header: dnsmessage.ResourceHeader{Name: q.Name, Type: dnsmessage.TypeCNAME, Class: dnsmessage.ClassINET},
+				Body:   &dnsmessage.CNAMEResource{CNAME: target},
+			},
+			{
+				Header: dnsmessage.ResourceHeader{Name: target, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET},
+				Body:   &dnsmessage.AResource{A: [4]byte{1, 2, 3, 4}},
+			},
+		}
+		resp, err := msg.Pack()
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Header().Set("Content-Type", "application/dns-message")
+		w.Write(resp)
+	}))
+	defer srv.Close()
+
+	addrs, err := LookupDoH(srv.Client(), srv.URL, "www.example.com", "A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"1.2.3.4"}; !reflect.DeepEqual(addrs, want) {
+		t.Errorf("期待 %v, 得到 %v", want, addrs)
+	}
+}
@@ -0,0 +1,157 @@
+package util
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const googleTokenURI = "https://oauth2.googleapis.com/token"
+
+// GoogleServiceAccountKey 服务账号密钥文件
+type GoogleServiceAccountKey struct {
+	Type         string `json:"type"`
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+type googleToken struct {
+	accessToken string
+	expiresAt   time.Time
+}
+
+// 访问令牌按服务账号和 scope 缓存到过期前
+var (
+	googleTokens   = map[string]googleToken{}
+	googleTokensMu sync.Mutex
+)
+
+// ParseGoogleServiceAccountKey 解析服务账号 JSON 密钥
+func ParseGoogleServiceAccountKey(keyJSON string) (key GoogleServiceAccountKey, err error) {
+	err = json.Unmarshal([]byte(keyJSON), &key)
+	if err != nil {
+		return
+	}
+	if key.ClientEmail == "" || key.PrivateKey == "" {
+		err = errors.New(LogStr("服务账号密钥缺少 client_email 或 private_key"))
+		return
+	}
+	if key.TokenURI == "" {
+		key.TokenURI = googleTokenURI
+	}
+	return
+}
+
+// GoogleServiceAccountToken 使用服务账号签名 JWT 换取访问令牌, 过期前复用
+// https://developers.google.com/identity/protocols/oauth2/service-account#httprest
+func GoogleServiceAccountToken(ctx context.Context, client *http.Client, key GoogleServiceAccountKey, scope string) (string, error) {
+	cacheKey := key.ClientEmail + " " + key.PrivateKeyID + " " + scope
+
+	googleTokensMu.Lock()
+	defer googleTokensMu.Unlock()
+
+	// 提前1分钟刷新
+	if token, ok := googleTokens[cacheKey]; ok && time.Now().Add(time.Minute).Before(token.expiresAt) {
+		return token.accessToken, nil
+	}
+
+	assertion, err := googleSignJWT(key, scope, time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{}
+	params.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	params.Set("assertion", assertion)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, key.TokenURI, strings.NewReader(params.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	resp, err := client.Do(req)
+	err = GetHTTPResponse(resp, err, &result)
+	if err != nil {
+		return "", err
+	}
+
+	googleTokens[cacheKey] = googleToken{
+		accessToken: result.AccessToken,
+		expiresAt:   time.Now().Add(time.Duration(result.ExpiresIn) * time.Second),
+	}
+	return result.AccessToken, nil
+}
+
+// googleSignJWT 生成 RS256 签名的 JWT, 有效期1小时
+func googleSignJWT(key GoogleServiceAccountKey, scope string, now time.Time) (string, error) {
+	privateKey, err := parseRSAPrivateKey(key.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+
+	header, _ := json.Marshal(map[string]string{
+		"alg": "RS256",
+		"typ": "JWT",
+		"kid": key.PrivateKeyID,
+	})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iss":   key.ClientEmail,
+		"scope": scope,
+		"aud":   key.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+
+	unsigned := WriteString(
+		base64.RawURLEncoding.EncodeToString(header), ".",
+		base64.RawURLEncoding.EncodeToString(claims),
+	)
+	hashed := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, hashed[:])
+	if err != nil {
+		return "", err
+	}
+
+	return WriteString(unsigned, ".", base64.RawURLEncoding.EncodeToString(signature)), nil
+}
+
+// parseRSAPrivateKey 解析 PEM 格式的 PKCS#8 或 PKCS#1 私钥
+func parseRSAPrivateKey(pemKey string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(pemKey))
+	if block == nil {
+		return nil, errors.New(LogStr("私钥格式不正确"))
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New(LogStr("私钥格式不正确"))
+	}
+	return key, nil
+}
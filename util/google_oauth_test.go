@@ -0,0 +1,53 @@
+package util
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestGoogleSignJWT 测试 JWT 的内容和签名
+func TestGoogleSignJWT(t *testing.T) {
+	privateKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	pkcs8, _ := x509.MarshalPKCS8PrivateKey(privateKey)
+	key := GoogleServiceAccountKey{
+		PrivateKeyID: "kid",
+		PrivateKey:   string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8})),
+		ClientEmail:  "ddns@project.iam.gserviceaccount.com",
+		TokenURI:     googleTokenURI,
+	}
+
+	now := time.Unix(1700000000, 0)
+	jwt, err := googleSignJWT(key, "https://www.googleapis.com/auth/ndev.clouddns.readwrite", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		t.Fatalf("期待 3 段, 得到 %d 段", len(parts))
+	}
+
+	claimsJSON, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	var claims map[string]interface{}
+	if err := json.Unmarshal(claimsJSON, &claims); err != nil {
+		t.Fatal(err)
+	}
+	if claims["iss"] != key.ClientEmail || claims["aud"] != googleTokenURI || claims["exp"] != float64(1700003600) {
+		t.Errorf("claims 不正确: %s", claimsJSON)
+	}
+
+	signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	hashed := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&privateKey.PublicKey, crypto.SHA256, hashed[:], signature); err != nil {
+		t.Errorf("签名验证失败: %s", err)
+	}
+}
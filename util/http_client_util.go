@@ -3,8 +3,10 @@ package util
 import (
 	"context"
 	"crypto/tls"
+	"fmt"
 	"net"
 	"net/http"
+	"net/url"
 	"time"
 )
 
@@ -24,13 +26,21 @@ var defaultTransport = &http.Transport{
 	IdleConnTimeout:       90 * time.Second,
 	TLSHandshakeTimeout:   10 * time.Second,
 	ExpectContinueTimeout: 1 * time.Second,
+	// 同一DNS服务商的请求复用空闲连接, 避免每次请求都重新进行TLS握手
+	MaxIdleConnsPerHost: 10,
 }
 
 // CreateHTTPClient Create Default HTTP Client
 func CreateHTTPClient() *http.Client {
+	return CreateHTTPClientTimeout(30 * time.Second)
+}
+
+// CreateHTTPClientTimeout Create Default HTTP Client with the given timeout
+// 网络异常或返回5xx/429时自动重试, timeout 包含重试的时间
+func CreateHTTPClientTimeout(timeout time.Duration) *http.Client {
 	return &http.Client{
-		Timeout:   30 * time.Second,
-		Transport: defaultTransport,
+		Timeout:   timeout,
+		Transport: &retryTransport{next: defaultTransport},
 	}
 }
 
@@ -89,3 +99,25 @@ func SetInsecureSkipVerify() {
 		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
 	}
 }
+
+// SetProxy 所有请求(包括获取IP)都使用代理, 支持 http/https/socks5
+func SetProxy(proxyURL string) error {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return err
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return fmt.Errorf(LogStr("不支持的代理协议: %s", u.Scheme))
+	}
+	if u.Host == "" {
+		return fmt.Errorf(LogStr("代理地址不正确: %s", proxyURL))
+	}
+
+	transports := []*http.Transport{defaultTransport, noProxyTcp4Transport, noProxyTcp6Transport}
+	for _, transport := range transports {
+		transport.Proxy = http.ProxyURL(u)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package util
+
+import (
+	"net/http"
+	"testing"
+)
+
+// TestSetProxy 测试代理协议校验及所有 http.Transport 都使用代理
+func TestSetProxy(t *testing.T) {
+	transports := []*http.Transport{defaultTransport, noProxyTcp4Transport, noProxyTcp6Transport}
+	defer func() {
+		defaultTransport.Proxy = http.ProxyFromEnvironment
+		noProxyTcp4Transport.Proxy = nil
+		noProxyTcp6Transport.Proxy = nil
+	}()
+
+	for _, invalid := range []string{"ftp://127.0.0.1:21", "socks5://", "127.0.0.1:1080"} {
+		if err := SetProxy(invalid); err == nil {
+			t.Errorf("%s 应该返回错误", invalid)
+		}
+	}
+
+	if err := SetProxy("socks5://127.0.0.1:1080"); err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	for _, transport := range transports {
+		u, err := transport.Proxy(req)
+		if err != nil || u == nil || u.String() != "socks5://127.0.0.1:1080" {
+			t.Errorf("期待 socks5://127.0.0.1:1080, 得到 %v %v", u, err)
+		}
+	}
+}
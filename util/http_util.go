@@ -1,12 +1,31 @@
 package util
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
 	"io"
 	"net/http"
+	"strings"
 )
 
+// maxErrorBodyLen 错误信息中返回内容的最大长度
+const maxErrorBodyLen = 512
+
+// HTTPStatusError 返回状态码不是2xx, Body 为完整的返回内容
+type HTTPStatusError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *HTTPStatusError) Error() string {
+	body := strings.TrimSpace(string(e.Body))
+	if len(body) > maxErrorBodyLen {
+		body = strings.ToValidUTF8(body[:maxErrorBodyLen], "") + "..."
+	}
+	return LogStr("返回内容: %s ,返回状态码: %d", body, e.StatusCode)
+}
+
 // GetHTTPResponse 处理HTTP结果，返回序列化的json
 func GetHTTPResponse(resp *http.Response, err error, result interface{}) error {
 	body, err := GetHTTPResponseOrg(resp, err)
@@ -38,8 +57,41 @@ func GetHTTPResponseOrg(resp *http.Response, err error) ([]byte, error) {
 
 	// 300及以上状态码都算异常
 	if resp.StatusCode >= 300 {
-		err = fmt.Errorf(LogStr("返回内容: %s ,返回状态码: %d", string(body), resp.StatusCode))
+		err = &HTTPStatusError{StatusCode: resp.StatusCode, Body: body}
 	}
 
 	return body, err
 }
+
+// NewJSONRequest 创建请求, body不为nil时序列化为json
+func NewJSONRequest(ctx context.Context, method, url string, body interface{}) (*http.Request, error) {
+	var reader io.Reader = http.NoBody
+	if body != nil {
+		byt, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(byt)
+	}
+
+	return http.NewRequestWithContext(ctx, method, url, reader)
+}
+
+// ParseJSONResponse 读取返回内容并反序列化json
+// 状态码不是2xx时不反序列化, 返回 *HTTPStatusError
+func ParseJSONResponse(resp *http.Response, result interface{}) error {
+	lr := io.LimitReader(resp.Body, 1024000)
+	byt, err := io.ReadAll(lr)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &HTTPStatusError{StatusCode: resp.StatusCode, Body: byt}
+	}
+
+	if len(byt) == 0 {
+		return nil
+	}
+	return json.Unmarshal(byt, result)
+}
@@ -0,0 +1,39 @@
+package util
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestParseJSONResponse 测试状态码不是2xx时返回状态码和截断的返回内容
+func TestParseJSONResponse(t *testing.T) {
+	newResp := func(status int, body string) *http.Response {
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}
+	}
+
+	var result struct {
+		ID string `json:"id"`
+	}
+	if err := ParseJSONResponse(newResp(http.StatusOK, `{"id":"1"}`), &result); err != nil || result.ID != "1" {
+		t.Fatalf("期待 1, 得到 %q %v", result.ID, err)
+	}
+	if err := ParseJSONResponse(newResp(http.StatusNoContent, ""), &result); err != nil {
+		t.Errorf("期待 nil, 得到 %v", err)
+	}
+
+	body := "<html>" + strings.Repeat("x", 2*maxErrorBodyLen) + "</html>"
+	err := ParseJSONResponse(newResp(http.StatusBadGateway, body), &result)
+	var statusErr *HTTPStatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("期待 *HTTPStatusError, 得到 %v", err)
+	}
+	if statusErr.StatusCode != http.StatusBadGateway || string(statusErr.Body) != body {
+		t.Errorf("期待完整的返回内容和状态码 502, 得到 %d", statusErr.StatusCode)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "502") || !strings.Contains(msg, "<html>") || len(msg) > 2*maxErrorBodyLen {
+		t.Errorf("期待包含状态码和截断的返回内容, 得到 %s", msg)
+	}
+}
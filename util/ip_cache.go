@@ -10,7 +10,7 @@ const IPCacheTimesENV = "DDNS_IP_CACHE_TIMES"
 // IpCache 上次IP缓存
 type IpCache struct {
 	Addr          string // 缓存地址
-	Times         int    // 剩余次数
+	Times         int    // 剩余次数, 小于0时只在地址改变时比对
 	TimesFailedIP int    // 获取ip失败的次数
 }
 
@@ -21,16 +21,22 @@ func (d *IpCache) Check(newAddr string) bool {
 		return true
 	}
 	// 地址改变 或 达到剩余次数
-	if d.Addr != newAddr || d.Times <= 1 {
+	if d.Addr != newAddr || (d.Times >= 0 && d.Times <= 1) {
 		IPCacheTimes, err := strconv.Atoi(os.Getenv(IPCacheTimesENV))
 		if err != nil {
 			IPCacheTimes = 5
 		}
 		d.Addr = newAddr
 		d.Times = IPCacheTimes + 1
+		// 小于0时不再定时比对, 启动或更新失败后缓存被重置, 会重新比对
+		if IPCacheTimes < 0 {
+			d.Times = -1
+		}
 		return true
 	}
 	d.Addr = newAddr
-	d.Times--
+	if d.Times > 0 {
+		d.Times--
+	}
 	return false
 }
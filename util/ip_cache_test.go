@@ -0,0 +1,42 @@
+package util
+
+import (
+	"testing"
+)
+
+// TestIpCacheCheck 测试间隔N次比对
+func TestIpCacheCheck(t *testing.T) {
+	t.Setenv(IPCacheTimesENV, "2")
+	cache := &IpCache{}
+	want := []bool{true, false, false, true, false}
+	for i, w := range want {
+		if got := cache.Check("1.2.3.4"); got != w {
+			t.Errorf("第 %d 次期待 %v, 得到 %v", i+1, w, got)
+		}
+	}
+	if !cache.Check("1.2.3.5") {
+		t.Error("地址改变后应比对")
+	}
+}
+
+// TestIpCacheCheckOnlyChanged 测试小于0时只在地址改变时比对
+func TestIpCacheCheckOnlyChanged(t *testing.T) {
+	t.Setenv(IPCacheTimesENV, "-1")
+	cache := &IpCache{}
+	if !cache.Check("1.2.3.4") {
+		t.Error("首次应比对")
+	}
+	for i := 0; i < 10; i++ {
+		if cache.Check("1.2.3.4") {
+			t.Fatalf("第 %d 次地址未改变不应比对", i+2)
+		}
+	}
+	if !cache.Check("1.2.3.5") {
+		t.Error("地址改变后应比对")
+	}
+	// 更新失败后缓存被重置
+	*cache = IpCache{}
+	if !cache.Check("1.2.3.5") {
+		t.Error("缓存重置后应比对")
+	}
+}
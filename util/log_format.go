@@ -0,0 +1,65 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+// LogFielder 作为日志参数时, JSON格式的日志会包含其返回的字段, 如 domain/provider
+type LogFielder interface {
+	LogFields() map[string]string
+}
+
+// 是否输出JSON格式的日志, 默认输出文本
+var logJSON = false
+
+var logLevelStrs = map[int]string{
+	LevelDebug: "debug",
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+}
+
+// SetLogFormat 设置日志格式, 可选 text/json
+// json 时每行为一个JSON对象, 包含 time/level/msg 及参数中的字段, 便于 Loki/ELK 等收集
+func SetLogFormat(format string) error {
+	switch format {
+	case "text":
+		logJSON = false
+		log.SetFlags(log.LstdFlags)
+	case "json":
+		logJSON = true
+		// 时间由JSON中的time字段提供
+		log.SetFlags(0)
+	default:
+		return fmt.Errorf(LogStr("日志格式不正确: %s, 可选 text/json", format))
+	}
+	return nil
+}
+
+// formatLog 按日志格式生成一行日志
+func formatLog(level int, key string, args []interface{}) string {
+	msg := LogStr(key, args...)
+	if !logJSON {
+		return msg
+	}
+
+	entry := map[string]string{
+		"time":  time.Now().Format(time.RFC3339),
+		"level": logLevelStrs[level],
+		"msg":   msg,
+	}
+	for _, arg := range args {
+		if fielder, ok := arg.(LogFielder); ok {
+			for k, v := range fielder.LogFields() {
+				if v != "" {
+					entry[k] = v
+				}
+			}
+		}
+	}
+	byt, _ := json.Marshal(entry)
+	return string(byt)
+}
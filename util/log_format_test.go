@@ -0,0 +1,45 @@
+package util
+
+import (
+	"encoding/json"
+	"log"
+	"testing"
+)
+
+type testFielder string
+
+func (f testFielder) String() string { return string(f) }
+
+func (f testFielder) LogFields() map[string]string {
+	return map[string]string{"domain": string(f), "provider": ""}
+}
+
+// TestFormatLogJSON 测试JSON格式的日志包含级别、消息及参数中的字段
+func TestFormatLogJSON(t *testing.T) {
+	defer SetLogFormat("text")
+	if err := SetLogFormat("xml"); err == nil {
+		t.Error("期待日志格式不正确")
+	}
+	if err := SetLogFormat("json"); err != nil {
+		t.Fatal(err)
+	}
+	if log.Flags() != 0 {
+		t.Error("JSON格式时不应输出日志前缀")
+	}
+
+	var entry map[string]string
+	if err := json.Unmarshal([]byte(formatLog(LevelError, "domain %s failed", []interface{}{testFielder("www.example.com")})), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["level"] != "error" || entry["msg"] != "domain www.example.com failed" || entry["domain"] != "www.example.com" || entry["time"] == "" {
+		t.Errorf("JSON日志不正确, 得到 %v", entry)
+	}
+	if _, ok := entry["provider"]; ok {
+		t.Error("空的字段不应输出")
+	}
+
+	SetLogFormat("text")
+	if s := formatLog(LevelInfo, "hello %s", []interface{}{"world"}); s != "hello world" {
+		t.Errorf("期待 hello world, 得到 %s", s)
+	}
+}
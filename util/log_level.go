@@ -0,0 +1,58 @@
+package util
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// 日志级别, 低于最低级别的日志不输出
+const (
+	LevelDebug = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var logLevelNames = map[string]int{
+	"debug": LevelDebug,
+	"info":  LevelInfo,
+	"warn":  LevelWarn,
+	"error": LevelError,
+}
+
+// 最低输出的日志级别, 默认 info
+var logLevel = LevelInfo
+
+// SetLogLevel 设置最低输出的日志级别, 可选 debug/info/warn/error
+func SetLogLevel(level string) error {
+	l, ok := logLevelNames[strings.ToLower(level)]
+	if !ok {
+		return fmt.Errorf(LogStr("日志级别不正确: %s, 可选 debug/info/warn/error", level))
+	}
+	logLevel = l
+	return nil
+}
+
+// logAt 级别不低于最低级别时输出日志
+func logAt(level int, key string, args ...interface{}) {
+	if level < logLevel {
+		return
+	}
+	log.Println(formatLog(level, key, args))
+}
+
+// LogDebug 每次运行都会重复输出的日志, 如IP未变化
+func LogDebug(key string, args ...interface{}) {
+	logAt(LevelDebug, key, args...)
+}
+
+// LogWarn 配置不正确等, 不影响继续运行
+func LogWarn(key string, args ...interface{}) {
+	logAt(LevelWarn, key, args...)
+}
+
+// LogError 请求或更新失败
+func LogError(key string, args ...interface{}) {
+	logAt(LevelError, key, args...)
+}
@@ -0,0 +1,40 @@
+package util
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestSetLogLevel 测试低于最低级别的日志不输出
+func TestSetLogLevel(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+	defer func(l int) { logLevel = l }(logLevel)
+
+	if err := SetLogLevel("bad"); err == nil {
+		t.Error("期待日志级别不正确")
+	}
+	if err := SetLogLevel("WARN"); err != nil {
+		t.Fatal(err)
+	}
+	LogDebug("debug")
+	Log("info")
+	LogWarn("warn")
+	LogError("error")
+
+	out := buf.String()
+	for _, s := range []string{"debug", "info"} {
+		if strings.Contains(out, s) {
+			t.Errorf("不应输出 %s 级别的日志", s)
+		}
+	}
+	for _, s := range []string{"warn", "error"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("期待输出 %s 级别的日志", s)
+		}
+	}
+}
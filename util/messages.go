@@ -1,7 +1,6 @@
 package util
 
 import (
-	"log"
 	"strings"
 
 	"golang.org/x/text/language"
@@ -24,6 +23,69 @@ func init() {
 	message.SetString(language.English, "更新域名解析 %s 成功! IP: %s", "Updated domain %s successfully! IP: %s")
 	message.SetString(language.English, "更新域名解析 %s 失败! 异常信息: %s", "Updated domain %s failed! Result: %s")
 
+	message.SetString(language.English, "删除多余域名解析 %s 成功!", "Deleted duplicate record of domain %s successfully!")
+	message.SetString(language.English, "删除多余域名解析 %s 失败! 异常信息: %s", "Deleted duplicate record of domain %s failed! Result: %s")
+	message.SetString(language.English, "%s 的TTL不能小于 %d, 已调整为 %d", "The TTL of %s cannot be less than %d, adjusted to %d")
+	message.SetString(language.English, "DynDNS2 认证失败", "DynDNS2 authentication failed")
+	message.SetString(language.English, "域名不属于该帐号", "The domain does not belong to this account")
+	message.SetString(language.English, "服务账号密钥缺少 client_email 或 private_key", "The service account key is missing client_email or private_key")
+	message.SetString(language.English, "私钥格式不正确", "Invalid private key format")
+	message.SetString(language.English, "没有支持TXT记录的DNS服务商配置", "No DNS provider configuration supports TXT records")
+	message.SetString(language.English, "设置TXT记录 %s 失败", "Set TXT record %s failed")
+	message.SetString(language.English, "域名: %s 的TTL %s 不正确, 将使用配置的TTL", "The TTL %[2]s of domain %[1]s is incorrect, the configured TTL will be used")
+	message.SetString(language.English, "域名: %s 的proxied %s 不正确, 将使用配置的值", "The proxied %[2]s of domain %[1]s is incorrect, the configured value will be used")
+	message.SetString(language.English, "请求 %s 失败, 将在 %s 后重试", "Request to %s failed, retrying in %s")
+	message.SetString(language.English, "更新失败的域名: %s", "Failed domains: %s")
+	message.SetString(language.English, "更新完成, 成功: %d, 失败: %d, 未改变: %d", "Update finished, succeeded: %d, failed: %d, nothing changed: %d")
+	message.SetString(language.English, "获取%s结果失败! 命令 %s 执行超过 %s", "Get %s result failed! The command %s ran longer than %s")
+	message.SetString(language.English, "正则表达式 %s 不正确, 将匹配返回值中的第一个%s", "The regex %s is incorrect, the first %s in the response will be used")
+	message.SetString(language.English, "获得的IPv4 %s 不是公网地址, 将不会更新! 如需使用请勾选允许内网IP", "The IPv4 %s obtained is not a public address and will not be updated! Check Allow private IP if you want to use it")
+	message.SetString(language.English, "通过DoH查询域名 %s 失败! 异常信息: %s", "Query domain %s by DoH failed! Exception: %s")
+	message.SetString(language.English, "IPv6后缀 %s 不正确! 异常信息: %s", "The IPv6 suffix %s is incorrect! Exception: %s")
+	message.SetString(language.English, "使用 %s 的前缀与后缀 %s 组合为 %s", "Combined the prefix of %s with the suffix %s into %s")
+	message.SetString(language.English, "网卡 %s 共有 %d 个IPv6地址, 可用 %d 个", "Network card %s has %d IPv6 addresses, %d available")
+	message.SetString(language.English, "请求头不正确: %s", "Request header is invalid: %s")
+	message.SetString(language.English, "不支持的代理协议: %s", "Unsupported proxy scheme: %s")
+	message.SetString(language.English, "代理地址不正确: %s", "Invalid proxy address: %s")
+	message.SetString(language.English, "读取历史记录失败: %s", "Failed to read history: %s")
+	message.SetString(language.English, "保存历史记录失败: %s", "Failed to save history: %s")
+	message.SetString(language.English, "配置文件中引用的环境变量 %s 未设置", "The environment variable %s referenced in the config file is not set")
+	message.SetString(language.English, "读取配置文件中引用的文件失败: %s", "Failed to read the file referenced in the config file: %s")
+	message.SetString(language.English, "配置文件已从版本 %d 升级到 %d", "The config file has been upgraded from version %d to %d")
+	message.SetString(language.English, "配置文件已被修改, 将重新加载", "The config file has been modified, reloading")
+	message.SetString(language.English, "已生成自签名证书: %s", "Self-signed certificate generated: %s")
+	message.SetString(language.English, "读取证书失败! %s", "Failed to load the certificate! %s")
+	message.SetString(language.English, "该DNS服务商暂不支持测试", "Testing is not supported for this DNS provider yet")
+	message.SetString(language.English, "测试成功", "Test succeeded")
+	message.SetString(language.English, "域名 %s 更新后运行命令超过 %s, 已结束", "Command for %s exceeded %s after the update, killed")
+	message.SetString(language.English, "域名 %s 更新后运行命令失败! 错误：%s, 输出：%q, 错误输出：%q", "Command for %s failed after the update! Error: %s, stdout: %q, stderr: %q")
+	message.SetString(language.English, "域名 %s 更新后运行命令成功, 输出：%q, 错误输出：%q", "Command for %s succeeded after the update, stdout: %q, stderr: %q")
+	message.SetString(language.English, "MX记录: %s 不正确, 格式为 域名 优先级 目标", "MX record: %s is incorrect, the format is: domain priority target")
+	message.SetString(language.English, "日志级别不正确: %s, 可选 debug/info/warn/error", "Log level %s is incorrect, available: debug/info/warn/error")
+	message.SetString(language.English, "日志格式不正确: %s, 可选 text/json", "Log format %s is incorrect, available: text/json")
+	message.SetString(language.English, "匹配成功! 匹配到地址: %s", "Matched! The address is: %s")
+	message.SetString(language.English, "更新超过 %s 未完成, 已取消进行中的请求", "The update did not finish within %s, in-flight requests are cancelled")
+	message.SetString(language.English, "收到退出信号, 等待正在进行的更新完成", "Received the exit signal, waiting for the running update to finish")
+	message.SetString(language.English, "ddns-go 已退出", "ddns-go exited")
+	message.SetString(language.English, "未找到域名 %s 的记录, 已开启只更新不新增, 将不会新增", "Record of domain %s not found, update only is enabled, will not create it")
+	message.SetString(language.English, "域名: %s 的updateOnly %s 不正确, 将使用配置的值", "The updateOnly %[2]s of domain %[1]s is incorrect, the configured value will be used")
+	message.SetString(language.English, "删除域名解析 %s 成功!", "Deleted record of domain %s successfully!")
+	message.SetString(language.English, "删除域名解析 %s 失败! 异常信息: %s", "Deleted record of domain %s failed! Result: %s")
+	message.SetString(language.English, "%s 不支持获取不到IP时删除记录", "%s does not support deleting records when no IP is available")
+	message.SetString(language.English, "连续3次未能获取%s地址, 将删除%s记录", "Failed to get %s address 3 times in a row, %s records will be deleted")
+	message.SetString(language.English, "通过DNS查询获取%s失败! 地址: %s", "Failed to get %s by DNS query! Address: %s")
+	message.SetString(language.English, "正在更新中, 请稍后再试", "An update is already running, please try again later")
+	message.SetString(language.English, "可信代理地址不正确: %s", "Incorrect trusted proxy address: %s")
+	message.SetString(language.English, "域名: %s 的nonWeb %s 不正确, 将使用配置的值", "Domain: %s nonWeb %s is incorrect, the configured value will be used")
+	message.SetString(language.English, "域名: %s 为非Web服务, 将不会开启代理", "Domain: %s is a non-web service, the proxy will not be enabled")
+	message.SetString(language.English, "检查 %s 的根域名失败! 异常信息: %s", "Failed to check the root domains of %s! Exception: %s")
+	message.SetString(language.English, "根域名 %s 不在 %s 的账号中, 请检查域名是否正确及令牌是否有权限", "Root domain %s is not in the %s account, please check the domain and the permissions of the token")
+	message.SetString(language.English, "第 %s 个配置已禁用, 将不会更新", "The %s config is disabled and will not be updated")
+	message.SetString(language.English, "域名 %s 在通知冷却时间内, 暂不通知", "Domain %s is in the notification cooldown, it will not be notified for now")
+	message.SetString(language.English, "CNAME记录: %s 不正确, 格式为 域名 目标", "CNAME record: %s is incorrect, the format is: domain target")
+	message.SetString(language.English, "演练模式已开启, 不会修改任何解析记录", "Dry run is enabled, no DNS records will be changed")
+	message.SetString(language.English, "演练模式, 域名 %s 将发送请求: %s", "Dry run, the request for domain %s would be: %s")
+
 	message.SetString(language.English, "你的IPv4未变化, 未触发 %s 请求", "Your's IPv4 has not changed, %s request has not been triggered")
 	message.SetString(language.English, "你的IPv6未变化, 未触发 %s 请求", "Your's IPv6 has not changed, %s request has not been triggered")
 	message.SetString(language.English, "Namecheap 不支持更新 IPv6", "Namecheap don't supports IPv6")
@@ -34,17 +96,20 @@ func init() {
 	message.SetString(language.English, "异常信息: %s", "Exception: %s")
 	message.SetString(language.English, "查询域名信息发生异常! %s", "Query domain info failed! %s")
 	message.SetString(language.English, "返回内容: %s ,返回状态码: %d", "Response body: %s ,Response status code: %d")
-	message.SetString(language.English, "通过接口获取IPv4失败! 接口地址: %s", "Get IPv4 from %s failed")
-	message.SetString(language.English, "通过接口获取IPv6失败! 接口地址: %s", "Get IPv6 from %s failed")
-	message.SetString(language.English, "将不会触发Webhook, 仅在第 3 次失败时触发一次Webhook, 当前失败次数：%d", "Webhook will not be triggered, only trigger once when the third failure, current failure times: %d")
+	message.SetString(language.English, "通过接口获取%s失败! 接口地址: %s", "Get %s from %s failed")
+	message.SetString(language.English, "通过接口 %s 获得%s: %s", "Got %[2]s from %[1]s: %[3]s")
 	message.SetString(language.English, "在DNS服务商中未找到根域名: %s", "Root domain not found in DNS provider: %s")
 
 	// webhook
 	message.SetString(language.English, "Webhook配置中的URL不正确", "Webhook url is incorrect")
 	message.SetString(language.English, "Webhook中的 RequestBody JSON 无效", "Webhook RequestBody JSON is invalid")
-	message.SetString(language.English, "Webhook调用成功! 返回数据：%s", "Webhook called successfully! Response body: %s")
-	message.SetString(language.English, "Webhook调用失败! 异常信息：%s", "Webhook called failed! Exception: %s")
 	message.SetString(language.English, "Webhook Header不正确: %s", "Webhook header is invalid: %s")
+	message.SetString(language.English, "将不会发送%s通知, 仅在第 3 次失败时发送一次, 当前失败次数：%d", "%s notification will not be sent, only send once when the third failure, current failure times: %d")
+	message.SetString(language.English, "邮件", "Email")
+	message.SetString(language.English, "时间: %s", "Time: %s")
+	message.SetString(language.English, "Webhook返回数据：%s", "Webhook response body: %s")
+	message.SetString(language.English, "%s通知发送成功", "%s notification sent successfully")
+	message.SetString(language.English, "%s通知发送失败! 异常信息：%s", "%s notification sent failed! Exception: %s")
 	message.SetString(language.English, "请输入Webhook的URL", "Please enter the Webhook url")
 
 	// callback
@@ -63,12 +128,11 @@ func init() {
 	// config
 	message.SetString(language.English, "从网卡获得IPv4失败", "Get IPv4 from network card failed")
 	message.SetString(language.English, "从网卡中获得IPv4失败! 网卡名: %s", "Get IPv4 from network card failed! Network card name: %s")
-	message.SetString(language.English, "获取IPv4结果失败! 接口: %s ,返回值: %s", "Get IPv4 result failed! Interface: %s ,Result: %s")
+	message.SetString(language.English, "获取%s结果失败! 接口: %s ,返回值: %s", "Get %s result failed! Interface: %s ,Result: %s")
 	message.SetString(language.English, "获取%s结果失败! 未能成功执行命令：%s, 错误：%q, 退出状态码：%s", "Get %s result failed! Command: %s, Error: %q, Exit status code: %s")
 	message.SetString(language.English, "获取%s结果失败! 命令: %s, 标准输出: %q", "Get %s result failed! Command: %s, Stdout: %q")
 	message.SetString(language.English, "从网卡获得IPv6失败", "Get IPv6 from network card failed")
 	message.SetString(language.English, "从网卡中获得IPv6失败! 网卡名: %s", "Get IPv6 from network card failed! Network card name: %s")
-	message.SetString(language.English, "获取IPv6结果失败! 接口: %s ,返回值: %s", "Get IPv6 result failed! Interface: %s ,Result: %s")
 	message.SetString(language.English, "未找到第 %d 个IPv6地址! 将使用第一个IPv6地址", "%dth IPv6 address not found! Will use the first IPv6 address")
 	message.SetString(language.English, "IPv6匹配表达式 %s 不正确! 最小从1开始", "IPv6 match expression %s is incorrect! Minimum start from 1")
 	message.SetString(language.English, "IPv6将使用正则表达式 %s 进行匹配", "IPv6 will use regular expression %s for matching")
@@ -82,6 +146,8 @@ func init() {
 	message.SetString(language.English, "域名: %s 解析失败", "The domain %s resolution failed")
 	message.SetString(language.English, "IPv6未改变, 将等待 %d 次后与DNS服务商进行比对", "IPv6 has not changed, will wait %d times to compare with DNS provider")
 	message.SetString(language.English, "IPv4未改变, 将等待 %d 次后与DNS服务商进行比对", "IPv4 has not changed, will wait %d times to compare with DNS provider")
+	message.SetString(language.English, "IPv6未改变, 将不会与DNS服务商进行比对", "IPv6 has not changed, will not compare with DNS provider")
+	message.SetString(language.English, "IPv4未改变, 将不会与DNS服务商进行比对", "IPv4 has not changed, will not compare with DNS provider")
 
 	message.SetString(language.English, "本机DNS异常! 将默认使用 %s, 可参考文档通过 -dns 自定义 DNS 服务器", "Local DNS exception! Will use %s by default, you can use -dns to customize DNS server")
 	message.SetString(language.English, "等待网络连接: %s", "Waiting for network connection: %s")
@@ -114,21 +180,36 @@ func init() {
 
 	// Login
 	message.SetString(language.English, "%q 登陆成功", "%q login successfully")
+	message.SetString(language.English, "登陆成功", "Login successfully")
 	message.SetString(language.English, "用户名或密码错误", "Username or password is incorrect")
 	message.SetString(language.English, "登录失败次数过多，请等待 %d 分钟后再试", "Too many login failures, please try again after %d minutes")
 	message.SetString(language.English, "用户名 %s 的密码已重置成功! 请重启ddns-go", "The password of username %s has been reset successfully! Please restart ddns-go")
 
 }
 
+// Log 输出 info 级别的日志
 func Log(key string, args ...interface{}) {
-	log.Println(LogStr(key, args...))
+	logAt(LevelInfo, key, args...)
 }
 
 func LogStr(key string, args ...interface{}) string {
 	return logPrinter.Sprintf(key, args...)
 }
 
+// 命令行指定的日志语言, 不为空时不再跟随配置文件或浏览器的语言
+var forcedLogLang string
+
+// SetLogLang 指定日志语言, 可选 en/zh, 为空时跟随配置文件中的语言
+func SetLogLang(lang string) {
+	forcedLogLang = lang
+	InitLogLang(lang)
+}
+
+// InitLogLang 初始化日志语言, 以zh开头时为中文, 其它为英文
 func InitLogLang(lang string) string {
+	if forcedLogLang != "" {
+		lang = forcedLogLang
+	}
 	logLang := language.English
 	if strings.HasPrefix(lang, "zh") {
 		logLang = language.Chinese
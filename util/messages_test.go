@@ -0,0 +1,71 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// TestSetLogLang 测试指定语言后不再跟随配置文件的语言
+func TestSetLogLang(t *testing.T) {
+	defer func() {
+		forcedLogLang = ""
+		InitLogLang("zh")
+	}()
+
+	if InitLogLang("zh-CN") != "zh" || LogStr("登陆成功") != "登陆成功" {
+		t.Error("期待中文日志")
+	}
+	SetLogLang("en")
+	if InitLogLang("zh-CN") != "en" || LogStr("登陆成功") != "Login successfully" {
+		t.Error("期待英文日志")
+	}
+}
+
+// 日志关键字, 如 util.Log("...")
+var logKeyReg = regexp.MustCompile(`(?:util\.)?(?:Log|LogDebug|LogWarn|LogError|LogStr)\(("(?:[^"\\]|\\.)*")`)
+
+// 中文字符
+var hanReg = regexp.MustCompile(`\p{Han}`)
+
+// TestLogMessagesTranslated 测试所有中文日志都有英文翻译
+func TestLogMessagesTranslated(t *testing.T) {
+	en := message.NewPrinter(language.English)
+	zh := message.NewPrinter(language.Chinese)
+
+	err := filepath.Walk("..", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && strings.HasPrefix(info.Name(), ".") && info.Name() != ".." {
+			return filepath.SkipDir
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		byt, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, match := range logKeyReg.FindAllStringSubmatch(string(byt), -1) {
+			key, err := strconv.Unquote(match[1])
+			if err != nil || !hanReg.MatchString(key) {
+				continue
+			}
+			// 未翻译时英文与中文相同
+			if en.Sprintf(key) == zh.Sprintf(key) {
+				t.Errorf("%s: 日志 %q 没有英文翻译", path, key)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
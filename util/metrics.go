@@ -0,0 +1,129 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 请求耗时直方图的桶(秒), 与 Prometheus 客户端的默认值一致
+var durationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// updateKey 更新指标的标签
+type updateKey struct {
+	provider   string
+	domain     string
+	recordType string
+}
+
+// histogram 直方图, counts 与 durationBuckets 一一对应, 不包括 +Inf
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+var metrics = struct {
+	sync.Mutex
+	attempts    map[updateKey]uint64
+	succeeded   map[updateKey]uint64
+	failed      map[updateKey]uint64
+	lastSuccess map[updateKey]int64
+	durations   map[string]*histogram
+}{
+	attempts:    map[updateKey]uint64{},
+	succeeded:   map[updateKey]uint64{},
+	failed:      map[updateKey]uint64{},
+	lastSuccess: map[updateKey]int64{},
+	durations:   map[string]*histogram{},
+}
+
+// RecordUpdate 记录一次域名更新
+func RecordUpdate(provider, domain, recordType string, success bool) {
+	key := updateKey{provider: provider, domain: domain, recordType: recordType}
+	metrics.Lock()
+	defer metrics.Unlock()
+	metrics.attempts[key]++
+	if success {
+		metrics.succeeded[key]++
+		metrics.lastSuccess[key] = time.Now().Unix()
+	} else {
+		metrics.failed[key]++
+	}
+}
+
+// ObserveRequestDuration 记录一次请求的耗时
+func ObserveRequestDuration(host string, d time.Duration) {
+	metrics.Lock()
+	defer metrics.Unlock()
+	h := metrics.durations[host]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(durationBuckets))}
+		metrics.durations[host] = h
+	}
+	seconds := d.Seconds()
+	for i, bucket := range durationBuckets {
+		if seconds <= bucket {
+			h.counts[i]++
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// WriteMetrics 以 Prometheus 文本格式输出所有指标
+func WriteMetrics(w io.Writer) {
+	metrics.Lock()
+	defer metrics.Unlock()
+
+	writeUpdateMetric(w, "ddns_go_update_attempts_total", "counter", "Number of DNS record updates attempted.", metrics.attempts)
+	writeUpdateMetric(w, "ddns_go_update_succeeded_total", "counter", "Number of DNS record updates succeeded.", metrics.succeeded)
+	writeUpdateMetric(w, "ddns_go_update_failed_total", "counter", "Number of DNS record updates failed.", metrics.failed)
+	lastSuccess := make(map[updateKey]uint64, len(metrics.lastSuccess))
+	for key, value := range metrics.lastSuccess {
+		lastSuccess[key] = uint64(value)
+	}
+	writeUpdateMetric(w, "ddns_go_last_success_timestamp_seconds", "gauge", "Unix time of the last successful DNS record update.", lastSuccess)
+
+	name := "ddns_go_request_duration_seconds"
+	fmt.Fprintf(w, "# HELP %s Latency of requests to the DNS provider API, including notifications.\n", name)
+	fmt.Fprintf(w, "# TYPE %s histogram\n", name)
+	hosts := make([]string, 0, len(metrics.durations))
+	for host := range metrics.durations {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	for _, host := range hosts {
+		h := metrics.durations[host]
+		label := fmt.Sprintf(`host="%s"`, escapeLabel(host))
+		for i, bucket := range durationBuckets {
+			fmt.Fprintf(w, "%s_bucket{%s,le=\"%g\"} %d\n", name, label, bucket, h.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, label, h.count)
+		fmt.Fprintf(w, "%s_sum{%s} %g\n", name, label, h.sum)
+		fmt.Fprintf(w, "%s_count{%s} %d\n", name, label, h.count)
+	}
+}
+
+// writeUpdateMetric 按标签排序输出更新指标
+func writeUpdateMetric(w io.Writer, name, metricType, help string, values map[updateKey]uint64) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, metricType)
+	lines := make([]string, 0, len(values))
+	for key, value := range values {
+		lines = append(lines, fmt.Sprintf(`%s{provider="%s",domain="%s",type="%s"} %d`,
+			name, escapeLabel(key.provider), escapeLabel(key.domain), escapeLabel(key.recordType), value))
+	}
+	sort.Strings(lines)
+	for _, line := range lines {
+		fmt.Fprintln(w, line)
+	}
+}
+
+// escapeLabel 转义标签值中的反斜杠、引号和换行
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
@@ -0,0 +1,39 @@
+package util
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWriteMetrics 测试输出的 Prometheus 指标
+func TestWriteMetrics(t *testing.T) {
+	RecordUpdate("cloudflare", "www.example.com", "A", true)
+	RecordUpdate("cloudflare", "www.example.com", "A", false)
+	ObserveRequestDuration("api.cloudflare.com", 30*time.Millisecond)
+
+	var sb strings.Builder
+	WriteMetrics(&sb)
+	out := sb.String()
+
+	for _, want := range []string{
+		`ddns_go_update_attempts_total{provider="cloudflare",domain="www.example.com",type="A"} 2`,
+		`ddns_go_update_succeeded_total{provider="cloudflare",domain="www.example.com",type="A"} 1`,
+		`ddns_go_update_failed_total{provider="cloudflare",domain="www.example.com",type="A"} 1`,
+		`ddns_go_last_success_timestamp_seconds{provider="cloudflare",domain="www.example.com",type="A"} `,
+		`ddns_go_request_duration_seconds_bucket{host="api.cloudflare.com",le="0.025"} 0`,
+		`ddns_go_request_duration_seconds_bucket{host="api.cloudflare.com",le="0.05"} 1`,
+		`ddns_go_request_duration_seconds_bucket{host="api.cloudflare.com",le="+Inf"} 1`,
+		`ddns_go_request_duration_seconds_count{host="api.cloudflare.com"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("缺少 %s\n%s", want, out)
+		}
+	}
+}
+
+func TestEscapeLabel(t *testing.T) {
+	if got := escapeLabel("a\"b\\c\nd"); got != `a\"b\\c\nd` {
+		t.Errorf("得到 %s", got)
+	}
+}
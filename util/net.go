@@ -1,14 +1,130 @@
 package util
 
 import (
+	"errors"
+	"fmt"
 	"net"
 	"net/http"
+	"os"
 	"strings"
 )
 
+// 可信的反向代理, 只有来自这些地址的请求才使用 X-Forwarded-For/X-Real-IP
+var trustedProxies []*net.IPNet
+
+// SetTrustedProxies 设置可信的反向代理, 多个IP或CIDR用逗号分隔
+func SetTrustedProxies(list string) error {
+	var proxies []*net.IPNet
+	for _, s := range strings.Split(list, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		if !strings.Contains(s, "/") {
+			if ip := net.ParseIP(s); ip != nil && ip.To4() != nil {
+				s += "/32"
+			} else {
+				s += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(s)
+		if err != nil {
+			return fmt.Errorf(LogStr("可信代理地址不正确: %s", s))
+		}
+		proxies = append(proxies, ipNet)
+	}
+	trustedProxies = proxies
+	return nil
+}
+
+// isTrustedProxy 是否为可信的反向代理, Unix socket 只能由本机的反向代理连接, 视为可信
+func isTrustedProxy(host string) bool {
+	if host == "" || host == "@" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range trustedProxies {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetClientIP 获得请求的客户端IP
+// 来自可信代理的请求, 从右向左取 X-Forwarded-For 中第一个不是可信代理的地址, 没有时使用 X-Real-IP
+func GetClientIP(r *http.Request) string {
+	host := remoteHost(r)
+	if !isTrustedProxy(host) {
+		return host
+	}
+
+	if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+		ips := strings.Split(strings.Join(forwarded, ","), ",")
+		for i := len(ips) - 1; i >= 0; i-- {
+			ip := strings.TrimSpace(ips[i])
+			if net.ParseIP(ip) == nil {
+				break
+			}
+			host = ip
+			if !isTrustedProxy(ip) {
+				break
+			}
+		}
+		return host
+	}
+	if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); net.ParseIP(ip) != nil {
+		return ip
+	}
+	return host
+}
+
+// remoteHost 请求来源的地址, 不含端口
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// 监听 Unix socket 的地址前缀, 如 unix:/run/ddns-go.sock
+const unixListenPrefix = "unix:"
+
+// CheckListenAddr 检查监听地址, 支持 host:port 或 unix:/path/to.sock
+func CheckListenAddr(addr string) error {
+	if path, ok := strings.CutPrefix(addr, unixListenPrefix); ok {
+		if path == "" {
+			return errors.New("empty unix socket path")
+		}
+		return nil
+	}
+	_, err := net.ResolveTCPAddr("tcp", addr)
+	return err
+}
+
+// Listen 监听 host:port 或 unix:/path/to.sock
+// 监听 Unix socket 时先删除上次未清理的 socket 文件
+func Listen(addr string) (net.Listener, error) {
+	if path, ok := strings.CutPrefix(addr, unixListenPrefix); ok {
+		if fi, err := os.Lstat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+			os.Remove(path)
+		}
+		return net.Listen("unix", path)
+	}
+	return net.Listen("tcp", addr)
+}
+
 // IsPrivateNetwork 是否为私有地址
 // https://en.wikipedia.org/wiki/Private_network
 func IsPrivateNetwork(remoteAddr string) bool {
+	// 不带端口的IPv6, 如 GetClientIP 的返回值
+	if ip := net.ParseIP(remoteAddr); ip != nil {
+		remoteAddr = "[" + remoteAddr + "]"
+	}
 	// removing optional port from remoteAddr
 	if strings.HasPrefix(remoteAddr, "[") { // ipv6
 		if index := strings.LastIndex(remoteAddr, "]"); index != -1 {
@@ -40,5 +156,9 @@ func GetRequestIPStr(r *http.Request) (addr string) {
 	if r.Header.Get("X-Forwarded-For") != "" {
 		addr = addr + " ,Forwarded-For: " + r.Header.Get("X-Forwarded-For")
 	}
+	// 来自可信代理时的客户端IP
+	if client := GetClientIP(r); client != remoteHost(r) {
+		addr = addr + " ,Client: " + client
+	}
 	return addr
 }
@@ -1,7 +1,12 @@
 package util
 
 import (
+	"context"
+	"io"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"path/filepath"
 	"testing"
 )
 
@@ -41,4 +46,104 @@ func TestGetRequestIPStr(t *testing.T) {
 	if GetRequestIPStr(&req) != "Remote: 192.168.1.1 ,Real-IP: 10.0.0.1 ,Forwarded-For: 10.0.0.2" {
 		t.Errorf("GetRequestIPStr failed")
 	}
+
+	defer SetTrustedProxies("")
+	SetTrustedProxies("192.168.1.1")
+	if GetRequestIPStr(&req) != "Remote: 192.168.1.1 ,Real-IP: 10.0.0.1 ,Forwarded-For: 10.0.0.2 ,Client: 10.0.0.2" {
+		t.Errorf("GetRequestIPStr failed")
+	}
+}
+
+// TestListen 测试监听 host:port 及 Unix socket
+func TestListen(t *testing.T) {
+	for addr, ok := range map[string]bool{
+		":9876":                  true,
+		"127.0.0.1:9876":         true,
+		"[::1]:9876":             true,
+		"unix:/run/ddns-go.sock": true,
+		"unix:":                  false,
+		"127.0.0.1":              false,
+	} {
+		if err := CheckListenAddr(addr); (err == nil) != ok {
+			t.Errorf("%s 校验失败: %v", addr, err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "ddns-go.sock")
+	for i := 0; i < 2; i++ {
+		l, err := Listen(unixListenPrefix + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		go http.Serve(l, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("ok"))
+		}))
+		client := http.Client{Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", path)
+			},
+		}}
+		resp, err := client.Get("http://unix/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if string(body) != "ok" {
+			t.Errorf("期待 ok, 得到 %s", body)
+		}
+		// 第二次监听时 socket 文件已存在, 需能正常监听
+		if i == 0 {
+			l.(*net.UnixListener).SetUnlinkOnClose(false)
+		}
+		l.Close()
+	}
+}
+
+// TestGetClientIP 测试只使用可信代理的 X-Forwarded-For/X-Real-IP
+func TestGetClientIP(t *testing.T) {
+	defer SetTrustedProxies("")
+	if err := SetTrustedProxies("127.0.0.1, 172.17.0.0/16,::1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetTrustedProxies("127.0.0.1,10.0.0.0/33"); err == nil {
+		t.Error("期待CIDR不正确")
+	}
+
+	tests := []struct {
+		remoteAddr string
+		forwarded  string
+		realIP     string
+		want       string
+	}{
+		{"223.5.5.5:1234", "", "", "223.5.5.5"},
+		{"223.5.5.5:1234", "192.168.1.2", "192.168.1.3", "223.5.5.5"},
+		{"127.0.0.1:1234", "", "", "127.0.0.1"},
+		{"127.0.0.1:1234", "", "223.5.5.5", "223.5.5.5"},
+		{"127.0.0.1:1234", "223.5.5.5", "192.168.1.3", "223.5.5.5"},
+		{"[::1]:1234", "2409::1", "", "2409::1"},
+		// 客户端伪造的地址在左侧, 取第一个不是可信代理的地址
+		{"127.0.0.1:1234", "192.168.1.2, 223.5.5.5, 172.17.0.2", "", "223.5.5.5"},
+		{"127.0.0.1:1234", "172.17.0.3, 172.17.0.2", "", "172.17.0.3"},
+		{"127.0.0.1:1234", "invalid, 172.17.0.2", "", "172.17.0.2"},
+		// Unix socket
+		{"@", "223.5.5.5", "", "223.5.5.5"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = tt.remoteAddr
+		if tt.forwarded != "" {
+			r.Header.Set("X-Forwarded-For", tt.forwarded)
+		}
+		if tt.realIP != "" {
+			r.Header.Set("X-Real-IP", tt.realIP)
+		}
+		if got := GetClientIP(r); got != tt.want {
+			t.Errorf("%s %q 期待 %s, 得到 %s", tt.remoteAddr, tt.forwarded, tt.want, got)
+		}
+	}
+
+	if IsPrivateNetwork("2409::1") || !IsPrivateNetwork("::1") || !IsPrivateNetwork("127.0.0.1") {
+		t.Error("不带端口的IP校验失败")
+	}
 }
@@ -0,0 +1,25 @@
+package util
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+)
+
+// OvhSigner OVH 签名方法, timestamp 需使用 /auth/time 返回的服务器时间
+// https://help.ovhcloud.com/csm/en-api-getting-started-ovhcloud-api?id=kb_article_view&sysparm_article=KB0042784
+func OvhSigner(applicationKey, applicationSecret, consumerKey string, r *http.Request, body string, timestamp int64) {
+	r.Header.Set("X-Ovh-Application", applicationKey)
+	r.Header.Set("X-Ovh-Consumer", consumerKey)
+	r.Header.Set("X-Ovh-Timestamp", strconv.FormatInt(timestamp, 10))
+	r.Header.Set("X-Ovh-Signature", ovhSignature(applicationSecret, consumerKey, r.Method, r.URL.String(), body, timestamp))
+}
+
+// ovhSignature "$1$" + SHA1_HEX(AS+"+"+CK+"+"+METHOD+"+"+QUERY+"+"+BODY+"+"+TSTAMP)
+func ovhSignature(applicationSecret, consumerKey, method, url, body string, timestamp int64) string {
+	sum := sha1.Sum([]byte(WriteString(
+		applicationSecret, "+", consumerKey, "+", method, "+", url, "+", body, "+", strconv.FormatInt(timestamp, 10),
+	)))
+	return "$1$" + hex.EncodeToString(sum[:])
+}
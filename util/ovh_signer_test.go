@@ -0,0 +1,61 @@
+package util
+
+import (
+	"net/http"
+	"testing"
+)
+
+// 使用 OVH 文档示例中的 Application Secret 和 Consumer Key
+const (
+	ovhTestApplicationSecret = "EXEgWIz07P0HYwtQDs7cNIqCiQaWSuHF"
+	ovhTestConsumerKey       = "MtSwSrPpNjqfVSmJhLbPyr2i45lSDRj1"
+	ovhTestTimestamp         = 1366560945
+)
+
+// TestOvhSignature 测试 GET 和带请求体的 POST 签名
+func TestOvhSignature(t *testing.T) {
+	tests := []struct {
+		method string
+		url    string
+		body   string
+		want   string
+	}{
+		{
+			http.MethodGet,
+			"https://eu.api.ovh.com/1.0/domain/zone/example.com/record?fieldType=A&subDomain=www",
+			"",
+			"$1$e9b8c774b18864ceed947252b11f78e2db222582",
+		},
+		{
+			http.MethodPost,
+			"https://eu.api.ovh.com/1.0/domain/zone/example.com/record",
+			`{"fieldType":"A","subDomain":"www","target":"1.2.3.4","ttl":300}`,
+			"$1$28a39bf51d7eec1f1089a75bbc977cb5a1f5824f",
+		},
+	}
+
+	for _, tt := range tests {
+		got := ovhSignature(ovhTestApplicationSecret, ovhTestConsumerKey, tt.method, tt.url, tt.body, ovhTestTimestamp)
+		if got != tt.want {
+			t.Errorf("%s %s 期待 %s, 得到 %s", tt.method, tt.url, tt.want, got)
+		}
+	}
+}
+
+// TestOvhSigner 测试请求头
+func TestOvhSigner(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://eu.api.ovh.com/1.0/domain/zone/example.com/record?fieldType=A&subDomain=www", nil)
+	OvhSigner("7kbG7Bk7S9Nt7ZSV", ovhTestApplicationSecret, ovhTestConsumerKey, req, "", ovhTestTimestamp)
+
+	want := map[string]string{
+		"X-Ovh-Application": "7kbG7Bk7S9Nt7ZSV",
+		"X-Ovh-Consumer":    ovhTestConsumerKey,
+		"X-Ovh-Timestamp":   "1366560945",
+		"X-Ovh-Signature":   "$1$e9b8c774b18864ceed947252b11f78e2db222582",
+	}
+	for k, v := range want {
+		if got := req.Header.Get(k); got != v {
+			t.Errorf("%s 期待 %s, 得到 %s", k, v, got)
+		}
+	}
+}
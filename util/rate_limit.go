@@ -0,0 +1,105 @@
+package util
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 每个接口地址每秒4次请求, 最多积攒40次
+// Cloudflare 限制为5分钟1200次, 即每秒4次
+const (
+	rateLimitPerSecond = 4
+	rateLimitBurst     = 40
+)
+
+// 单次等待 Retry-After 的最长时间
+const retryAfterMaxDelay = 60 * time.Second
+
+// tokenBucket 令牌桶
+type tokenBucket struct {
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	rate   float64
+	burst  float64
+}
+
+// 按接口地址区分, 所有DNS服务商共用
+var (
+	rateLimiters   = map[string]*tokenBucket{}
+	rateLimitersMu sync.Mutex
+)
+
+// waitRateLimit 等待请求地址有可用的令牌
+func waitRateLimit(req *http.Request) error {
+	rateLimitersMu.Lock()
+	bucket, ok := rateLimiters[req.URL.Host]
+	if !ok {
+		bucket = &tokenBucket{tokens: rateLimitBurst, last: time.Now(), rate: rateLimitPerSecond, burst: rateLimitBurst}
+		rateLimiters[req.URL.Host] = bucket
+	}
+	rateLimitersMu.Unlock()
+
+	return bucket.wait(req.Context())
+}
+
+// wait 取走一个令牌, 没有时等待
+func (b *tokenBucket) wait(ctx context.Context) error {
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+	b.tokens--
+	// 令牌不足时预先扣除, 按欠缺的数量等待
+	var delay time.Duration
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	b.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(delay):
+		return nil
+	}
+}
+
+// retryAfter 解析 Retry-After(秒或HTTP时间), 或 Cloudflare 的 Ratelimit 中的 t=秒
+// 没有时返回0
+func retryAfter(resp *http.Response) time.Duration {
+	if resp == nil {
+		return 0
+	}
+
+	if value := strings.TrimSpace(resp.Header.Get("Retry-After")); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		if date, err := http.ParseTime(value); err == nil {
+			if delay := time.Until(date); delay > 0 {
+				return delay
+			}
+		}
+	}
+
+	// 如: "default";r=0;t=30
+	for _, part := range strings.Split(resp.Header.Get("Ratelimit"), ";") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(part), "t="); ok {
+			if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+				return time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,46 @@
+package util
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestRetryAfter 测试 Retry-After 和 Ratelimit 的解析
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		header http.Header
+		want   time.Duration
+	}{
+		{http.Header{"Retry-After": {"30"}}, 30 * time.Second},
+		{http.Header{"Ratelimit": {`"default";r=0;t=12`}}, 12 * time.Second},
+		{http.Header{"Retry-After": {"abc"}}, 0},
+		{http.Header{}, 0},
+	}
+	for _, tt := range tests {
+		if got := retryAfter(&http.Response{Header: tt.header}); got != tt.want {
+			t.Errorf("%v 期待 %s, 得到 %s", tt.header, tt.want, got)
+		}
+	}
+
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if got := retryAfter(&http.Response{Header: http.Header{"Retry-After": {date}}}); got <= 0 || got > time.Minute {
+		t.Errorf("%s 期待 0-1分钟, 得到 %s", date, got)
+	}
+}
+
+// TestTokenBucket 测试令牌用完后等待
+func TestTokenBucket(t *testing.T) {
+	bucket := &tokenBucket{tokens: 2, last: time.Now(), rate: 20, burst: 2}
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := bucket.wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 前2次不等待, 后2次每次等待50ms
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("期待至少等待 80ms, 实际 %s", elapsed)
+	}
+}
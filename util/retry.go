@@ -0,0 +1,142 @@
+package util
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+)
+
+// 请求的最大尝试次数
+var maxRetryAttempts = 3
+
+// 第一次重试前的等待时间, 之后每次翻倍
+var retryBaseDelay = 500 * time.Millisecond
+
+const retryMaxDelay = 10 * time.Second
+
+// SetMaxRetryAttempts 设置请求的最大尝试次数, 小于1时不重试
+func SetMaxRetryAttempts(attempts int) {
+	if attempts < 1 {
+		attempts = 1
+	}
+	maxRetryAttempts = attempts
+}
+
+// retryTransport 网络异常或返回5xx/429时使用指数退避重试, 其它4xx直接返回。
+// POST等非幂等请求只重试429和连接失败, 避免服务商已处理后重复新增记录
+type retryTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip 实现 http.RoundTripper, 退出时取消进行中的请求
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req, cancel, err := withShutdown(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.roundTrip(req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+func (t *retryTransport) roundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		if err := waitRateLimit(req); err != nil {
+			return nil, err
+		}
+
+		start := time.Now()
+		resp, err := t.next.RoundTrip(req)
+		ObserveRequestDuration(req.URL.Host, time.Since(start))
+		if attempt >= maxRetryAttempts || !shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		// 优先使用服务商返回的等待时间, 超过剩余的超时时间时不再等待
+		delay := retryDelay(attempt)
+		if after := retryAfter(resp); after > 0 {
+			delay = after
+			if delay > retryAfterMaxDelay {
+				delay = retryAfterMaxDelay
+			}
+		}
+		if deadline, ok := req.Context().Deadline(); ok && time.Until(deadline) < delay {
+			return resp, err
+		}
+
+		// 请求体无法重新读取时不重试
+		var body io.ReadCloser
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, err
+			}
+			var bodyErr error
+			body, bodyErr = req.GetBody()
+			if bodyErr != nil {
+				return resp, err
+			}
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		Log("请求 %s 失败, 将在 %s 后重试", req.URL.Host, delay)
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+
+		req = req.Clone(req.Context())
+		if body != nil {
+			req.Body = body
+		}
+	}
+}
+
+// shouldRetry 只重试网络异常和5xx/429, 非幂等请求只重试429和连接失败
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	// 超时或取消
+	if req.Context().Err() != nil {
+		return false
+	}
+	if err != nil {
+		return isIdempotent(req.Method) || isDialError(err)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return resp.StatusCode >= 500 && isIdempotent(req.Method)
+}
+
+// isIdempotent 重复请求不会产生额外影响的方法
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// isDialError 连接失败, 请求还未发出
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// retryDelay 指数退避, 加上最多一半的随机抖动
+func retryDelay(attempt int) time.Duration {
+	delay := retryBaseDelay << (attempt - 1)
+	if delay <= 0 || delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return delay + time.Duration(rand.Int63n(int64(delay)/2+1))
+}
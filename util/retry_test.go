@@ -0,0 +1,74 @@
+package util
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRetryTransport 测试5xx重试和4xx直接返回, POST只重试429
+func TestRetryTransport(t *testing.T) {
+	retryBaseDelay = time.Millisecond
+	defer func() { retryBaseDelay = 500 * time.Millisecond }()
+
+	tests := []struct {
+		name     string
+		method   string
+		statuses []int
+		want     int
+		attempts int
+	}{
+		{"5xx后成功", http.MethodPut, []int{503, 502, 200}, 200, 3},
+		{"429后成功", http.MethodPut, []int{429, 200}, 200, 2},
+		{"4xx不重试", http.MethodPut, []int{400, 200}, 400, 1},
+		{"超过最大次数", http.MethodPut, []int{500, 500, 500, 200}, 500, 3},
+		{"POST的5xx不重试", http.MethodPost, []int{502, 200}, 502, 1},
+		{"POST的429重试", http.MethodPost, []int{429, 200}, 200, 2},
+	}
+
+	for _, tt := range tests {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// 每次重试都需要完整的请求体
+			body, _ := io.ReadAll(r.Body)
+			if string(body) != "body" {
+				t.Errorf("%s: 第 %d 次请求体为 %q", tt.name, attempts+1, body)
+			}
+			w.WriteHeader(tt.statuses[attempts])
+			attempts++
+		}))
+
+		client := &http.Client{Transport: &retryTransport{next: http.DefaultTransport}}
+		req, _ := http.NewRequest(tt.method, server.URL, strings.NewReader("body"))
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		resp.Body.Close()
+		server.Close()
+
+		if resp.StatusCode != tt.want || attempts != tt.attempts {
+			t.Errorf("%s: 期待状态码 %d 请求 %d 次, 得到状态码 %d 请求 %d 次", tt.name, tt.want, tt.attempts, resp.StatusCode, attempts)
+		}
+	}
+}
+
+// TestShouldRetryNetworkError 测试POST只在连接失败时重试网络异常
+func TestShouldRetryNetworkError(t *testing.T) {
+	get, _ := http.NewRequest(http.MethodGet, "http://127.0.0.1", nil)
+	post, _ := http.NewRequest(http.MethodPost, "http://127.0.0.1", nil)
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	readErr := &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}
+
+	if !shouldRetry(get, nil, readErr) || !shouldRetry(post, nil, dialErr) {
+		t.Error("GET的网络异常和POST的连接失败应重试")
+	}
+	if shouldRetry(post, nil, readErr) {
+		t.Error("POST发出后的网络异常不应重试")
+	}
+}
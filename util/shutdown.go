@@ -0,0 +1,61 @@
+package util
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// 退出时取消的context, 通过 CreateHTTPClient 发出的请求都会随之取消
+var (
+	shutdownCtx, cancelShutdown = context.WithCancel(context.Background())
+	shutdownMu                  sync.Mutex
+)
+
+// CancelRequests 取消所有进行中的请求, 之后的请求直接返回错误, 用于退出时结束卡住的请求
+func CancelRequests() {
+	shutdownMu.Lock()
+	defer shutdownMu.Unlock()
+	cancelShutdown()
+}
+
+// ShutdownContext 退出时取消的context
+func ShutdownContext() context.Context {
+	shutdownMu.Lock()
+	defer shutdownMu.Unlock()
+	return shutdownCtx
+}
+
+// withShutdown 返回退出时会被取消的请求, 需在响应体关闭或请求失败后调用 cancel
+func withShutdown(req *http.Request) (*http.Request, context.CancelFunc, error) {
+	shutdownMu.Lock()
+	done := shutdownCtx.Done()
+	err := shutdownCtx.Err()
+	shutdownMu.Unlock()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithCancel(req.Context())
+	go func() {
+		select {
+		case <-done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return req.WithContext(ctx), cancel, nil
+}
+
+// cancelOnClose 关闭响应体时释放请求的context
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
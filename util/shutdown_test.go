@@ -0,0 +1,49 @@
+package util
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestCancelRequests 测试退出时取消卡住的请求, 之后的请求直接返回错误
+func TestCancelRequests(t *testing.T) {
+	defer func(ctx context.Context, cancel context.CancelFunc) {
+		shutdownCtx, cancelShutdown = ctx, cancel
+	}(shutdownCtx, cancelShutdown)
+	shutdownCtx, cancelShutdown = context.WithCancel(context.Background())
+
+	block := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-block:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(block)
+
+	client := &http.Client{Transport: &retryTransport{next: http.DefaultTransport}}
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := client.Get(server.URL)
+		errCh <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	CancelRequests()
+	select {
+	case err := <-errCh:
+		if err == nil {
+			t.Error("期待请求被取消")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("请求未被取消")
+	}
+
+	if _, err := client.Get(server.URL); err == nil {
+		t.Error("退出后的请求应直接返回错误")
+	}
+}
@@ -0,0 +1,86 @@
+package util
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// 自签名证书的文件名, 与配置文件在同一目录
+const (
+	selfSignedCertFileName = ".ddns_go_cert.pem"
+	selfSignedKeyFileName  = ".ddns_go_key.pem"
+)
+
+// LoadTLSCertificate 读取证书, certFile/keyFile 为空时使用自签名证书
+func LoadTLSCertificate(certFile, keyFile string) (tls.Certificate, error) {
+	if certFile != "" || keyFile != "" {
+		return tls.LoadX509KeyPair(certFile, keyFile)
+	}
+
+	dir := filepath.Dir(GetConfigFilePath())
+	certFile = filepath.Join(dir, selfSignedCertFileName)
+	keyFile = filepath.Join(dir, selfSignedKeyFileName)
+	if cert, err := tls.LoadX509KeyPair(certFile, keyFile); err == nil {
+		return cert, nil
+	}
+
+	certPEM, keyPEM, err := generateSelfSignedCert(time.Now())
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	// 保存后重启仍使用同一证书, 避免浏览器每次都提示
+	if err := os.WriteFile(certFile, certPEM, 0600); err != nil {
+		return tls.Certificate{}, err
+	}
+	if err := os.WriteFile(keyFile, keyPEM, 0600); err != nil {
+		return tls.Certificate{}, err
+	}
+	Log("已生成自签名证书: %s", certFile)
+	return tls.X509KeyPair(certPEM, keyPEM)
+}
+
+// generateSelfSignedCert 生成有效期10年的自签名证书, 返回PEM格式的证书和私钥
+func generateSelfSignedCert(now time.Time) (certPEM []byte, keyPEM []byte, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{CommonName: "ddns-go"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM, nil
+}
@@ -0,0 +1,40 @@
+package util
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// TestLoadTLSCertificate 测试生成自签名证书, 再次读取时使用同一证书
+func TestLoadTLSCertificate(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(ConfigFilePathENV, filepath.Join(dir, ".ddns_go_config.yaml"))
+
+	cert, err := LoadTLSCertificate("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := LoadTLSCertificate("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(cert.Certificate[0], again.Certificate[0]) {
+		t.Error("应使用已生成的证书")
+	}
+
+	// 指定证书
+	certFile := filepath.Join(dir, selfSignedCertFileName)
+	keyFile := filepath.Join(dir, selfSignedKeyFileName)
+	if _, err := LoadTLSCertificate(certFile, keyFile); err != nil {
+		t.Error(err)
+	}
+	if _, err := LoadTLSCertificate(certFile, ""); err == nil {
+		t.Error("未指定私钥应返回错误")
+	}
+	if info, err := os.Stat(keyFile); runtime.GOOS != "windows" && (err != nil || info.Mode().Perm()&0077 != 0) {
+		t.Errorf("私钥权限不正确: %v %v", info, err)
+	}
+}
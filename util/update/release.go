@@ -43,7 +43,7 @@ func getLatest(repo string) (*Release, error) {
 	var result ReleaseResp
 	err = util.GetHTTPResponse(resp, err, &result)
 	if err != nil {
-		util.Log("异常信息: %s", err)
+		util.LogError("异常信息: %s", err)
 		return nil, err
 	}
 
@@ -24,17 +24,15 @@ func Auth(f ViewFunc) ViewFunc {
 
 		// 禁止公网访问
 		if conf.NotAllowWanAccess {
-			if !util.IsPrivateNetwork(r.RemoteAddr) {
+			if !util.IsPrivateNetwork(util.GetClientIP(r)) {
 				w.WriteHeader(http.StatusForbidden)
-				util.Log("%q 被禁止从公网访问", util.GetRequestIPStr(r))
+				util.LogWarn("%q 被禁止从公网访问", util.GetRequestIPStr(r))
 				return
 			}
 		}
 
 		// 验证token
-		if cookieInSystem.Value != "" &&
-			cookieInSystem.Value == cookieInWeb.Value &&
-			cookieInSystem.Expires.After(time.Now()) {
+		if isValidToken(cookieInWeb.Value) {
 			f(w, r) // 执行被装饰的函数
 			return
 		}
@@ -43,6 +41,13 @@ func Auth(f ViewFunc) ViewFunc {
 	}
 }
 
+// isValidToken 是否为登录后未过期的token
+func isValidToken(token string) bool {
+	return cookieInSystem.Value != "" &&
+		cookieInSystem.Value == token &&
+		cookieInSystem.Expires.After(time.Now())
+}
+
 // AuthAssert 保护静态等文件不被公网访问
 func AuthAssert(f ViewFunc) ViewFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -51,17 +56,17 @@ func AuthAssert(f ViewFunc) ViewFunc {
 
 		// 配置文件为空, 启动时间超过3小时禁止从公网访问
 		if err != nil &&
-			time.Now().Unix()-startTime > 3*60*60 && !util.IsPrivateNetwork(r.RemoteAddr) {
+			time.Now().Unix()-startTime > 3*60*60 && !util.IsPrivateNetwork(util.GetClientIP(r)) {
 			w.WriteHeader(http.StatusForbidden)
-			util.Log("%q 配置文件为空, 超过3小时禁止从公网访问", util.GetRequestIPStr(r))
+			util.LogError("%q 配置文件为空, 超过3小时禁止从公网访问", util.GetRequestIPStr(r))
 			return
 		}
 
 		// 禁止公网访问
 		if conf.NotAllowWanAccess {
-			if !util.IsPrivateNetwork(r.RemoteAddr) {
+			if !util.IsPrivateNetwork(util.GetClientIP(r)) {
 				w.WriteHeader(http.StatusForbidden)
-				util.Log("%q 被禁止从公网访问", util.GetRequestIPStr(r))
+				util.LogWarn("%q 被禁止从公网访问", util.GetRequestIPStr(r))
 				return
 			}
 		}
@@ -0,0 +1,39 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/dns"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// DnsTest 测试页面中填写的DNS服务商配置
+func DnsTest(writer http.ResponseWriter, request *http.Request) {
+	var data struct {
+		dnsConf4JS
+		// 第几个配置, 用于还原隐藏的ID、Secret
+		Index int `json:"Index"`
+	}
+	err := json.NewDecoder(request.Body).Decode(&data)
+	if err != nil {
+		returnError(writer, util.LogStr("数据解析失败, 请刷新页面重试"))
+		return
+	}
+
+	conf, _ := config.GetConfigCached()
+	var old *config.DnsConfig
+	if data.Index >= 0 && data.Index < len(conf.DnsConf) {
+		old = &conf.DnsConf[data.Index]
+	}
+	dnsConf := config.DnsConfig{HTTPTimeout: strings.TrimSpace(data.HTTPTimeout)}
+	dnsConf.DNS = getDNSFromJS(data.dnsConf4JS, old)
+
+	if err := dns.CheckDnsConfig(request.Context(), dnsConf); err != nil {
+		returnError(writer, err.Error())
+		return
+	}
+	returnOK(writer, util.LogStr("测试成功"), nil)
+}
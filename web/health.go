@@ -0,0 +1,18 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/jeessy2/ddns-go/v6/dns"
+)
+
+// Healthz 健康检查, 所有配置连续全部更新失败超过阈值时返回503
+func Healthz(writer http.ResponseWriter, request *http.Request) {
+	writer.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if !dns.Healthy() {
+		writer.WriteHeader(http.StatusServiceUnavailable)
+		writer.Write([]byte("unhealthy"))
+		return
+	}
+	writer.Write([]byte("ok"))
+}
@@ -94,6 +94,7 @@ func LoginFunc(w http.ResponseWriter, r *http.Request) {
 			Path:     "/",
 			Expires:  time.Now().AddDate(0, 0, timeoutDays), // 设置过期时间
 			HttpOnly: true,
+			Secure:   r.TLS != nil,
 		}
 		// 写入cookie
 		http.SetCookie(w, cookieInSystem)
@@ -104,7 +105,7 @@ func LoginFunc(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ld.failedTimes = ld.failedTimes + 1
-	util.Log("%q 帐号密码不正确", util.GetRequestIPStr(r))
+	util.LogWarn("%q 帐号密码不正确", util.GetRequestIPStr(r))
 	returnError(w, util.LogStr("用户名或密码错误"))
 }
 
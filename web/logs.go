@@ -2,28 +2,65 @@ package web
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"sync"
+	"time"
 )
 
+// 实时日志的心跳间隔, 防止代理断开空闲连接, 同时检查登录是否过期
+const logStreamHeartbeat = 30 * time.Second
+
 // MemoryLogs 内存中的日志
 type MemoryLogs struct {
 	MaxNum int      // 保存最大条数
 	Logs   []string // 日志
+	lock   sync.Mutex
+	// 实时日志的订阅者
+	subscribers map[chan string]struct{}
 }
 
 func (mlogs *MemoryLogs) Write(p []byte) (n int, err error) {
+	mlogs.lock.Lock()
+	defer mlogs.lock.Unlock()
 	mlogs.Logs = append(mlogs.Logs, string(p))
 	// 处理日志数量
 	if len(mlogs.Logs) > mlogs.MaxNum {
 		mlogs.Logs = mlogs.Logs[len(mlogs.Logs)-mlogs.MaxNum:]
 	}
+	// 订阅者处理不及时则丢弃, 不阻塞日志输出
+	for ch := range mlogs.subscribers {
+		select {
+		case ch <- string(p):
+		default:
+		}
+	}
 	return len(p), nil
 }
 
-var mlogs = &MemoryLogs{MaxNum: 50}
+// subscribe 订阅之后的日志, 返回当前的全部日志
+func (mlogs *MemoryLogs) subscribe() (chan string, []string) {
+	mlogs.lock.Lock()
+	defer mlogs.lock.Unlock()
+	if mlogs.subscribers == nil {
+		mlogs.subscribers = map[chan string]struct{}{}
+	}
+	ch := make(chan string, mlogs.MaxNum)
+	mlogs.subscribers[ch] = struct{}{}
+	return ch, append([]string{}, mlogs.Logs...)
+}
+
+// unsubscribe 取消订阅
+func (mlogs *MemoryLogs) unsubscribe(ch chan string) {
+	mlogs.lock.Lock()
+	defer mlogs.lock.Unlock()
+	delete(mlogs.subscribers, ch)
+}
+
+var mlogs = &MemoryLogs{MaxNum: 300}
 
 // 初始化日志
 func init() {
@@ -34,11 +71,60 @@ func init() {
 // Logs web
 func Logs(writer http.ResponseWriter, request *http.Request) {
 	// mlogs.Logs数组转为json
+	mlogs.lock.Lock()
 	logs, _ := json.Marshal(mlogs.Logs)
+	mlogs.lock.Unlock()
 	writer.Write(logs)
 }
 
+// LogsStream 通过 Server-Sent Events 实时推送日志
+// 连接后先发送 init 事件, 内容为当前的全部日志, 之后每条日志为一个 message 事件
+func LogsStream(writer http.ResponseWriter, request *http.Request) {
+	flusher, ok := writer.(http.Flusher)
+	if !ok {
+		http.Error(writer, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	var token string
+	if cookie, err := request.Cookie(cookieName); err == nil {
+		token = cookie.Value
+	}
+
+	ch, logs := mlogs.subscribe()
+	defer mlogs.unsubscribe(ch)
+
+	writer.Header().Set("Content-Type", "text/event-stream")
+	writer.Header().Set("Cache-Control", "no-cache")
+	// 禁止 Nginx 缓冲
+	writer.Header().Set("X-Accel-Buffering", "no")
+	initLogs, _ := json.Marshal(logs)
+	fmt.Fprintf(writer, "event: init\ndata: %s\n\n", initLogs)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(logStreamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-request.Context().Done():
+			return
+		case <-heartbeat.C:
+			// 登录过期后断开
+			if !isValidToken(token) {
+				return
+			}
+			fmt.Fprint(writer, ": heartbeat\n\n")
+		case line := <-ch:
+			// 日志包含换行, 使用json编码
+			data, _ := json.Marshal(line)
+			fmt.Fprintf(writer, "data: %s\n\n", data)
+		}
+		flusher.Flush()
+	}
+}
+
 // ClearLog
 func ClearLog(writer http.ResponseWriter, request *http.Request) {
+	mlogs.lock.Lock()
 	mlogs.Logs = mlogs.Logs[:0]
+	mlogs.lock.Unlock()
 }
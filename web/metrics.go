@@ -0,0 +1,13 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// Metrics Prometheus 指标
+func Metrics(writer http.ResponseWriter, request *http.Request) {
+	writer.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	util.WriteMetrics(writer)
+}
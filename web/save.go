@@ -38,6 +38,30 @@ func checkAndSave(request *http.Request) string {
 		WebhookURL         string       `json:"WebhookURL"`
 		WebhookRequestBody string       `json:"WebhookRequestBody"`
 		WebhookHeaders     string       `json:"WebhookHeaders"`
+		WebhookMethod      string       `json:"WebhookMethod"`
+		WebhookTrigger     string       `json:"WebhookTrigger"`
+		TelegramBotToken   string       `json:"TelegramBotToken"`
+		TelegramChatID     string       `json:"TelegramChatID"`
+		TelegramTrigger    string       `json:"TelegramTrigger"`
+		BarkURL            string       `json:"BarkURL"`
+		BarkSound          string       `json:"BarkSound"`
+		BarkGroup          string       `json:"BarkGroup"`
+		BarkTrigger        string       `json:"BarkTrigger"`
+		DiscordURL         string       `json:"DiscordURL"`
+		DiscordTrigger     string       `json:"DiscordTrigger"`
+		SlackURL           string       `json:"SlackURL"`
+		SlackChannel       string       `json:"SlackChannel"`
+		SlackTrigger       string       `json:"SlackTrigger"`
+		EmailHost          string       `json:"EmailHost"`
+		EmailPort          string       `json:"EmailPort"`
+		EmailUsername      string       `json:"EmailUsername"`
+		EmailPassword      string       `json:"EmailPassword"`
+		EmailFrom          string       `json:"EmailFrom"`
+		EmailTo            string       `json:"EmailTo"`
+		EmailTLS           bool         `json:"EmailTLS"`
+		EmailTrigger       string       `json:"EmailTrigger"`
+		HookCommand        string       `json:"HookCommand"`
+		NotifyCooldown     string       `json:"NotifyCooldown"`
 		DnsConf            []dnsConf4JS `json:"DnsConf"`
 	}
 
@@ -69,6 +93,40 @@ func checkAndSave(request *http.Request) string {
 	conf.WebhookURL = strings.TrimSpace(data.WebhookURL)
 	conf.WebhookRequestBody = strings.TrimSpace(data.WebhookRequestBody)
 	conf.WebhookHeaders = strings.TrimSpace(data.WebhookHeaders)
+	conf.WebhookMethod = data.WebhookMethod
+	conf.WebhookTrigger = data.WebhookTrigger
+	if token := strings.TrimSpace(data.TelegramBotToken); token != hideValue(conf.TelegramBotToken) {
+		conf.TelegramBotToken = token
+	}
+	conf.TelegramChatID = strings.TrimSpace(data.TelegramChatID)
+	conf.TelegramTrigger = data.TelegramTrigger
+	if barkURL := strings.TrimSpace(data.BarkURL); barkURL != hideValue(conf.BarkURL) {
+		conf.BarkURL = barkURL
+	}
+	conf.BarkSound = strings.TrimSpace(data.BarkSound)
+	conf.BarkGroup = strings.TrimSpace(data.BarkGroup)
+	conf.BarkTrigger = data.BarkTrigger
+	if discordURL := strings.TrimSpace(data.DiscordURL); discordURL != hideValue(conf.DiscordURL) {
+		conf.DiscordURL = discordURL
+	}
+	conf.DiscordTrigger = data.DiscordTrigger
+	if slackURL := strings.TrimSpace(data.SlackURL); slackURL != hideValue(conf.SlackURL) {
+		conf.SlackURL = slackURL
+	}
+	conf.SlackChannel = strings.TrimSpace(data.SlackChannel)
+	conf.SlackTrigger = data.SlackTrigger
+	conf.EmailHost = strings.TrimSpace(data.EmailHost)
+	conf.EmailPort = strings.TrimSpace(data.EmailPort)
+	conf.EmailUsername = strings.TrimSpace(data.EmailUsername)
+	if data.EmailPassword != hideValue(conf.EmailPassword) {
+		conf.EmailPassword = data.EmailPassword
+	}
+	conf.EmailFrom = strings.TrimSpace(data.EmailFrom)
+	conf.EmailTo = strings.TrimSpace(data.EmailTo)
+	conf.EmailTLS = data.EmailTLS
+	conf.EmailTrigger = data.EmailTrigger
+	conf.HookCommand = strings.TrimSpace(data.HookCommand)
+	conf.NotifyCooldown = strings.TrimSpace(data.NotifyCooldown)
 
 	// 如果新密码不为空则检查是否够强, 内/外网要求强度不同
 	conf.Username = usernameNew
@@ -92,40 +150,62 @@ func checkAndSave(request *http.Request) string {
 		if v == empty {
 			continue
 		}
-		dnsConf := config.DnsConfig{Name: v.Name, TTL: v.TTL}
+		dnsConf := config.DnsConfig{Name: v.Name, TTL: v.TTL, HTTPTimeout: strings.TrimSpace(v.HTTPTimeout), Interval: strings.TrimSpace(v.Interval), DohURL: strings.TrimSpace(v.DohURL), Proxied: v.Proxied}
 		// 覆盖以前的配置
-		dnsConf.DNS.Name = v.DnsName
-		dnsConf.DNS.ID = strings.TrimSpace(v.DnsID)
-		dnsConf.DNS.Secret = strings.TrimSpace(v.DnsSecret)
+		var old *config.DnsConfig
+		if k < len(conf.DnsConf) {
+			old = &conf.DnsConf[k]
+		}
+		dnsConf.DNS = getDNSFromJS(v, old)
+		dnsConf.Comment = strings.TrimSpace(v.Comment)
+		dnsConf.Tags = strings.TrimSpace(v.Tags)
+		dnsConf.CleanDuplicates = v.CleanDuplicates
+		dnsConf.UpdateOnly = v.UpdateOnly
+		dnsConf.DeleteOnNoIP = v.DeleteOnNoIP
+		dnsConf.Enabled = v.Enabled
 
-		if v.Ipv4Domains == "" && v.Ipv6Domains == "" {
-			util.Log("第 %s 个配置未填写域名", util.Ordinal(k+1, conf.Lang))
+		if v.Ipv4Domains == "" && v.Ipv6Domains == "" && v.CnameDomains == "" && v.MxDomains == "" {
+			util.LogWarn("第 %s 个配置未填写域名", util.Ordinal(k+1, conf.Lang))
 		}
 
 		dnsConf.Ipv4.Enable = v.Ipv4Enable
 		dnsConf.Ipv4.GetType = v.Ipv4GetType
 		dnsConf.Ipv4.URL = strings.TrimSpace(v.Ipv4Url)
+		dnsConf.Ipv4.URLReg = strings.TrimSpace(v.Ipv4UrlReg)
+		dnsConf.Ipv4.URLAuth.Headers = parseHeaders(v.Ipv4UrlHeaders)
+		dnsConf.Ipv4.URLAuth.Username = strings.TrimSpace(v.Ipv4UrlUsername)
+		dnsConf.Ipv4.URLAuth.Password = v.Ipv4UrlPassword
 		dnsConf.Ipv4.NetInterface = v.Ipv4NetInterface
 		dnsConf.Ipv4.Cmd = strings.TrimSpace(v.Ipv4Cmd)
+		dnsConf.Ipv4.AllowPrivate = v.Ipv4AllowPrivate
 		dnsConf.Ipv4.Domains = util.SplitLines(v.Ipv4Domains)
 
 		dnsConf.Ipv6.Enable = v.Ipv6Enable
 		dnsConf.Ipv6.GetType = v.Ipv6GetType
 		dnsConf.Ipv6.URL = strings.TrimSpace(v.Ipv6Url)
+		dnsConf.Ipv6.URLReg = strings.TrimSpace(v.Ipv6UrlReg)
+		dnsConf.Ipv6.URLAuth.Headers = parseHeaders(v.Ipv6UrlHeaders)
+		dnsConf.Ipv6.URLAuth.Username = strings.TrimSpace(v.Ipv6UrlUsername)
+		dnsConf.Ipv6.URLAuth.Password = v.Ipv6UrlPassword
 		dnsConf.Ipv6.NetInterface = v.Ipv6NetInterface
 		dnsConf.Ipv6.Cmd = strings.TrimSpace(v.Ipv6Cmd)
 		dnsConf.Ipv6.Ipv6Reg = strings.TrimSpace(v.Ipv6Reg)
+		dnsConf.Ipv6.Suffix = strings.TrimSpace(v.Ipv6Suffix)
+		dnsConf.Ipv6.PreferTemporary = v.Ipv6PreferTemp
 		dnsConf.Ipv6.Domains = util.SplitLines(v.Ipv6Domains)
 
-		if k < len(conf.DnsConf) {
-			c := &conf.DnsConf[k]
-			idHide, secretHide := getHideIDSecret(c)
-			if dnsConf.DNS.ID == idHide {
-				dnsConf.DNS.ID = c.DNS.ID
+		dnsConf.Cname.Domains = util.SplitLines(v.CnameDomains)
+		dnsConf.Mx.Domains = util.SplitLines(v.MxDomains)
+
+		if old != nil {
+			if dnsConf.Ipv4.URLAuth.Password == hideValue(old.Ipv4.URLAuth.Password) {
+				dnsConf.Ipv4.URLAuth.Password = old.Ipv4.URLAuth.Password
 			}
-			if dnsConf.DNS.Secret == secretHide {
-				dnsConf.DNS.Secret = c.DNS.Secret
+			if dnsConf.Ipv6.URLAuth.Password == hideValue(old.Ipv6.URLAuth.Password) {
+				dnsConf.Ipv6.URLAuth.Password = old.Ipv6.URLAuth.Password
 			}
+			restoreHeaders(dnsConf.Ipv4.URLAuth.Headers, old.Ipv4.URLAuth.Headers)
+			restoreHeaders(dnsConf.Ipv6.URLAuth.Headers, old.Ipv6.URLAuth.Headers)
 		}
 
 		dnsConfArray = append(dnsConfArray, dnsConf)
@@ -145,3 +225,62 @@ func checkAndSave(request *http.Request) string {
 	}
 	return "ok"
 }
+
+// getDNSFromJS 获得页面中的DNS服务商配置, 未修改隐藏的ID、Secret时使用以前的配置
+func getDNSFromJS(v dnsConf4JS, old *config.DnsConfig) config.DNS {
+	dns := config.DNS{
+		Name:        v.DnsName,
+		ID:          strings.TrimSpace(v.DnsID),
+		Secret:      strings.TrimSpace(v.DnsSecret),
+		ZoneID:      strings.TrimSpace(v.DnsZoneID),
+		Endpoint:    strings.TrimSpace(v.DnsEndpoint),
+		TenantID:    strings.TrimSpace(v.DnsTenantID),
+		ConsumerKey: strings.TrimSpace(v.DnsConsumerKey),
+		AuthMode:    v.DnsAuthMode,
+	}
+	if old == nil {
+		return dns
+	}
+
+	idHide, secretHide := getHideIDSecret(old)
+	if dns.ID == idHide {
+		dns.ID = old.DNS.ID
+	}
+	if dns.Secret == secretHide {
+		dns.Secret = old.DNS.Secret
+	}
+	if dns.ConsumerKey == hideValue(old.DNS.ConsumerKey) {
+		dns.ConsumerKey = old.DNS.ConsumerKey
+	}
+	return dns
+}
+
+// restoreHeaders 未修改隐藏的请求头的值时使用以前的值
+func restoreHeaders(headers, old map[string]string) {
+	for key, value := range headers {
+		if oldValue, ok := old[key]; ok && value == hideValue(oldValue) {
+			headers[key] = oldValue
+		}
+	}
+}
+
+// parseHeaders 解析每行一个 Key: Value 的请求头
+func parseHeaders(s string) map[string]string {
+	var headers map[string]string
+	for _, line := range util.SplitLines(s) {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		key, value, found := strings.Cut(line, ":")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			util.LogWarn("请求头不正确: %s", line)
+			continue
+		}
+		if headers == nil {
+			headers = map[string]string{}
+		}
+		headers[key] = strings.TrimSpace(value)
+	}
+	return headers
+}
@@ -0,0 +1,23 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/jeessy2/ddns-go/v6/dns"
+)
+
+// 返回的IP变化记录数
+const statusHistoryCount = 20
+
+// Status 所有域名的当前状态及最近的IP变化记录
+func Status(writer http.ResponseWriter, request *http.Request) {
+	writer.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(writer).Encode(struct {
+		Domains []dns.DomainStatus `json:"domains"`
+		History []dns.HistoryEntry `json:"history"`
+	}{
+		Domains: dns.GetStatus(),
+		History: dns.GetHistory(statusHistoryCount),
+	})
+}
@@ -0,0 +1,22 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/jeessy2/ddns-go/v6/dns"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// UpdateNow 立即更新全部配置并返回更新结果, 不与正在运行的更新同时进行
+func UpdateNow(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		writer.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	result, ok := dns.RunNow()
+	if !ok {
+		returnError(writer, util.LogStr("正在更新中, 请稍后再试"))
+		return
+	}
+	returnOK(writer, util.LogStr("更新完成, 成功: %d, 失败: %d, 未改变: %d", result.Success, result.Failed, result.Nothing), result)
+}
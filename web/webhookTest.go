@@ -13,10 +13,11 @@ func WebhookTest(writer http.ResponseWriter, request *http.Request) {
 		URL         string `json:"URL"`
 		RequestBody string `json:"RequestBody"`
 		Headers     string `json:"Headers"`
+		Method      string `json:"Method"`
 	}
 	err := json.NewDecoder(request.Body).Decode(&data)
 	if err != nil {
-		util.Log("数据解析失败, 请刷新页面重试")
+		util.LogError("数据解析失败, 请刷新页面重试")
 		return
 	}
 
@@ -25,30 +26,21 @@ func WebhookTest(writer http.ResponseWriter, request *http.Request) {
 	headers := data.Headers
 
 	if url == "" {
-		util.Log("请输入Webhook的URL")
+		util.LogWarn("请输入Webhook的URL")
 		return
 	}
 
-	var domains = make([]*config.Domain, 1)
-	domains[0] = &config.Domain{}
-	domains[0].DomainName = "example.com"
-	domains[0].SubDomain = "test"
-	domains[0].UpdateStatus = config.UpdatedSuccess
-
-	fakeDomains := &config.Domains{
-		Ipv4Addr:    "127.0.0.1",
-		Ipv4Domains: domains,
-		Ipv6Addr:    "::1",
-		Ipv6Domains: domains,
+	results := []config.DomainResult{
+		{Domain: "test.example.com", RecordType: "A", OldIP: "127.0.0.2", NewIP: "127.0.0.1", Status: "success"},
+		{Domain: "test.example.com", RecordType: "AAAA", OldIP: "::2", NewIP: "::1", Status: "success"},
 	}
 
-	fakeConfig := &config.Config{
-		Webhook: config.Webhook{
-			WebhookURL:         url,
-			WebhookRequestBody: requestBody,
-			WebhookHeaders:     headers,
-		},
+	webhook := config.Webhook{
+		WebhookURL:         url,
+		WebhookRequestBody: requestBody,
+		WebhookHeaders:     headers,
+		WebhookMethod:      data.Method,
 	}
 
-	config.ExecWebhook(fakeDomains, fakeConfig)
+	config.SendNotify("Webhook", config.NewWebhookNotifier(webhook), results)
 }
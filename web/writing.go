@@ -7,6 +7,7 @@ import (
 	"html/template"
 	"net/http"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/jeessy2/ddns-go/v6/config"
@@ -23,19 +24,47 @@ type dnsConf4JS struct {
 	DnsName          string
 	DnsID            string
 	DnsSecret        string
+	DnsZoneID        string
+	DnsEndpoint      string
+	DnsTenantID      string
+	DnsConsumerKey   string
+	DnsAuthMode      string
 	TTL              string
+	HTTPTimeout      string
+	Interval         string
+	DohURL           string
+	Proxied          bool
+	Comment          string
+	Tags             string
+	CleanDuplicates  bool
+	UpdateOnly       bool
+	DeleteOnNoIP     bool
+	Enabled          bool
 	Ipv4Enable       bool
 	Ipv4GetType      string
 	Ipv4Url          string
+	Ipv4UrlReg       string
+	Ipv4UrlHeaders   string
+	Ipv4UrlUsername  string
+	Ipv4UrlPassword  string
 	Ipv4NetInterface string
 	Ipv4Cmd          string
+	Ipv4AllowPrivate bool
 	Ipv4Domains      string
 	Ipv6Enable       bool
 	Ipv6GetType      string
 	Ipv6Url          string
+	Ipv6UrlReg       string
+	Ipv6UrlHeaders   string
+	Ipv6UrlUsername  string
+	Ipv6UrlPassword  string
 	Ipv6NetInterface string
 	Ipv6Cmd          string
 	Ipv6Reg          string
+	Ipv6Suffix       string
+	Ipv6PreferTemp   bool
+	CnameDomains     string
+	MxDomains        string
 	Ipv6Domains      string
 }
 
@@ -59,19 +88,49 @@ func Writing(writer http.ResponseWriter, request *http.Request) {
 	err = tmpl.Execute(writer, struct {
 		DnsConf           template.JS
 		NotAllowWanAccess bool
+		NotifyCooldown    string
 		Username          string
 		config.Webhook
+		config.Telegram
+		config.Bark
+		config.Discord
+		config.Slack
+		config.Email
+		config.Hook
 		Version string
 		Ipv4    []config.NetInterface
 		Ipv6    []config.NetInterface
 	}{
 		DnsConf:           template.JS(getDnsConfStr(conf.DnsConf)),
 		NotAllowWanAccess: conf.NotAllowWanAccess,
+		NotifyCooldown:    conf.NotifyCooldown,
 		Username:          conf.User.Username,
 		Webhook:           conf.Webhook,
-		Version:           os.Getenv(VersionEnv),
-		Ipv4:              ipv4,
-		Ipv6:              ipv6,
+		Telegram: config.Telegram{
+			TelegramBotToken: hideValue(conf.TelegramBotToken),
+			TelegramChatID:   conf.TelegramChatID,
+			TelegramTrigger:  conf.TelegramTrigger,
+		},
+		Bark: config.Bark{
+			BarkURL:     hideValue(conf.BarkURL),
+			BarkSound:   conf.BarkSound,
+			BarkGroup:   conf.BarkGroup,
+			BarkTrigger: conf.BarkTrigger,
+		},
+		Discord: config.Discord{
+			DiscordURL:     hideValue(conf.DiscordURL),
+			DiscordTrigger: conf.DiscordTrigger,
+		},
+		Slack: config.Slack{
+			SlackURL:     hideValue(conf.SlackURL),
+			SlackChannel: conf.SlackChannel,
+			SlackTrigger: conf.SlackTrigger,
+		},
+		Email:   getHideEmail(conf.Email),
+		Hook:    conf.Hook,
+		Version: os.Getenv(VersionEnv),
+		Ipv4:    ipv4,
+		Ipv6:    ipv6,
 	})
 	if err != nil {
 		fmt.Println("Error happened..")
@@ -89,19 +148,47 @@ func getDnsConfStr(dnsConf []config.DnsConfig) string {
 			DnsName:          conf.DNS.Name,
 			DnsID:            idHide,
 			DnsSecret:        secretHide,
+			DnsZoneID:        conf.DNS.ZoneID,
+			DnsEndpoint:      conf.DNS.Endpoint,
+			DnsTenantID:      conf.DNS.TenantID,
+			DnsConsumerKey:   hideValue(conf.DNS.ConsumerKey),
+			DnsAuthMode:      conf.DNS.AuthMode,
 			TTL:              conf.TTL,
+			HTTPTimeout:      conf.HTTPTimeout,
+			Interval:         conf.Interval,
+			DohURL:           conf.DohURL,
+			Proxied:          conf.Proxied,
+			Comment:          conf.Comment,
+			Tags:             conf.Tags,
+			CleanDuplicates:  conf.CleanDuplicates,
+			UpdateOnly:       conf.UpdateOnly,
+			DeleteOnNoIP:     conf.DeleteOnNoIP,
+			Enabled:          conf.Enabled,
 			Ipv4Enable:       conf.Ipv4.Enable,
 			Ipv4GetType:      conf.Ipv4.GetType,
 			Ipv4Url:          conf.Ipv4.URL,
+			Ipv4UrlReg:       conf.Ipv4.URLReg,
+			Ipv4UrlHeaders:   getHeadersStr(hideHeaders(conf.Ipv4.URLAuth.Headers)),
+			Ipv4UrlUsername:  conf.Ipv4.URLAuth.Username,
+			Ipv4UrlPassword:  hideValue(conf.Ipv4.URLAuth.Password),
 			Ipv4NetInterface: conf.Ipv4.NetInterface,
 			Ipv4Cmd:          conf.Ipv4.Cmd,
+			Ipv4AllowPrivate: conf.Ipv4.AllowPrivate,
 			Ipv4Domains:      strings.Join(conf.Ipv4.Domains, "\r\n"),
 			Ipv6Enable:       conf.Ipv6.Enable,
 			Ipv6GetType:      conf.Ipv6.GetType,
 			Ipv6Url:          conf.Ipv6.URL,
+			Ipv6UrlReg:       conf.Ipv6.URLReg,
+			Ipv6UrlHeaders:   getHeadersStr(hideHeaders(conf.Ipv6.URLAuth.Headers)),
+			Ipv6UrlUsername:  conf.Ipv6.URLAuth.Username,
+			Ipv6UrlPassword:  hideValue(conf.Ipv6.URLAuth.Password),
 			Ipv6NetInterface: conf.Ipv6.NetInterface,
 			Ipv6Cmd:          conf.Ipv6.Cmd,
 			Ipv6Reg:          conf.Ipv6.Ipv6Reg,
+			Ipv6Suffix:       conf.Ipv6.Suffix,
+			Ipv6PreferTemp:   conf.Ipv6.PreferTemporary,
+			CnameDomains:     strings.Join(conf.Cname.Domains, "\r\n"),
+			MxDomains:        strings.Join(conf.Mx.Domains, "\r\n"),
 			Ipv6Domains:      strings.Join(conf.Ipv6.Domains, "\r\n"),
 		})
 	}
@@ -110,19 +197,51 @@ func getDnsConfStr(dnsConf []config.DnsConfig) string {
 }
 
 // 显示的数量
-const displayCount int = 3
+const displayCount int = 4
+
+// 隐藏部分的占位符, 固定长度不暴露真实长度
+const hiddenPlaceholder = "********"
 
 // hideIDSecret 隐藏真实的ID、Secret
 func getHideIDSecret(conf *config.DnsConfig) (idHide string, secretHide string) {
-	if len(conf.DNS.ID) > displayCount && conf.DNS.Name != "callback" {
-		idHide = conf.DNS.ID[:displayCount] + strings.Repeat("*", len(conf.DNS.ID)-displayCount)
-	} else {
-		idHide = conf.DNS.ID
+	if conf.DNS.Name == "callback" {
+		return conf.DNS.ID, conf.DNS.Secret
+	}
+	return hideValue(conf.DNS.ID), hideValue(conf.DNS.Secret)
+}
+
+// getHideEmail 隐藏邮箱密码
+func getHideEmail(email config.Email) config.Email {
+	email.EmailPassword = hideValue(email.EmailPassword)
+	return email
+}
+
+// hideHeaders 隐藏请求头的值, 值中通常包含令牌等认证信息
+func hideHeaders(headers map[string]string) map[string]string {
+	hidden := make(map[string]string, len(headers))
+	for key, value := range headers {
+		hidden[key] = hideValue(value)
+	}
+	return hidden
+}
+
+// getHeadersStr 请求头转为每行一个 Key: Value
+func getHeadersStr(headers map[string]string) string {
+	lines := make([]string, 0, len(headers))
+	for key, value := range headers {
+		lines = append(lines, key+": "+value)
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\r\n")
+}
+
+// hideValue 只显示后几位, 前面用占位符代替, 长度不超过显示数量的两倍时全部用占位符代替
+func hideValue(value string) string {
+	if value == "" {
+		return ""
 	}
-	if len(conf.DNS.Secret) > displayCount && conf.DNS.Name != "callback" {
-		secretHide = conf.DNS.Secret[:displayCount] + strings.Repeat("*", len(conf.DNS.Secret)-displayCount)
-	} else {
-		secretHide = conf.DNS.Secret
+	if len(value) > displayCount*2 {
+		return hiddenPlaceholder + value[len(value)-displayCount:]
 	}
-	return
+	return hiddenPlaceholder
 }
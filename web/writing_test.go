@@ -0,0 +1,38 @@
+package web
+
+import (
+	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+)
+
+// TestHideValue 测试只显示后4位, 较短的值全部隐藏
+func TestHideValue(t *testing.T) {
+	tests := map[string]string{
+		"":                    "",
+		"abc":                 "********",
+		"abcdefgh":            "********",
+		"abcdefghi":           "********fghi",
+		"cf-token-1234567890": "********7890",
+	}
+	for value, want := range tests {
+		if got := hideValue(value); got != want {
+			t.Errorf("%q 期待 %q, 得到 %q", value, want, got)
+		}
+	}
+}
+
+// TestGetDNSFromJSKeepsHidden 测试提交的值与隐藏后的值相同时保留以前的Secret
+func TestGetDNSFromJSKeepsHidden(t *testing.T) {
+	old := &config.DnsConfig{DNS: config.DNS{Name: "cloudflare", Secret: "cf-token-1234567890"}}
+
+	dns := getDNSFromJS(dnsConf4JS{DnsName: "cloudflare", DnsSecret: hideValue(old.DNS.Secret)}, old)
+	if dns.Secret != old.DNS.Secret {
+		t.Errorf("期待保留以前的Secret, 得到 %q", dns.Secret)
+	}
+
+	dns = getDNSFromJS(dnsConf4JS{DnsName: "cloudflare", DnsSecret: "new-token"}, old)
+	if dns.Secret != "new-token" {
+		t.Errorf("期待新的Secret, 得到 %q", dns.Secret)
+	}
+}